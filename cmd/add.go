@@ -3,30 +3,95 @@ package cmd
 import (
 	"fmt"
 	"github.com/spf13/cobra"
-	"github.com/zen-flo/todo-cli/internal/storage"
 	"github.com/zen-flo/todo-cli/internal/task"
 	"time"
 )
 
+// priorityFromFlags возвращает приоритет из флагов --priority и --important
+// (синоним --priority=high) и признак того, что хоть один из них указан.
+// Если указаны оба, действует --priority.
+func priorityFromFlags(cmd *cobra.Command) (task.Priority, bool, error) {
+	if cmd.Flags().Changed("priority") {
+		value, _ := cmd.Flags().GetString("priority")
+		p, err := task.ParsePriority(value)
+		return p, true, err
+	}
+	if cmd.Flags().Changed("important") {
+		if important, _ := cmd.Flags().GetBool("important"); important {
+			return task.PriorityHigh, true, nil
+		}
+		return task.PriorityNormal, true, nil
+	}
+	return task.PriorityNormal, false, nil
+}
+
+// completePriorities — автодополнение для флагов с приоритетом.
+func completePriorities(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return task.PriorityNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
 // addCmd — подкоманда "add", которая создаёт новую задачу.
 // Пример использования:
 //
 //	todo add "Купить хлеб"
+//	todo add "Сдать отчёт" --due=fri
+//	todo add "Починить прод" --priority=urgent
+//	todo add "Обновить сертификаты" --tag=infra --tag=oncall
+//	todo add "Собрать релиз" --project=work.release
+//	todo add "Написать changelog" --parent=3   — подзадача задачи 3
 var addCmd = &cobra.Command{
 	Use:   "add [task title]",      // формат вызова
 	Short: "Добавить новую задачу", // краткое описание
 	Args:  cobra.ExactArgs(1),      // ожидаем ровно один аргумент — название задачи
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
-			fmt.Println("Ошибка: нужно указать заголовок задачи.")
+			printError("Ошибка: нужно указать заголовок задачи.")
+			return
+		}
+		// Разбираем срок выполнения, если он указан
+		var due time.Time
+		if value, _ := cmd.Flags().GetString("due"); value != "" {
+			parsed, err := task.ParseDate(value, time.Now())
+			if err != nil {
+				printError("Ошибка:", err)
+				return
+			}
+			due = parsed
+		}
+
+		// Считываем приоритет (--important — синоним --priority=high)
+		priority, _, err := priorityFromFlags(cmd)
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		// Разбираем теги
+		tagValues, _ := cmd.Flags().GetStringArray("tag")
+		tags, excluded, err := parseTagArgs(tagValues)
+		if err == nil && len(excluded) > 0 {
+			err = fmt.Errorf("тег %q нельзя исключить у новой задачи", excluded[0])
+		}
+		if err != nil {
+			printError("Ошибка:", err)
 			return
 		}
-		// Создаём новое хранилище задач.
-		// Указываем путь к файлу.
-		store := storage.NewJSONStore(tasksFile)
 
-		// Считываем флаг, что задача важная
-		important, _ := cmd.Flags().GetBool("important")
+		// Проверяем имя проекта
+		projectValue, _ := cmd.Flags().GetString("project")
+		project, err := task.NormalizeProject(projectValue)
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		// Открываем хранилище задач, выбранное флагом --backend.
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
 
 		// Формируем новую задачу.
 		// ID сейчас фиксированный (1) — это временное решение,
@@ -34,20 +99,42 @@ var addCmd = &cobra.Command{
 		newTask := task.Task{
 			// ID присваивается автоматически внутри AddTask
 			Title:     args[0],
-			Completed: false,
-			Important: important,
+			Priority:  priority,
 			CreatedAt: time.Now(),
+			DueAt:     due,
+			Project:   project,
+		}
+		for _, tag := range tags {
+			newTask.AddTag(tag)
+		}
+
+		// Подзадача: родитель должен существовать, проект наследуется от него
+		if parentID, _ := cmd.Flags().GetInt("parent"); parentID != 0 {
+			parent, err := store.GetTask(parentID)
+			if err != nil {
+				printError("Ошибка:", err)
+				return
+			}
+			newTask.ParentID = parent.ID
+			if !cmd.Flags().Changed("project") {
+				newTask.Project = parent.Project
+			}
 		}
 
 		// Пытаемся добавить задачу в хранилище.
-		err := store.AddTask(newTask)
+		// Хранилище возвращает задачу с назначенным ID.
+		created, err := store.AddTask(newTask)
 		if err != nil {
-			fmt.Println("Ошибка при добавлении задачи:", err)
+			printError("Ошибка при добавлении задачи:", err)
 			return
 		}
 
 		// Если всё ок — выводим сообщение пользователю.
-		fmt.Println("Добавлена задача:", newTask.Title)
+		fmt.Fprintf(textOut(), "Добавлена задача [%d]: %s", created.ID, created.Title)
+		if !created.DueAt.IsZero() {
+			fmt.Fprintf(textOut(), " (срок: %s)", formatDue(created))
+		}
+		fmt.Fprintln(textOut())
 	},
 }
 
@@ -56,8 +143,25 @@ var addCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(addCmd)
 
-	// Флаг важности
-	addCmd.Flags().BoolP("important", "i", false, "Отметить задачу как важную")
+	// Флаги приоритета
+	addCmd.Flags().StringP("priority", "p", "normal", "Приоритет: low, normal, high или urgent")
+	addCmd.Flags().BoolP("important", "i", false, "Отметить задачу как важную (то же, что --priority=high)")
+	_ = addCmd.RegisterFlagCompletionFunc("priority", completePriorities)
+
+	// Флаг срока выполнения
+	addCmd.Flags().StringP("due", "d", "", "Срок выполнения: 2025-03-14, tomorrow, +3d, fri")
+
+	// Флаг тегов (можно указывать несколько раз)
+	addCmd.Flags().StringArrayP("tag", "t", nil, "Тег задачи (можно повторять)")
+	_ = addCmd.RegisterFlagCompletionFunc("tag", completeTags)
+
+	// Флаг проекта
+	addCmd.Flags().String("project", "", "Проект задачи, например work.release")
+	_ = addCmd.RegisterFlagCompletionFunc("project", completeProjects)
+
+	// Флаг родительской задачи
+	addCmd.Flags().Int("parent", 0, "ID родительской задачи (новая задача станет её подзадачей)")
+	_ = addCmd.RegisterFlagCompletionFunc("parent", completeTaskIDs)
 
 	// Для bool-флага автодополнение пустое, чтобы не ломать shell completion
 	_ = addCmd.RegisterFlagCompletionFunc("important", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// archivedTasks возвращает задачи из архива завершённых задач хранилища.
+// У хранилища без архива он пуст.
+func archivedTasks(store storage.Storage) ([]task.Task, error) {
+	js, ok := store.(*storage.JournaledStore)
+	if !ok || js.Archive == nil {
+		return nil, nil
+	}
+	entries, err := js.Archive.List()
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]task.Task, 0, len(entries))
+	for _, e := range entries {
+		tasks = append(tasks, e.Task)
+	}
+	return tasks, nil
+}
+
+// archivable возвращает ID завершённых задач, которые можно перенести
+// в архив: завершённых раньше before (любых, если before нулевое)
+// и без подзадач, остающихся в списке.
+func archivable(tasks []task.Task, before time.Time) []int {
+	candidate := make(map[int]bool)
+	for _, t := range tasks {
+		if t.IsClosed() && (before.IsZero() || t.ClosedAt().Before(before)) {
+			candidate[t.ID] = true
+		}
+	}
+
+	var ids []int
+	for _, t := range tasks {
+		if !candidate[t.ID] {
+			continue
+		}
+		keep := true
+		for _, child := range task.Descendants(tasks, t.ID) {
+			if !candidate[child] {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			ids = append(ids, t.ID)
+		}
+	}
+	return ids
+}
+
+// archiveCmd — подкоманда "archive", которая переносит завершённые задачи
+// из файла задач в архив по месяцам завершения (tasks.json.archive/2025-03.json).
+// В отличие от clear, задачи не удаляются: их можно посмотреть через
+// "todo list --archived" и найти через "todo search --include-archive".
+// Задача с незавершёнными подзадачами остаётся в списке.
+// Пример использования:
+//
+//	todo archive
+//	todo archive --older-than=14d
+var archiveCmd = &cobra.Command{
+	Use:   "archive",                              // формат вызова
+	Short: "Перенести завершённые задачи в архив", // краткое описание
+	Args:  cobra.NoArgs,                           // аргументы не нужны
+	Run: func(cmd *cobra.Command, args []string) {
+		var before time.Time
+		if value, _ := cmd.Flags().GetString("older-than"); value != "" {
+			t, ok := parseAgo(value, time.Now())
+			if !ok {
+				printErrorf("Ошибка в --older-than: не удалось разобрать %q, используйте 14d, 2w или 12h\n", value)
+				return
+			}
+			before = t
+		}
+
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		// Выбор и перенос задач — одна атомарная операция
+		var archived []int
+		err = modifyTasks(store, "archive", func(tasks []task.Task) ([]task.Task, error) {
+			archived = archivable(tasks, before)
+			moved := make(map[int]bool, len(archived))
+			for _, id := range archived {
+				moved[id] = true
+			}
+
+			active := make([]task.Task, 0, len(tasks)-len(archived))
+			for _, t := range tasks {
+				if !moved[t.ID] {
+					active = append(active, t)
+				}
+			}
+			// Зависимости от перенесённых задач больше не нужны
+			return task.PruneBlockers(active), nil
+		})
+		if err != nil {
+			printError("Ошибка при переносе задач в архив:", err)
+			return
+		}
+
+		if len(archived) == 0 {
+			fmt.Fprintln(textOut(), "Нет завершённых задач для переноса в архив.")
+			return
+		}
+		bulkResult{Changed: archived}.print("Задача с ID %d перенесена в архив.", "Перенесено в архив задач:", "")
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "archive" к rootCmd.
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+
+	// Флаг возраста завершённых задач
+	archiveCmd.Flags().String("older-than", "", "Перенести только задачи, завершённые раньше (например, 14d, 2w, 12h)")
+}
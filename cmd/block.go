@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// changeBlockers добавляет (add = true) или удаляет зависимости задачи id
+// от задач by одной операцией и возвращает зависимости, которые действительно
+// изменились. Пустой by при удалении снимает все зависимости задачи.
+// Новая зависимость, замыкающая цикл, отклоняется с *task.CycleError.
+func changeBlockers(store storage.Storage, id int, by []int, add bool) ([]int, error) {
+	var changed []int
+	kind := "unblock"
+	if add {
+		kind = "block"
+	}
+	err := modifyTasks(store, kind, func(tasks []task.Task) ([]task.Task, error) {
+		i := taskIndex(tasks, id)
+		if i < 0 {
+			return nil, &storage.NotFoundError{ID: id}
+		}
+		if !add && len(by) == 0 {
+			by = tasks[i].BlockedBy
+		}
+		for _, blocker := range by {
+			if add {
+				if err := task.CheckBlocker(tasks, id, blocker); err != nil {
+					return nil, err
+				}
+				if tasks[i].AddBlocker(blocker) {
+					changed = append(changed, blocker)
+				}
+			} else if tasks[i].RemoveBlocker(blocker) {
+				changed = append(changed, blocker)
+			}
+		}
+		return tasks, nil
+	})
+	return changed, err
+}
+
+// taskIndex возвращает индекс задачи с указанным ID в срезе или -1.
+func taskIndex(tasks []task.Task, id int) int {
+	for i, t := range tasks {
+		if t.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// openBlockersByID возвращает невыполненные зависимости каждой задачи,
+// у которой они есть.
+func openBlockersByID(tasks []task.Task) map[int][]int {
+	blocked := make(map[int][]int)
+	for _, t := range tasks {
+		if open := task.OpenBlockers(tasks, t); len(open) > 0 {
+			blocked[t.ID] = open
+		}
+	}
+	return blocked
+}
+
+// runBlockChange — общий обработчик "block" и "unblock".
+func runBlockChange(cmd *cobra.Command, args []string, add bool) {
+	// Конвертируем аргумент в int (ID задачи)
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		printError("Некорректный ID задачи:", args[0])
+		return
+	}
+	by, _ := cmd.Flags().GetIntSlice("by")
+
+	// Создаём хранилище задач
+	store, err := openStore()
+	if err != nil {
+		printError("Ошибка при открытии хранилища:", err)
+		return
+	}
+	defer func() { _ = store.Close() }()
+
+	changed, err := changeBlockers(store, id, by, add)
+	if err != nil {
+		printError("Ошибка:", err)
+		return
+	}
+
+	switch {
+	case len(changed) == 0 && add:
+		fmt.Fprintf(textOut(), "Задача с ID %d уже зависит от этих задач.\n", id)
+	case len(changed) == 0:
+		fmt.Fprintf(textOut(), "Задача с ID %d не зависит от этих задач.\n", id)
+	case add:
+		fmt.Fprintf(textOut(), "Задача с ID %d теперь ждёт задачи: %s\n", id, formatIDs(changed))
+	default:
+		fmt.Fprintf(textOut(), "Задача с ID %d больше не ждёт задачи: %s\n", id, formatIDs(changed))
+	}
+}
+
+// blockCmd — подкоманда "block", которая делает задачу зависимой от других:
+// браться за неё имеет смысл только после их выполнения.
+// Пример использования:
+//
+//	todo block 5 --by 3      — задача 5 ждёт задачу 3
+//	todo block 5 --by 3,4    — задача 5 ждёт задачи 3 и 4
+var blockCmd = &cobra.Command{
+	Use:               "block [task ID] --by [task ID]...",  // формат вызова
+	Short:             "Сделать задачу зависимой от других", // краткое описание
+	Args:              cobra.ExactArgs(1),                   // ожидаем ровно один аргумент — ID задачи
+	ValidArgsFunction: completeTaskIDs,                      // автодополнение ID задачи
+	Run:               func(cmd *cobra.Command, args []string) { runBlockChange(cmd, args, true) },
+}
+
+// unblockCmd — подкоманда "unblock", которая снимает зависимости задачи.
+// Пример использования:
+//
+//	todo unblock 5 --by 3    — задача 5 больше не ждёт задачу 3
+//	todo unblock 5           — снять все зависимости задачи 5
+var unblockCmd = &cobra.Command{
+	Use:               "unblock [task ID] [--by task ID]...", // формат вызова
+	Short:             "Снять зависимости задачи",            // краткое описание
+	Args:              cobra.ExactArgs(1),                    // ожидаем ровно один аргумент — ID задачи
+	ValidArgsFunction: completeTaskIDs,                       // автодополнение ID задачи
+	Run:               func(cmd *cobra.Command, args []string) { runBlockChange(cmd, args, false) },
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманды "block" и "unblock" к rootCmd.
+func init() {
+	rootCmd.AddCommand(blockCmd, unblockCmd)
+
+	blockCmd.Flags().IntSlice("by", nil, "ID задач, от которых зависит задача (через запятую или повторно)")
+	_ = blockCmd.MarkFlagRequired("by")
+	_ = blockCmd.RegisterFlagCompletionFunc("by", completeTaskIDs)
+
+	unblockCmd.Flags().IntSlice("by", nil, "ID задач, зависимость от которых нужно снять (по умолчанию — все)")
+	_ = unblockCmd.RegisterFlagCompletionFunc("by", completeTaskIDs)
+}
@@ -5,11 +5,11 @@ import (
 	"github.com/zen-flo/todo-cli/internal/task"
 
 	"github.com/spf13/cobra"
-	"github.com/zen-flo/todo-cli/internal/storage"
 )
 
 // clearCmd — подкоманда "clear", которая удаляет все завершённые задачи
-// из списка. Используется для очистки списка задач от "мусора".
+// из списка в корзину. Используется для очистки списка задач от "мусора".
+// Незавершённые подзадачи удалённых задач остаются задачами верхнего уровня.
 // Пример использования:
 //
 //	todo clear
@@ -18,37 +18,40 @@ var clearCmd = &cobra.Command{
 	Short: "Удалить все завершённые задачи", // краткое описание
 	Run: func(cmd *cobra.Command, args []string) {
 		// Создаём хранилище задач
-		store := storage.NewJSONStore(tasksFile)
-
-		// Загружаем список задач
-		tasks, err := store.ListTasks()
+		store, err := openStore()
 		if err != nil {
-			fmt.Println("Ошибка при загрузке задач:", err)
+			printError("Ошибка при открытии хранилища:", err)
 			return
 		}
+		defer func() { _ = store.Close() }()
 
-		// Фильтруем только незавершённые
-		active := make([]task.Task, 0)
+		// Оставляем только незавершённые задачи.
+		// Чтение и перезапись выполняются одной атомарной операцией.
 		cleared := 0
-		for _, t := range tasks {
-			if !t.Completed {
-				active = append(active, t)
-			} else {
-				cleared++
+		err = modifyTasks(store, "clear", func(tasks []task.Task) ([]task.Task, error) {
+			var closed []int
+			for _, t := range tasks {
+				if t.IsClosed() {
+					closed = append(closed, t.ID)
+				}
 			}
-		}
-
-		// Перезаписываем список
-		if err := store.OverwriteTasks(active); err != nil {
-			fmt.Println("Ошибка при очистке завершённых задач:", err)
+			cleared = len(closed)
+			// Незавершённые подзадачи удалённых задач становятся задачами
+			// верхнего уровня, зависимости от удалённых задач снимаются
+			active, _ := deleteWithChildren(tasks, closed, "orphan")
+			return active, nil
+		})
+		if err != nil {
+			printError("Ошибка при очистке завершённых задач:", err)
 			return
 		}
 
 		// Сообщение пользователю
 		if cleared > 0 {
-			fmt.Printf("Удалено завершённых задач: %d\n", cleared)
+			fmt.Fprintf(textOut(), "Удалено завершённых задач: %d\n", cleared)
+			fmt.Fprintln(textOut(), "Удалённые задачи лежат в корзине: todo trash list")
 		} else {
-			fmt.Println("Нет завершённых задач для удаления.")
+			fmt.Fprintln(textOut(), "Нет завершённых задач для удаления.")
 		}
 	},
 }
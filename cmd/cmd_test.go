@@ -2,12 +2,21 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/zen-flo/todo-cli/internal/output"
 	"github.com/zen-flo/todo-cli/internal/storage"
 	"github.com/zen-flo/todo-cli/internal/task"
 )
@@ -49,7 +58,9 @@ func captureOutput(f func()) string {
 
 // --- Вспомогательная функция для временного хранилища ---
 func withTempStore(t *testing.T, f func(store *storage.JSONStore, tmpFile string)) {
-	tmpFile, err := os.CreateTemp("", "tasks_*.json")
+	// Файл создаём во временной директории теста, чтобы вместе с ним
+	// удалялись и служебные файлы хранилища (например, tasks.json.lock).
+	tmpFile, err := os.CreateTemp(t.TempDir(), "tasks_*.json")
 	if err != nil {
 		t.Fatalf("не удалось создать временный файл: %v", err)
 	}
@@ -67,6 +78,38 @@ func withTempStore(t *testing.T, f func(store *storage.JSONStore, tmpFile string
 	f(store, tmpFile.Name())
 }
 
+// --- Вспомогательная функция для сброса флагов команды ---
+// Флаги cobra живут в глобальных переменных команд и сохраняются между
+// тестами, поэтому тесты, зависящие от флагов, начинают с чистого листа.
+// Глобальные флаги (--file, --output) не сбрасываются: после rootCmd.Execute
+// cobra добавляет их в cmd.Flags().
+func resetFlags(t *testing.T, cmd *cobra.Command) {
+	t.Helper()
+
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if cmd != cmd.Root() && cmd.Root().PersistentFlags().Lookup(f.Name) == f {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sv.Replace(nil)
+		} else if err := f.Value.Set(f.DefValue); err != nil {
+			t.Fatalf("не удалось сбросить флаг --%s: %v", f.Name, err)
+		}
+		f.Changed = false
+	})
+}
+
+// --- Вспомогательная функция для установки флагов команды ---
+func setFlags(t *testing.T, cmd *cobra.Command, flags map[string]string) {
+	t.Helper()
+
+	for name, value := range flags {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("не удалось установить флаг --%s=%s: %v", name, value, err)
+		}
+	}
+}
+
 // --- Тест команды addCmd ---
 func TestAddCommand(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
@@ -82,7 +125,7 @@ func TestAddCommand(t *testing.T) {
 		if len(tasks) != 1 {
 			t.Fatalf("ожидалось 1 задача, получено %d", len(tasks))
 		}
-		if tasks[0].Title != "Test Task" || !tasks[0].Important {
+		if tasks[0].Title != "Test Task" || tasks[0].Priority != task.PriorityHigh {
 			t.Errorf("неверные данные задачи: %+v", tasks[0])
 		}
 	})
@@ -145,10 +188,10 @@ func TestUpdateCmdValidArgsFunction(t *testing.T) {
 // --- Тест команды doneCmd ---
 func TestDoneCommand(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
-		err := store.AddTask(task.Task{
+		_, err := store.AddTask(task.Task{
 			ID:        1,
 			Title:     "Task 1",
-			Completed: true,
+			Status:    task.StatusDone,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
@@ -160,7 +203,7 @@ func TestDoneCommand(t *testing.T) {
 		})
 
 		tasks, _ := store.ListTasks()
-		if !tasks[0].Completed {
+		if tasks[0].Status != task.StatusDone {
 			t.Errorf("задача должна быть отмечена как выполненная")
 		}
 	})
@@ -193,7 +236,7 @@ func TestDoneCommand_InvalidID(t *testing.T) {
 // --- Тест команды deleteCmd ---
 func TestDeleteCommand(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
-		err := store.AddTask(task.Task{
+		_, err := store.AddTask(task.Task{
 			ID:        1,
 			Title:     "Task 1",
 			CreatedAt: time.Now(),
@@ -249,7 +292,7 @@ func TestDeleteCommand_InvalidID(t *testing.T) {
 // --- Тест команды updateCmd ---
 func TestUpdateCommand(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
-		err := store.AddTask(task.Task{
+		_, err := store.AddTask(task.Task{
 			ID:        1,
 			Title:     "Old Title",
 			CreatedAt: time.Now(),
@@ -266,7 +309,7 @@ func TestUpdateCommand(t *testing.T) {
 		})
 
 		tasks, _ := store.ListTasks()
-		if tasks[0].Title != "New Title" || !tasks[0].Important {
+		if tasks[0].Title != "New Title" || tasks[0].Priority != task.PriorityHigh {
 			t.Errorf("обновление задачи не сработало: %+v", tasks[0])
 		}
 	})
@@ -299,7 +342,7 @@ func TestUpdateCommand_NoArgs_Error(t *testing.T) {
 // --- Тест updateCmd с некорректным ID ---
 func TestUpdateCommand_InvalidID(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
-		err := store.AddTask(task.Task{
+		_, err := store.AddTask(task.Task{
 			ID:        1,
 			Title:     "Old Task",
 			CreatedAt: time.Now(),
@@ -332,7 +375,7 @@ func TestUpdateCommand_BadID_Error(t *testing.T) {
 // --- Тест команды listCmd ---
 func TestListCommand(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
-		err := store.AddTask(task.Task{
+		_, err := store.AddTask(task.Task{
 			ID:        1,
 			Title:     "Task A",
 			CreatedAt: time.Now(),
@@ -340,10 +383,10 @@ func TestListCommand(t *testing.T) {
 		if err != nil {
 			t.Fatalf("не удалось выполнить AddTask: %v", err)
 		}
-		err = store.AddTask(task.Task{
+		_, err = store.AddTask(task.Task{
 			ID:        2,
 			Title:     "Task B",
-			Completed: true,
+			Status:    task.StatusDone,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
@@ -391,19 +434,19 @@ func TestListCommand_InvalidSort(t *testing.T) {
 // --- Тест команды pendingCmd ---
 func TestPendingCommand(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
-		err := store.AddTask(task.Task{
+		_, err := store.AddTask(task.Task{
 			ID:        1,
 			Title:     "Task 1",
-			Completed: false,
+			Status:    task.StatusTodo,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
 			t.Fatalf("не удалось выполнить AddTask: %v", err)
 		}
-		err = store.AddTask(task.Task{
+		_, err = store.AddTask(task.Task{
 			ID:        2,
 			Title:     "Task 2",
-			Completed: true,
+			Status:    task.StatusDone,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
@@ -426,19 +469,19 @@ func TestPendingCommand(t *testing.T) {
 // --- Тест команды completedCmd ---
 func TestCompletedCommand(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
-		err := store.AddTask(task.Task{
+		_, err := store.AddTask(task.Task{
 			ID:        1,
 			Title:     "Task 1",
-			Completed: true,
+			Status:    task.StatusDone,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
 			t.Fatalf("не удалось выполнить AddTask: %v", err)
 		}
-		err = store.AddTask(task.Task{
+		_, err = store.AddTask(task.Task{
 			ID:        2,
 			Title:     "Task 2",
-			Completed: false,
+			Status:    task.StatusTodo,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
@@ -462,19 +505,19 @@ func TestCompletedCommand(t *testing.T) {
 func TestCompleteAllCommand_AlreadyCompleted(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
 		// Добавляем задачи, все уже выполненные
-		err := store.AddTask(task.Task{
+		_, err := store.AddTask(task.Task{
 			ID:        1,
 			Title:     "Task 1",
-			Completed: true,
+			Status:    task.StatusDone,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
 			t.Fatalf("не удалось добавить задачу: %v", err)
 		}
-		err = store.AddTask(task.Task{
+		_, err = store.AddTask(task.Task{
 			ID:        2,
 			Title:     "Task 2",
-			Completed: true,
+			Status:    task.StatusDone,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
@@ -495,7 +538,7 @@ func TestCompleteAllCommand_AlreadyCompleted(t *testing.T) {
 		// Проверяем, что все задачи остаются выполненными
 		tasks, _ := store.ListTasks()
 		for _, tsk := range tasks {
-			if !tsk.Completed {
+			if tsk.Status != task.StatusDone {
 				t.Errorf("задача %d должна быть отмечена как выполненная", tsk.ID)
 			}
 		}
@@ -505,19 +548,19 @@ func TestCompleteAllCommand_AlreadyCompleted(t *testing.T) {
 // --- Тест команды clearCmd ---
 func TestClearCommand(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
-		err := store.AddTask(task.Task{
+		_, err := store.AddTask(task.Task{
 			ID:        1,
 			Title:     "Task 1",
-			Completed: true,
+			Status:    task.StatusDone,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
 			t.Fatalf("не удалось выполнить AddTask: %v", err)
 		}
-		err = store.AddTask(task.Task{
+		_, err = store.AddTask(task.Task{
 			ID:        2,
 			Title:     "Task 2",
-			Completed: false,
+			Status:    task.StatusTodo,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
@@ -535,14 +578,46 @@ func TestClearCommand(t *testing.T) {
 	})
 }
 
+// --- Проверка clearCmd с подзадачами ---
+// Незавершённые подзадачи удалённой задачи становятся задачами верхнего
+// уровня и не ссылаются на удалённого родителя.
+func TestClearCommand_Subtasks(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		for _, tk := range []task.Task{
+			{Title: "Родитель", Status: task.StatusDone},
+			{Title: "Открытая подзадача", ParentID: 1},
+			{Title: "Закрытая подзадача", ParentID: 1, Status: task.StatusCancelled},
+			{Title: "Внучка", ParentID: 2},
+		} {
+			tk.CreatedAt = time.Now()
+			if _, err := store.AddTask(tk); err != nil {
+				t.Fatalf("не удалось выполнить AddTask: %v", err)
+			}
+		}
+
+		captureOutput(func() { clearCmd.Run(clearCmd, []string{}) })
+
+		tasks, _ := store.ListTasks()
+		if len(tasks) != 2 || tasks[0].ID != 2 || tasks[1].ID != 4 {
+			t.Fatalf("ожидались задачи 2 и 4, получено %+v", tasks)
+		}
+		if tasks[0].ParentID != 0 {
+			t.Errorf("подзадача ссылается на удалённого родителя: ParentID = %d", tasks[0].ParentID)
+		}
+		if tasks[1].ParentID != 2 {
+			t.Errorf("связь с оставшимся родителем потеряна: ParentID = %d", tasks[1].ParentID)
+		}
+	})
+}
+
 // --- Проверка ошибок clearCmd ---
 func TestClearCommand_ErrorOnOverwrite(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
 		// Добавляем завершённую задачу, чтобы список active не был пустым
-		if err := store.AddTask(task.Task{
+		if _, err := store.AddTask(task.Task{
 			ID:        1,
 			Title:     "Completed Task",
-			Completed: true,
+			Status:    task.StatusDone,
 			CreatedAt: time.Now(),
 		}); err != nil {
 			t.Fatalf("не удалось добавить задачу: %v", err)
@@ -583,19 +658,19 @@ func TestClearCommand_EmptyList(t *testing.T) {
 // --- Тест команды completeAllCmd ---
 func TestCompleteAllCommand(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
-		err := store.AddTask(task.Task{
+		_, err := store.AddTask(task.Task{
 			ID:        1,
 			Title:     "Task 1",
-			Completed: false,
+			Status:    task.StatusTodo,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
 			t.Fatalf("не удалось выполнить AddTask: %v", err)
 		}
-		err = store.AddTask(task.Task{
+		_, err = store.AddTask(task.Task{
 			ID:        2,
 			Title:     "Task 2",
-			Completed: false,
+			Status:    task.StatusTodo,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
@@ -608,7 +683,7 @@ func TestCompleteAllCommand(t *testing.T) {
 
 		tasks, _ := store.ListTasks()
 		for _, tsk := range tasks {
-			if !tsk.Completed {
+			if tsk.Status != task.StatusDone {
 				t.Errorf("задача %d должна быть отмечена как выполненная", tsk.ID)
 			}
 		}
@@ -618,7 +693,7 @@ func TestCompleteAllCommand(t *testing.T) {
 // --- Проверка completeAllCmd для всех выполненных ---
 func TestCompleteAllCommand_AllCompleted(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
-		_ = store.AddTask(task.Task{ID: 1, Title: "Task 1", Completed: true})
+		_, _ = store.AddTask(task.Task{ID: 1, Title: "Task 1", Status: task.StatusDone})
 		output := captureOutput(func() {
 			completeAllCmd.Run(completeAllCmd, []string{})
 		})
@@ -631,19 +706,19 @@ func TestCompleteAllCommand_AllCompleted(t *testing.T) {
 // --- Тест команды searchCmd ---
 func TestSearchCommand(t *testing.T) {
 	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
-		err := store.AddTask(task.Task{
+		_, err := store.AddTask(task.Task{
 			ID:        1,
 			Title:     "Buy Milk",
-			Completed: false,
+			Status:    task.StatusTodo,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
 			t.Fatalf("не удалось выполнить AddTask: %v", err)
 		}
-		err = store.AddTask(task.Task{
+		_, err = store.AddTask(task.Task{
 			ID:        2,
 			Title:     "Read Book",
-			Completed: false,
+			Status:    task.StatusTodo,
 			CreatedAt: time.Now(),
 		})
 		if err != nil {
@@ -662,3 +737,1904 @@ func TestSearchCommand(t *testing.T) {
 		}
 	})
 }
+
+// --- Тест выбора хранилища через --backend=sqlite ---
+func TestBackendSQLite(t *testing.T) {
+	origBackend, origDB := backend, dbFile
+	backend = "sqlite"
+	dbFile = filepath.Join(t.TempDir(), "tasks.db")
+	defer func() { backend, dbFile = origBackend, origDB }()
+
+	captureOutput(func() {
+		addCmd.Run(addCmd, []string{"SQLite Task"})
+		doneCmd.Run(doneCmd, []string{"1"})
+	})
+
+	output := captureOutput(func() {
+		completedCmd.Run(completedCmd, []string{})
+	})
+	if !strings.Contains(output, "SQLite Task") {
+		t.Errorf("задача должна сохраниться в SQLite и быть выполненной, получено: %s", output)
+	}
+}
+
+// --- Тест неизвестного хранилища ---
+func TestBackendUnknown(t *testing.T) {
+	orig := backend
+	backend = "redis"
+	defer func() { backend = orig }()
+
+	output := captureOutput(func() {
+		pendingCmd.Run(pendingCmd, []string{})
+	})
+	if !strings.Contains(output, "неизвестное хранилище") {
+		t.Errorf("ожидалось сообщение о неизвестном хранилище, получено: %s", output)
+	}
+}
+
+// --- Тест передачи --lock-timeout в JSON-хранилище ---
+func TestOpenStore_LockTimeout(t *testing.T) {
+	orig := lockTimeout
+	lockTimeout = 250 * time.Millisecond
+	defer func() { lockTimeout = orig }()
+
+	store, err := openStore()
+	if err != nil {
+		t.Fatalf("openStore вернул ошибку: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	journaled, ok := store.(*storage.JournaledStore)
+	if !ok {
+		t.Fatalf("ожидался *storage.JournaledStore, получено %T", store)
+	}
+	js, ok := journaled.Storage.(*storage.JSONStore)
+	if !ok {
+		t.Fatalf("ожидался *storage.JSONStore, получено %T", journaled.Storage)
+	}
+	if js.LockTimeout != 250*time.Millisecond {
+		t.Errorf("ожидался таймаут 250ms, получено %s", js.LockTimeout)
+	}
+}
+
+// --- Тест команд undo и redo ---
+func TestUndoRedoCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		captureOutput(func() {
+			addCmd.Run(addCmd, []string{"Undo Task"})
+			deleteCmd.Run(deleteCmd, []string{"1"})
+		})
+
+		output := captureOutput(func() {
+			undoCmd.Run(undoCmd, []string{})
+		})
+		if !strings.Contains(output, "Отменена операция") || !strings.Contains(output, "delete") {
+			t.Errorf("ожидалось сообщение об отмене delete, получено: %s", output)
+		}
+		tasks, _ := store.ListTasks()
+		if len(tasks) != 1 || tasks[0].Title != "Undo Task" {
+			t.Fatalf("после undo задача должна вернуться: %+v", tasks)
+		}
+
+		// Просмотр журнала показывает обе операции, delete — как отменённую.
+		if err := undoCmd.Flags().Set("list", "true"); err != nil {
+			t.Fatalf("не удалось установить флаг: %v", err)
+		}
+		output = captureOutput(func() {
+			undoCmd.Run(undoCmd, []string{})
+		})
+		if err := undoCmd.Flags().Set("list", "false"); err != nil {
+			t.Fatalf("не удалось сбросить флаг: %v", err)
+		}
+		if !strings.Contains(output, "add") || !strings.Contains(output, "(отменена)") {
+			t.Errorf("ожидался список операций с отменённой delete, получено: %s", output)
+		}
+
+		output = captureOutput(func() {
+			redoCmd.Run(redoCmd, []string{})
+		})
+		if !strings.Contains(output, "Повторена операция") {
+			t.Errorf("ожидалось сообщение о повторе, получено: %s", output)
+		}
+		tasks, _ = store.ListTasks()
+		if len(tasks) != 0 {
+			t.Errorf("после redo задача должна быть снова удалена: %+v", tasks)
+		}
+	})
+}
+
+// --- Тест undo при пустом журнале ---
+func TestUndoCommand_Empty(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		output := captureOutput(func() {
+			undoCmd.Run(undoCmd, []string{})
+		})
+		if !strings.Contains(output, "нет операций для отмены") {
+			t.Errorf("ожидалось сообщение об отсутствии операций, получено: %s", output)
+		}
+	})
+}
+
+// --- Тест команд history и log ---
+func TestHistoryAndLogCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		captureOutput(func() {
+			addCmd.Run(addCmd, []string{"Audit Task"})
+			addCmd.Run(addCmd, []string{"Other Task"})
+			doneCmd.Run(doneCmd, []string{"1"})
+		})
+
+		output := captureOutput(func() {
+			historyCmd.Run(historyCmd, []string{"1"})
+		})
+		if !strings.Contains(output, `создана: "Audit Task"`) || !strings.Contains(output, `status: "todo" → "done"`) {
+			t.Errorf("ожидалась история создания и выполнения, получено: %s", output)
+		}
+		if strings.Contains(output, "Other Task") {
+			t.Errorf("в историю задачи 1 попала другая задача: %s", output)
+		}
+
+		output = captureOutput(func() {
+			historyCmd.Run(historyCmd, []string{"42"})
+		})
+		if !strings.Contains(output, "История задачи с ID 42 пуста") {
+			t.Errorf("ожидалось сообщение о пустой истории, получено: %s", output)
+		}
+
+		output = captureOutput(func() {
+			logCmd.Run(logCmd, []string{})
+		})
+		if strings.Count(output, "\n") != 3 || !strings.Contains(output, "Other Task") {
+			t.Errorf("ожидалось три события в журнале, получено: %s", output)
+		}
+
+		// События из будущего не существуют — журнал за период пуст.
+		if err := logCmd.Flags().Set("since", "2999-01-01"); err != nil {
+			t.Fatalf("не удалось установить флаг: %v", err)
+		}
+		defer func() { _ = logCmd.Flags().Set("since", "") }()
+		output = captureOutput(func() {
+			logCmd.Run(logCmd, []string{})
+		})
+		if !strings.Contains(output, "Изменений не найдено") {
+			t.Errorf("ожидался пустой журнал, получено: %s", output)
+		}
+	})
+}
+
+// --- Тест разбора --since ---
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"24h", now.Add(-24 * time.Hour)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"2w", now.AddDate(0, 0, -14)},
+		{"today", time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)},
+		{"yesterday", time.Date(2025, 3, 9, 0, 0, 0, 0, time.Local)},
+		{"2025-03-01", time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)},
+		{"2025-03-01 09:15", time.Date(2025, 3, 1, 9, 15, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, %v; ожидалось %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	if _, err := parseSince("вчера", now); err == nil {
+		t.Error("ожидалась ошибка для некорректного значения")
+	}
+}
+
+// --- Тест срока выполнения в add и update ---
+func TestAddAndUpdateDue(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		resetFlags(t, addCmd)
+		resetFlags(t, updateCmd)
+		defer resetFlags(t, addCmd)
+		defer resetFlags(t, updateCmd)
+
+		setFlags(t, addCmd, map[string]string{"due": "2030-05-20"})
+		output := captureOutput(func() {
+			addCmd.Run(addCmd, []string{"Due Task"})
+		})
+		if !strings.Contains(output, "срок: 2030-05-20") {
+			t.Errorf("ожидался срок в сообщении, получено: %s", output)
+		}
+
+		tasks, _ := store.ListTasks()
+		want := time.Date(2030, 5, 20, 0, 0, 0, 0, time.Local)
+		if len(tasks) != 1 || !tasks[0].DueAt.Equal(want) {
+			t.Fatalf("ожидался срок %v, получено %+v", want, tasks)
+		}
+
+		// Только срок, без нового названия
+		setFlags(t, updateCmd, map[string]string{"due": "+3d"})
+		captureOutput(func() {
+			updateCmd.Run(updateCmd, []string{"1"})
+		})
+		got, _ := store.GetTask(1)
+		want, _ = task.ParseDate("+3d", time.Now())
+		if got.Title != "Due Task" || !got.DueAt.Equal(want) {
+			t.Errorf("срок не обновился или сбросилось название: %+v", got)
+		}
+
+		// none убирает срок
+		setFlags(t, updateCmd, map[string]string{"due": "none"})
+		captureOutput(func() {
+			updateCmd.Run(updateCmd, []string{"1"})
+		})
+		got, _ = store.GetTask(1)
+		if !got.DueAt.IsZero() {
+			t.Errorf("ожидалось, что срок будет убран: %+v", got)
+		}
+
+		setFlags(t, addCmd, map[string]string{"due": "когда-нибудь"})
+		output = captureOutput(func() {
+			addCmd.Run(addCmd, []string{"Bad Due"})
+		})
+		if !strings.Contains(output, "не удалось разобрать дату") {
+			t.Errorf("ожидалась ошибка разбора даты, получено: %s", output)
+		}
+	})
+}
+
+// --- Тест фильтров и сортировки по сроку в list ---
+func TestListCommand_Due(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		resetFlags(t, listCmd)
+		defer resetFlags(t, listCmd)
+
+		day := func(d int) time.Time { return time.Date(2030, 1, d, 0, 0, 0, 0, time.Local) }
+		for _, tk := range []task.Task{
+			{Title: "No Due", CreatedAt: time.Now()},
+			{Title: "Late Due", CreatedAt: time.Now(), DueAt: day(20)},
+			{Title: "Early Due", CreatedAt: time.Now(), DueAt: day(5)},
+			{Title: "Overdue", CreatedAt: time.Now(), DueAt: time.Now().AddDate(0, 0, -2)},
+		} {
+			if _, err := store.AddTask(tk); err != nil {
+				t.Fatalf("не удалось добавить задачу: %v", err)
+			}
+		}
+
+		setFlags(t, listCmd, map[string]string{"sort": "due"})
+		output := captureOutput(func() {
+			listCmd.Run(listCmd, []string{})
+		})
+		order := []string{"Overdue", "Early Due", "Late Due", "No Due"}
+		last := -1
+		for _, title := range order {
+			idx := strings.Index(output, title)
+			if idx <= last {
+				t.Fatalf("ожидался порядок %v, получено:\n%s", order, output)
+			}
+			last = idx
+		}
+		if !strings.Contains(output, "\033[33mOverdue") || strings.Contains(output, "\033[33mNo Due") {
+			t.Errorf("просроченной должна быть только задача с прошедшим сроком:\n%s", output)
+		}
+
+		setFlags(t, listCmd, map[string]string{"due-after": "2030-01-01", "due-before": "2030-01-10"})
+		output = captureOutput(func() {
+			listCmd.Run(listCmd, []string{})
+		})
+		if !strings.Contains(output, "Early Due") || strings.Contains(output, "Late Due") ||
+			strings.Contains(output, "No Due") || strings.Contains(output, "Overdue") {
+			t.Errorf("фильтр по сроку сработал неверно:\n%s", output)
+		}
+	})
+}
+
+// --- Тест приоритетов: add, update, фильтр и сортировка в list ---
+func TestPriorityCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		for _, c := range []*cobra.Command{addCmd, updateCmd, listCmd} {
+			resetFlags(t, c)
+			defer resetFlags(t, c)
+		}
+
+		captureOutput(func() {
+			for _, p := range []string{"low", "urgent", "normal", "high"} {
+				setFlags(t, addCmd, map[string]string{"priority": p})
+				addCmd.Run(addCmd, []string{"Task " + p})
+			}
+		})
+
+		// update с одним флагом меняет только приоритет
+		setFlags(t, updateCmd, map[string]string{"priority": "urgent"})
+		captureOutput(func() {
+			updateCmd.Run(updateCmd, []string{"1"})
+		})
+		got, _ := store.GetTask(1)
+		if got.Priority != task.PriorityUrgent || got.Title != "Task low" {
+			t.Errorf("ожидался приоритет urgent без смены названия: %+v", got)
+		}
+
+		setFlags(t, listCmd, map[string]string{"priority": ">=high", "sort": "-priority"})
+		output := captureOutput(func() {
+			listCmd.Run(listCmd, []string{})
+		})
+		if strings.Contains(output, "Task normal") {
+			t.Errorf("фильтр >=high пропустил обычную задачу:\n%s", output)
+		}
+		iUrgent, iLow, iHigh := strings.Index(output, "Task urgent"), strings.Index(output, "Task low"), strings.Index(output, "Task high")
+		if iUrgent < 0 || iLow < 0 || iHigh < 0 || iHigh < iUrgent || iHigh < iLow {
+			t.Errorf("ожидались срочные задачи перед high:\n%s", output)
+		}
+		if !strings.Contains(output, "🚨 Task urgent") || !strings.Contains(output, "🔥 Task high") {
+			t.Errorf("ожидались значки приоритета:\n%s", output)
+		}
+
+		setFlags(t, listCmd, map[string]string{"priority": "важно"})
+		output = captureOutput(func() {
+			listCmd.Run(listCmd, []string{})
+		})
+		if !strings.Contains(output, "неизвестный приоритет") {
+			t.Errorf("ожидалась ошибка фильтра, получено: %s", output)
+		}
+
+		setFlags(t, addCmd, map[string]string{"priority": "critical"})
+		output = captureOutput(func() {
+			addCmd.Run(addCmd, []string{"Bad"})
+		})
+		if !strings.Contains(output, "неизвестный приоритет") {
+			t.Errorf("ожидалась ошибка приоритета, получено: %s", output)
+		}
+	})
+}
+
+// --- Тест разбора фильтра приоритета ---
+func TestParsePriorityFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   []task.Priority // приоритеты, проходящие фильтр
+	}{
+		{"high", []task.Priority{task.PriorityHigh}},
+		{">=high", []task.Priority{task.PriorityHigh, task.PriorityUrgent}},
+		{">normal", []task.Priority{task.PriorityHigh, task.PriorityUrgent}},
+		{"<=normal", []task.Priority{task.PriorityLow, task.PriorityNormal}},
+		{"<normal", []task.Priority{task.PriorityLow}},
+		{"=urgent", []task.Priority{task.PriorityUrgent}},
+	}
+	all := []task.Priority{task.PriorityLow, task.PriorityNormal, task.PriorityHigh, task.PriorityUrgent}
+
+	for _, tt := range tests {
+		ok, err := parsePriorityFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("parsePriorityFilter(%q) вернул ошибку: %v", tt.filter, err)
+		}
+		var got []task.Priority
+		for _, p := range all {
+			if ok(p) {
+				got = append(got, p)
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%q: ожидалось %v, получено %v", tt.filter, tt.want, got)
+		}
+	}
+}
+
+// --- Тест тегов: add/update --tag, tag add/remove, tags, фильтр list ---
+func TestTagCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		for _, c := range []*cobra.Command{addCmd, updateCmd, listCmd} {
+			resetFlags(t, c)
+			defer resetFlags(t, c)
+		}
+
+		captureOutput(func() {
+			setFlags(t, addCmd, map[string]string{"tag": "Backend"})
+			addCmd.Run(addCmd, []string{"API"})
+			resetFlags(t, addCmd)
+			setFlags(t, addCmd, map[string]string{"tag": "infra"})
+			addCmd.Run(addCmd, []string{"Deploy"})
+			resetFlags(t, addCmd)
+			addCmd.Run(addCmd, []string{"Untagged"})
+		})
+
+		output := captureOutput(func() {
+			tagAddCmd.Run(tagAddCmd, []string{"2", "oncall", "backend"})
+		})
+		if !strings.Contains(output, "добавлены теги: oncall, backend") {
+			t.Errorf("ожидалось сообщение о добавлении тегов, получено: %s", output)
+		}
+		output = captureOutput(func() {
+			tagRemoveCmd.Run(tagRemoveCmd, []string{"2", "oncall"})
+		})
+		if !strings.Contains(output, "удалены теги: oncall") {
+			t.Errorf("ожидалось сообщение об удалении тега, получено: %s", output)
+		}
+		output = captureOutput(func() {
+			tagRemoveCmd.Run(tagRemoveCmd, []string{"42", "oncall"})
+		})
+		if !strings.Contains(output, "не найдена") {
+			t.Errorf("ожидалась ошибка для несуществующей задачи, получено: %s", output)
+		}
+
+		// update: +x добавляет, -x убирает
+		setFlags(t, updateCmd, map[string]string{"tag": "-backend"})
+		captureOutput(func() {
+			updateCmd.Run(updateCmd, []string{"1"})
+		})
+		if got, _ := store.GetTask(1); len(got.Tags) != 0 || got.Title != "API" {
+			t.Errorf("ожидалось, что тег backend будет убран: %+v", got)
+		}
+
+		output = captureOutput(func() {
+			tagsCmd.Run(tagsCmd, []string{})
+		})
+		if !strings.Contains(output, "backend") || !strings.Contains(output, "infra") || strings.Contains(output, "oncall") {
+			t.Errorf("неверный список тегов: %s", output)
+		}
+
+		setFlags(t, listCmd, map[string]string{"tag": "infra"})
+		output = captureOutput(func() {
+			listCmd.Run(listCmd, []string{})
+		})
+		if !strings.Contains(output, "Deploy") || !strings.Contains(output, "#backend #infra") || strings.Contains(output, "API") {
+			t.Errorf("фильтр --tag=infra сработал неверно:\n%s", output)
+		}
+
+		resetFlags(t, listCmd)
+		setFlags(t, listCmd, map[string]string{"tag": "-infra"})
+		output = captureOutput(func() {
+			listCmd.Run(listCmd, []string{})
+		})
+		if strings.Contains(output, "Deploy") || !strings.Contains(output, "API") || !strings.Contains(output, "Untagged") {
+			t.Errorf("фильтр --tag=-infra сработал неверно:\n%s", output)
+		}
+
+		names, _ := completeTags(listCmd, nil, "-")
+		if fmt.Sprint(names) != "[-backend -infra]" {
+			t.Errorf("неверное автодополнение тегов: %v", names)
+		}
+		names, _ = completeTagArgs(false)(tagRemoveCmd, []string{"2"}, "")
+		if fmt.Sprint(names) != "[backend infra]" {
+			t.Errorf("неверное автодополнение тегов задачи: %v", names)
+		}
+	})
+}
+
+// --- Тест проектов: фильтры, сводка, переименование и архив ---
+func TestProjectCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		for _, c := range []*cobra.Command{addCmd, listCmd, pendingCmd, completedCmd, searchCmd, projectArchiveCmd} {
+			resetFlags(t, c)
+			defer resetFlags(t, c)
+		}
+
+		for _, tk := range []task.Task{
+			{Title: "Release notes", Project: "work.release", Status: task.StatusDone},
+			{Title: "Release build", Project: "work.release"},
+			{Title: "Standup", Project: "work"},
+			{Title: "Groceries", Project: "home"},
+			{Title: "Loose end"},
+		} {
+			tk.CreatedAt = time.Now()
+			if _, err := store.AddTask(tk); err != nil {
+				t.Fatalf("не удалось добавить задачу: %v", err)
+			}
+		}
+
+		setFlags(t, pendingCmd, map[string]string{"project": "work"})
+		output := captureOutput(func() {
+			pendingCmd.Run(pendingCmd, []string{})
+		})
+		if !strings.Contains(output, "Release build") || !strings.Contains(output, "Standup") ||
+			strings.Contains(output, "Groceries") || strings.Contains(output, "Release notes") {
+			t.Errorf("pending --project=work сработал неверно:\n%s", output)
+		}
+
+		setFlags(t, searchCmd, map[string]string{"project": "home"})
+		output = captureOutput(func() {
+			searchCmd.Run(searchCmd, []string{"e"})
+		})
+		if !strings.Contains(output, "Groceries") || strings.Contains(output, "Release") {
+			t.Errorf("search --project=home сработал неверно:\n%s", output)
+		}
+
+		output = captureOutput(func() {
+			projectListCmd.Run(projectListCmd, []string{})
+		})
+		for _, want := range []string{"work      ", "1/3", "  work.release", "1/2", "50%", "home", "(без проекта)"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("в сводке проектов нет %q:\n%s", want, output)
+			}
+		}
+
+		output = captureOutput(func() {
+			projectRenameCmd.Run(projectRenameCmd, []string{"work.release", "work.ship"})
+		})
+		if !strings.Contains(output, "задач: 2") {
+			t.Errorf("ожидалось переименование двух задач, получено: %s", output)
+		}
+		if got, _ := store.GetTask(2); got.Project != "work.ship" {
+			t.Errorf("проект не переименован: %+v", got)
+		}
+		output = captureOutput(func() {
+			projectRenameCmd.Run(projectRenameCmd, []string{"work", "work.inner"})
+		})
+		if !strings.Contains(output, "внутрь самого себя") {
+			t.Errorf("ожидалась ошибка переноса проекта в себя, получено: %s", output)
+		}
+
+		// Архивный проект пропадает из списков, но виден при явном --project
+		captureOutput(func() {
+			projectArchiveCmd.Run(projectArchiveCmd, []string{"work"})
+		})
+		output = captureOutput(func() {
+			listCmd.Run(listCmd, []string{})
+		})
+		if strings.Contains(output, "Standup") || !strings.Contains(output, "Groceries") {
+			t.Errorf("задачи архивного проекта должны быть скрыты:\n%s", output)
+		}
+		setFlags(t, completedCmd, map[string]string{"project": "work.ship"})
+		output = captureOutput(func() {
+			completedCmd.Run(completedCmd, []string{})
+		})
+		if !strings.Contains(output, "Release notes") {
+			t.Errorf("явно указанный архивный проект должен показываться:\n%s", output)
+		}
+
+		setFlags(t, projectArchiveCmd, map[string]string{"restore": "true"})
+		captureOutput(func() {
+			projectArchiveCmd.Run(projectArchiveCmd, []string{"work"})
+		})
+		if got, _ := store.GetTask(3); got.Archived {
+			t.Errorf("проект не возвращён из архива: %+v", got)
+		}
+
+		output = captureOutput(func() {
+			projectArchiveCmd.Run(projectArchiveCmd, []string{"nope"})
+		})
+		if !strings.Contains(output, "проект nope не найден") {
+			t.Errorf("ожидалась ошибка для неизвестного проекта, получено: %s", output)
+		}
+	})
+}
+
+// --- Тест подзадач: add --parent, done, list --tree, delete ---
+func TestSubtaskCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		for _, c := range []*cobra.Command{addCmd, updateCmd, doneCmd, listCmd, deleteCmd} {
+			resetFlags(t, c)
+			defer resetFlags(t, c)
+		}
+
+		captureOutput(func() {
+			setFlags(t, addCmd, map[string]string{"project": "work"})
+			addCmd.Run(addCmd, []string{"Release"})
+			resetFlags(t, addCmd)
+			setFlags(t, addCmd, map[string]string{"parent": "1"})
+			addCmd.Run(addCmd, []string{"Changelog"})
+			addCmd.Run(addCmd, []string{"Build"})
+			setFlags(t, addCmd, map[string]string{"parent": "3"})
+			addCmd.Run(addCmd, []string{"Binaries"})
+		})
+		if got, _ := store.GetTask(2); got.ParentID != 1 || got.Project != "work" {
+			t.Fatalf("подзадача должна получить родителя и его проект: %+v", got)
+		}
+
+		// Родителя с открытыми подзадачами закрыть нельзя
+		output := captureOutput(func() {
+			doneCmd.Run(doneCmd, []string{"1"})
+		})
+		if !strings.Contains(output, "есть невыполненные подзадачи: 2, 3, 4") {
+			t.Errorf("ожидался отказ закрыть родителя, получено: %s", output)
+		}
+
+		captureOutput(func() {
+			doneCmd.Run(doneCmd, []string{"2"})
+		})
+		setFlags(t, listCmd, map[string]string{"tree": "true"})
+		output = captureOutput(func() {
+			listCmd.Run(listCmd, []string{})
+		})
+		for _, want := range []string{"(1/2)", "├─ Changelog", "└─ Build", "   └─ Binaries"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("в дереве нет %q:\n%s", want, output)
+			}
+		}
+
+		// Нельзя сделать задачу подзадачей её же подзадачи
+		setFlags(t, updateCmd, map[string]string{"parent": "4"})
+		output = captureOutput(func() {
+			updateCmd.Run(updateCmd, []string{"1"})
+		})
+		if !strings.Contains(output, "своей же подзадачи") {
+			t.Errorf("ожидалась ошибка цикла, получено: %s", output)
+		}
+
+		// --cascade закрывает подзадачи вместе с родителем
+		setFlags(t, doneCmd, map[string]string{"cascade": "true"})
+		output = captureOutput(func() {
+			doneCmd.Run(doneCmd, []string{"3"})
+		})
+		if !strings.Contains(output, "выполнены подзадачи: 4") {
+			t.Errorf("ожидалось каскадное выполнение, получено: %s", output)
+		}
+		if got, _ := store.GetTask(4); got.Status != task.StatusDone {
+			t.Errorf("подзадача должна быть выполнена: %+v", got)
+		}
+
+		// delete спрашивает, что делать с подзадачами
+		origStdin := stdin
+		defer func() { stdin = origStdin }()
+
+		stdin = strings.NewReader("c\n")
+		output = captureOutput(func() {
+			deleteCmd.Run(deleteCmd, []string{"1"})
+		})
+		if !strings.Contains(output, "Удаление отменено") {
+			t.Errorf("ожидалась отмена удаления, получено: %s", output)
+		}
+
+		stdin = strings.NewReader("o\n")
+		captureOutput(func() {
+			deleteCmd.Run(deleteCmd, []string{"3"})
+		})
+		if got, err := store.GetTask(4); err != nil || got.ParentID != 0 {
+			t.Errorf("подзадача должна остаться без родителя: %+v, %v", got, err)
+		}
+
+		setFlags(t, deleteCmd, map[string]string{"children": "remove"})
+		captureOutput(func() {
+			deleteCmd.Run(deleteCmd, []string{"1"})
+		})
+		tasks, _ := store.ListTasks()
+		if len(tasks) != 1 || tasks[0].ID != 4 {
+			t.Errorf("ожидалось, что останется только задача 4: %+v", tasks)
+		}
+	})
+}
+
+// TestDependencyCommands проверяет block/unblock, list --ready,
+// отказ done для заблокированной задачи и очистку зависимостей при удалении.
+func TestDependencyCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		for _, c := range []*cobra.Command{blockCmd, unblockCmd, doneCmd, listCmd, deleteCmd} {
+			resetFlags(t, c)
+			defer resetFlags(t, c)
+		}
+
+		for _, title := range []string{"Backup", "Migrate", "Deploy", "Announce"} {
+			if _, err := store.AddTask(task.Task{Title: title, CreatedAt: time.Now()}); err != nil {
+				t.Fatalf("AddTask вернул ошибку: %v", err)
+			}
+		}
+
+		setFlags(t, blockCmd, map[string]string{"by": "1"})
+		captureOutput(func() { blockCmd.Run(blockCmd, []string{"2"}) })
+		resetFlags(t, blockCmd)
+		setFlags(t, blockCmd, map[string]string{"by": "2,4"})
+		output := captureOutput(func() { blockCmd.Run(blockCmd, []string{"3"}) })
+		if !strings.Contains(output, "ждёт задачи: 2, 4") {
+			t.Errorf("неожиданный вывод block: %s", output)
+		}
+
+		// Зависимость, замыкающая цикл, отклоняется с понятным путём
+		resetFlags(t, blockCmd)
+		setFlags(t, blockCmd, map[string]string{"by": "3"})
+		output = captureOutput(func() { blockCmd.Run(blockCmd, []string{"1"}) })
+		if !strings.Contains(output, `#1 "Backup" → #3 "Deploy" → #2 "Migrate" → #1 "Backup"`) {
+			t.Errorf("ожидалась ошибка цикла, получено: %s", output)
+		}
+		if got, _ := store.GetTask(1); len(got.BlockedBy) != 0 {
+			t.Errorf("зависимость с циклом не должна сохраняться: %+v", got)
+		}
+
+		// --ready показывает только задачи без открытых зависимостей
+		setFlags(t, listCmd, map[string]string{"ready": "true"})
+		output = captureOutput(func() { listCmd.Run(listCmd, []string{}) })
+		if !strings.Contains(output, "Backup") || !strings.Contains(output, "Announce") ||
+			strings.Contains(output, "Migrate") || strings.Contains(output, "Deploy") {
+			t.Errorf("неверный вывод list --ready:\n%s", output)
+		}
+		resetFlags(t, listCmd)
+		output = captureOutput(func() { listCmd.Run(listCmd, []string{}) })
+		if !strings.Contains(output, "ждёт: 2, 4") {
+			t.Errorf("в списке нет зависимостей задачи 3:\n%s", output)
+		}
+
+		// Заблокированную задачу закрыть нельзя без --force
+		output = captureOutput(func() { doneCmd.Run(doneCmd, []string{"2"}) })
+		if !strings.Contains(output, "ждёт невыполненные задачи: 1") {
+			t.Errorf("ожидался отказ, получено: %s", output)
+		}
+		setFlags(t, doneCmd, map[string]string{"force": "true"})
+		captureOutput(func() { doneCmd.Run(doneCmd, []string{"2"}) })
+		if got, _ := store.GetTask(2); got.Status != task.StatusDone {
+			t.Errorf("задача должна быть выполнена с --force: %+v", got)
+		}
+
+		// unblock без --by снимает все зависимости
+		captureOutput(func() { unblockCmd.Run(unblockCmd, []string{"2"}) })
+		if got, _ := store.GetTask(2); len(got.BlockedBy) != 0 {
+			t.Errorf("зависимости должны быть сняты: %+v", got)
+		}
+
+		// Удаление задачи убирает зависимости от неё
+		captureOutput(func() { deleteCmd.Run(deleteCmd, []string{"4"}) })
+		if got, _ := store.GetTask(3); fmt.Sprint(got.BlockedBy) != "[2]" {
+			t.Errorf("после удаления должна остаться зависимость только от 2: %+v", got)
+		}
+	})
+}
+
+// TestRecurCommands проверяет recur, индикатор в list и создание
+// следующего повторения командой done.
+func TestRecurCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		for _, c := range []*cobra.Command{doneCmd, listCmd} {
+			resetFlags(t, c)
+			defer resetFlags(t, c)
+		}
+
+		due := time.Now().AddDate(0, 0, -1).Truncate(24 * time.Hour)
+		if _, err := store.AddTask(task.Task{Title: "Weekly report", CreatedAt: time.Now(), DueAt: due}); err != nil {
+			t.Fatalf("AddTask вернул ошибку: %v", err)
+		}
+
+		output := captureOutput(func() { recurCmd.Run(recurCmd, []string{"1", "sometimes"}) })
+		if !strings.Contains(output, "не удалось разобрать правило повторения") {
+			t.Errorf("ожидалась ошибка разбора, получено: %s", output)
+		}
+
+		output = captureOutput(func() { recurCmd.Run(recurCmd, []string{"1", "Every Mon,Thu"}) })
+		if !strings.Contains(output, "повторяется: every mon,thu") {
+			t.Errorf("неожиданный вывод recur: %s", output)
+		}
+		output = captureOutput(func() { listCmd.Run(listCmd, []string{}) })
+		if !strings.Contains(output, "🔁 every mon,thu") {
+			t.Errorf("в списке нет индикатора повторения:\n%s", output)
+		}
+
+		output = captureOutput(func() { doneCmd.Run(doneCmd, []string{"1"}) })
+		if !strings.Contains(output, "Следующее повторение [2]: Weekly report") {
+			t.Errorf("ожидалось сообщение о следующем повторении, получено: %s", output)
+		}
+		next, err := store.GetTask(2)
+		if err != nil || next.Status == task.StatusDone || next.Recur.String() != "every mon,thu" || !next.DueAt.After(time.Now()) {
+			t.Errorf("неверное следующее повторение: %+v, %v", next, err)
+		}
+		if wd := next.DueAt.Weekday(); wd != time.Monday && wd != time.Thursday {
+			t.Errorf("срок повторения должен выпасть на пн или чт: %v", next.DueAt)
+		}
+
+		captureOutput(func() { recurCmd.Run(recurCmd, []string{"2", "none"}) })
+		if got, _ := store.GetTask(2); !got.Recur.IsZero() {
+			t.Errorf("правило повторения должно быть снято: %+v", got)
+		}
+	})
+}
+
+// TestTimeTrackingCommands проверяет start/stop, log-time, индикатор
+// таймера в list и отчёт timesheet.
+func TestTimeTrackingCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		for _, c := range []*cobra.Command{logTimeCmd, timesheetCmd, listCmd} {
+			resetFlags(t, c)
+			defer resetFlags(t, c)
+		}
+
+		for _, tt := range []task.Task{
+			{Title: "Deploy", Tags: []string{"infra"}},
+			{Title: "Review"},
+		} {
+			tt.CreatedAt = time.Now()
+			if _, err := store.AddTask(tt); err != nil {
+				t.Fatalf("AddTask вернул ошибку: %v", err)
+			}
+		}
+
+		output := captureOutput(func() { startCmd.Run(startCmd, []string{"1"}) })
+		if !strings.Contains(output, "Таймер запущен: [1] Deploy") {
+			t.Errorf("неожиданный вывод start: %s", output)
+		}
+
+		// Второй таймер запустить нельзя
+		output = captureOutput(func() { startCmd.Run(startCmd, []string{"2"}) })
+		if !strings.Contains(output, "уже запущен таймер задачи [1] Deploy") {
+			t.Errorf("ожидался отказ запустить второй таймер, получено: %s", output)
+		}
+
+		output = captureOutput(func() { listCmd.Run(listCmd, []string{}) })
+		if !strings.Contains(output, "⏱ 0m") {
+			t.Errorf("в списке нет идущего таймера:\n%s", output)
+		}
+
+		output = captureOutput(func() { stopCmd.Run(stopCmd, []string{}) })
+		if !strings.Contains(output, "Таймер остановлен: [1] Deploy") {
+			t.Errorf("неожиданный вывод stop: %s", output)
+		}
+		output = captureOutput(func() { stopCmd.Run(stopCmd, []string{}) })
+		if !strings.Contains(output, "Нет запущенного таймера") {
+			t.Errorf("неожиданный вывод повторного stop: %s", output)
+		}
+
+		output = captureOutput(func() { logTimeCmd.Run(logTimeCmd, []string{"1", "soon"}) })
+		if !strings.Contains(output, "некорректная длительность") {
+			t.Errorf("ожидалась ошибка длительности, получено: %s", output)
+		}
+		output = captureOutput(func() { logTimeCmd.Run(logTimeCmd, []string{"1", "1h30m"}) })
+		if !strings.Contains(output, "записано 1h30m (всего 1h30m)") {
+			t.Errorf("неожиданный вывод log-time: %s", output)
+		}
+		captureOutput(func() { logTimeCmd.Run(logTimeCmd, []string{"2", "45m"}) })
+
+		output = captureOutput(func() { timesheetCmd.Run(timesheetCmd, []string{}) })
+		for _, want := range []string{"за всё время", "[1] Deploy", "#infra", "(без тегов)", "Итого: 2h15m"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("в отчёте нет %q:\n%s", want, output)
+			}
+		}
+		if strings.Index(output, "[1] Deploy") > strings.Index(output, "[2] Review") {
+			t.Errorf("задачи должны идти от большего времени к меньшему:\n%s", output)
+		}
+
+		setFlags(t, timesheetCmd, map[string]string{"by": "tag"})
+		output = captureOutput(func() { timesheetCmd.Run(timesheetCmd, []string{}) })
+		if strings.Contains(output, "По задачам") || !strings.Contains(output, "По тегам") {
+			t.Errorf("ожидался только раздел по тегам:\n%s", output)
+		}
+
+		// Время задач, перенесённых в архив, остаётся в отчёте
+		resetFlags(t, timesheetCmd)
+		resetFlags(t, doneCmd)
+		defer resetFlags(t, doneCmd)
+		captureOutput(func() { doneCmd.Run(doneCmd, []string{"1"}) })
+		output = captureOutput(func() { archiveCmd.Run(archiveCmd, nil) })
+		if !strings.Contains(output, "Задача с ID 1 перенесена в архив.") {
+			t.Fatalf("задача 1 должна уйти в архив:\n%s", output)
+		}
+		setFlags(t, timesheetCmd, map[string]string{"week": "true"})
+		output = captureOutput(func() { timesheetCmd.Run(timesheetCmd, []string{}) })
+		if !strings.Contains(output, "[1] Deploy") || !strings.Contains(output, "Итого: 2h15m") {
+			t.Errorf("в отчёте за неделю нет времени архивной задачи:\n%s", output)
+		}
+	})
+}
+
+// TestEditAndShowCommands проверяет редактирование задачи через редактор
+// (с повторным открытием при ошибке) и подробный вывод show.
+func TestEditAndShowCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		if _, err := store.AddTask(task.Task{Title: "Отчёт", CreatedAt: time.Now(), Tags: []string{"work"}}); err != nil {
+			t.Fatalf("AddTask вернул ошибку: %v", err)
+		}
+
+		origEditor := runEditor
+		defer func() { runEditor = origEditor }()
+
+		// Редактор по очереди сохраняет заданные тексты и запоминает,
+		// что было в файле при открытии.
+		var opened []string
+		editorWrites := func(texts ...string) {
+			opened = nil
+			runEditor = func(path string) error {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				opened = append(opened, string(data))
+				return os.WriteFile(path, []byte(texts[len(opened)-1]), 0600)
+			}
+		}
+
+		// Номер строки в комментарии указывает на строку файла с комментарием:
+		// "priority" — четвёртая строка после строки с ошибкой
+		editorWrites(
+			"---\ntitle: Квартальный отчёт\npriority: urgent!\n---\n",
+			"# Ошибка: старая\n---\ntitle: Квартальный отчёт\npriority: urgent\ndue: завтрашний\n---\n",
+			"---\ntitle: Квартальный отчёт\npriority: urgent\ndue: 2030-01-15\n---\n\nСобрать цифры\n- выручка\n",
+		)
+		output := captureOutput(func() { editCmd.Run(editCmd, []string{"1"}) })
+		if len(opened) != 3 || !strings.Contains(opened[0], "title: Отчёт") {
+			t.Fatalf("редактор должен открыться трижды с задачей, получено: %q", opened)
+		}
+		for i, want := range map[int]string{1: "# Ошибка: строка 4: ", 2: "# Ошибка: строка 5: "} {
+			lines := strings.Split(opened[i], "\n")
+			if !strings.HasPrefix(opened[i], want) || strings.HasPrefix(lines[1], "#") {
+				t.Errorf("ошибка должна попасть в файл при повторном открытии: %q", opened[i])
+			}
+		}
+		if line := strings.Split(opened[2], "\n")[4]; !strings.HasPrefix(line, "due:") {
+			t.Errorf("строка 5 файла с ошибкой — %q, ожидалось поле due", line)
+		}
+		if !strings.Contains(output, "успешно обновлена") {
+			t.Errorf("задача должна обновиться: %s", output)
+		}
+		got, _ := store.GetTask(1)
+		if got.Title != "Квартальный отчёт" || got.Priority != task.PriorityUrgent ||
+			got.DueAt.Format("2006-01-02") != "2030-01-15" || got.Notes != "Собрать цифры\n- выручка" || len(got.Tags) != 1 {
+			t.Errorf("неверная задача после edit: %+v", got)
+		}
+
+		// Пустой файл отменяет редактирование
+		editorWrites("")
+		output = captureOutput(func() { editCmd.Run(editCmd, []string{"1"}) })
+		if !strings.Contains(output, "Редактирование отменено") {
+			t.Errorf("ожидалась отмена, получено: %s", output)
+		}
+
+		// Файл, не изменённый после ошибки, тоже отменяет редактирование
+		runEditor = func(path string) error {
+			data, _ := os.ReadFile(path)
+			if !strings.HasPrefix(string(data), "# Ошибка") {
+				return os.WriteFile(path, []byte("---\ntitle: x\ncolor: red\n---\n"), 0600)
+			}
+			return nil
+		}
+		output = captureOutput(func() { editCmd.Run(editCmd, []string{"1"}) })
+		if !strings.Contains(output, "неизвестное поле") || !strings.Contains(output, "Редактирование отменено") {
+			t.Errorf("ожидалась ошибка и отмена, получено: %s", output)
+		}
+
+		output = captureOutput(func() { showCmd.Run(showCmd, []string{"1"}) })
+		for _, want := range []string{"[1] 🚨 Квартальный отчёт", "urgent", "2030-01-15", "#work", "Заметки:", "  - выручка"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("в выводе show нет %q:\n%s", want, output)
+			}
+		}
+		output = captureOutput(func() { showCmd.Run(showCmd, []string{"7"}) })
+		if !strings.Contains(output, "не найдена") {
+			t.Errorf("ожидалась ошибка для несуществующей задачи, получено: %s", output)
+		}
+	})
+}
+
+// --- Тест команды status и фильтра по статусу ---
+func TestStatusCommand(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		origConfig := configDir
+		configDir = t.TempDir()
+		defer func() { configDir = origConfig }()
+
+		for _, tsk := range []task.Task{
+			{Title: "Деплой", CreatedAt: time.Now()},
+			{Title: "Ревью", CreatedAt: time.Now(), Recur: task.Recurrence{Freq: task.FreqDaily, Interval: 1}},
+			{Title: "Отменить", CreatedAt: time.Now()},
+		} {
+			if _, err := store.AddTask(tsk); err != nil {
+				t.Fatalf("AddTask вернул ошибку: %v", err)
+			}
+		}
+		resetFlags(t, statusCmd)
+
+		output := captureOutput(func() { statusCmd.Run(statusCmd, []string{"1", "in_progress"}) })
+		if got, _ := store.GetTask(1); got.Status != task.StatusInProgress || !strings.Contains(output, "todo → ") {
+			t.Errorf("ожидался статус in-progress, получено %s: %s", got.Status, output)
+		}
+
+		// Переход, запрещённый рабочим процессом, и его обход через --force
+		output = captureOutput(func() { statusCmd.Run(statusCmd, []string{"3", "review"}) })
+		if got, _ := store.GetTask(3); got.Status != task.StatusTodo || !strings.Contains(output, "запрещён") {
+			t.Errorf("ожидался отказ в переходе, получено %s: %s", got.Status, output)
+		}
+		setFlags(t, statusCmd, map[string]string{"force": "true"})
+		captureOutput(func() { statusCmd.Run(statusCmd, []string{"3", "review"}) })
+		if got, _ := store.GetTask(3); got.Status != task.StatusReview {
+			t.Errorf("--force должен разрешить переход, получено %s", got.Status)
+		}
+		resetFlags(t, statusCmd)
+
+		// Рабочий процесс из файла настроек: из review можно только отменить
+		if err := os.WriteFile(filepath.Join(configDir, workflowFile), []byte(`{"review":["cancelled"]}`), 0600); err != nil {
+			t.Fatalf("не удалось записать workflow.json: %v", err)
+		}
+		output = captureOutput(func() { statusCmd.Run(statusCmd, []string{"3", "done"}) })
+		if !strings.Contains(output, "можно перейти в: cancelled") {
+			t.Errorf("ожидался отказ по настроенному рабочему процессу, получено: %s", output)
+		}
+		captureOutput(func() { statusCmd.Run(statusCmd, []string{"3", "cancelled"}) })
+
+		// Выполнение повторяющейся задачи создаёт следующее повторение
+		output = captureOutput(func() { statusCmd.Run(statusCmd, []string{"2", "done"}) })
+		if next, err := store.GetTask(4); err != nil || next.Status != task.StatusTodo || !strings.Contains(output, "Следующее повторение [4]") {
+			t.Errorf("ожидалось следующее повторение, получено %+v, %v: %s", next, err, output)
+		}
+
+		output = captureOutput(func() { statusCmd.Run(statusCmd, []string{"1", "paused"}) })
+		if !strings.Contains(output, "неизвестный статус") {
+			t.Errorf("ожидалась ошибка для неизвестного статуса, получено: %s", output)
+		}
+
+		// Фильтр списка по статусам; отменённые задачи не считаются открытыми
+		resetFlags(t, listCmd)
+		setFlags(t, listCmd, map[string]string{"filter": "in-progress,cancelled"})
+		output = captureOutput(func() { listCmd.Run(listCmd, nil) })
+		if !strings.Contains(output, "Деплой") || !strings.Contains(output, "Отменить") || strings.Contains(output, "Ревью") {
+			t.Errorf("неверный список по статусам:\n%s", output)
+		}
+		setFlags(t, listCmd, map[string]string{"filter": "pending"})
+		output = captureOutput(func() { listCmd.Run(listCmd, nil) })
+		if !strings.Contains(output, "Деплой") || strings.Contains(output, "Отменить") || !strings.Contains(output, "🔄") {
+			t.Errorf("неверный список незавершённых задач:\n%s", output)
+		}
+		resetFlags(t, listCmd)
+	})
+}
+
+// --- Тест правил done для todo status ---
+// status done и cancelled подчиняются тем же проверкам, что и todo done:
+// зависимости (--force) и невыполненные подзадачи (--cascade).
+func TestStatusCommand_DoneRules(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		origConfig := configDir
+		configDir = t.TempDir()
+		defer func() { configDir = origConfig }()
+
+		for _, tsk := range []task.Task{
+			{Title: "Ждёт вторую", BlockedBy: []int{2}},
+			{Title: "Блокирующая"},
+			{Title: "Родитель"},
+			{Title: "Подзадача", ParentID: 3},
+		} {
+			tsk.CreatedAt = time.Now()
+			if _, err := store.AddTask(tsk); err != nil {
+				t.Fatalf("AddTask вернул ошибку: %v", err)
+			}
+		}
+		resetFlags(t, statusCmd)
+		defer resetFlags(t, statusCmd)
+
+		// Задачу, которая ждёт другую, не выполнить ни done, ни status done
+		resetFlags(t, doneCmd)
+		doneOutput := captureOutput(func() { doneCmd.Run(doneCmd, []string{"1"}) })
+		output := captureOutput(func() { statusCmd.Run(statusCmd, []string{"1", "done"}) })
+		if got, _ := store.GetTask(1); got.Status != task.StatusTodo ||
+			!strings.Contains(doneOutput, "ждёт невыполненные задачи: 2") || !strings.Contains(output, "ждёт невыполненные задачи: 2") {
+			t.Errorf("status done должен отказать, как done, получено %s:\n%s\n%s", got.Status, doneOutput, output)
+		}
+		setFlags(t, statusCmd, map[string]string{"force": "true"})
+		captureOutput(func() { statusCmd.Run(statusCmd, []string{"1", "done"}) })
+		if got, _ := store.GetTask(1); got.Status != task.StatusDone || got.CompletedAt.IsZero() {
+			t.Errorf("--force должен выполнить задачу, получено %+v", got)
+		}
+		resetFlags(t, statusCmd)
+
+		// Родителя с невыполненной подзадачей нельзя выполнить или отменить без --cascade
+		for _, to := range []string{"done", "cancelled"} {
+			output = captureOutput(func() { statusCmd.Run(statusCmd, []string{"3", to}) })
+			if got, _ := store.GetTask(3); got.Status != task.StatusTodo || !strings.Contains(output, "невыполненные подзадачи: 4") {
+				t.Errorf("status %s должен отказать для родителя, получено %s: %s", to, got.Status, output)
+			}
+		}
+		setFlags(t, statusCmd, map[string]string{"cascade": "true"})
+		output = captureOutput(func() { statusCmd.Run(statusCmd, []string{"3", "cancelled"}) })
+		parent, _ := store.GetTask(3)
+		child, _ := store.GetTask(4)
+		if parent.Status != task.StatusCancelled || child.Status != task.StatusCancelled || !strings.Contains(output, "подзадачи: 4") {
+			t.Errorf("--cascade должен отменить задачу с подзадачей, получено %s и %s: %s", parent.Status, child.Status, output)
+		}
+	})
+}
+
+// --- Тест времени изменения и выполнения задач ---
+func TestTimestampsCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		origConfig := configDir
+		configDir = t.TempDir()
+		defer func() { configDir = origConfig }()
+
+		captureOutput(func() {
+			addCmd.Run(addCmd, []string{"Первая"})
+			addCmd.Run(addCmd, []string{"Вторая"})
+			addCmd.Run(addCmd, []string{"Третья"})
+			doneCmd.Run(doneCmd, []string{"2"})
+			completeAllCmd.Run(completeAllCmd, nil)
+		})
+		tasks, _ := store.ListTasks()
+		for _, tsk := range tasks {
+			if tsk.UpdatedAt.IsZero() || tsk.CompletedAt.IsZero() {
+				t.Fatalf("ожидались отметки времени у всех задач: %+v", tsk)
+			}
+		}
+
+		resetFlags(t, listCmd)
+		setFlags(t, listCmd, map[string]string{"long": "true", "sort": "completed"})
+		output := captureOutput(func() { listCmd.Run(listCmd, nil) })
+		resetFlags(t, listCmd)
+		if !strings.Contains(output, "COMPLETED AT") || strings.Index(output, "Вторая") > strings.Index(output, "Первая") {
+			t.Errorf("ожидался подробный список, отсортированный по выполнению:\n%s", output)
+		}
+
+		// Возврат в работу сбрасывает время выполнения
+		resetFlags(t, statusCmd)
+		captureOutput(func() { statusCmd.Run(statusCmd, []string{"2", "todo"}) })
+		if got, _ := store.GetTask(2); !got.CompletedAt.IsZero() || !got.UpdatedAt.After(tasks[1].UpdatedAt) {
+			t.Errorf("ожидалось сброшенное время выполнения: %+v", got)
+		}
+		output = captureOutput(func() { showCmd.Run(showCmd, []string{"1"}) })
+		if !strings.Contains(output, "Изменена:") || !strings.Contains(output, "Выполнена:") {
+			t.Errorf("в выводе show нет отметок времени:\n%s", output)
+		}
+	})
+}
+
+// --- Тест массовых операций done, reopen и delete ---
+func TestBulkCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		for i, tags := range [][]string{{"sprint"}, nil, {"sprint"}, nil, {"draft"}, {"draft"}} {
+			if _, err := store.AddTask(task.Task{Title: fmt.Sprintf("Задача %d", i+1), CreatedAt: time.Now(), Tags: tags}); err != nil {
+				t.Fatalf("AddTask вернул ошибку: %v", err)
+			}
+		}
+		for _, c := range []*cobra.Command{doneCmd, reopenCmd, deleteCmd} {
+			resetFlags(t, c)
+		}
+		statuses := func() string {
+			tasks, _ := store.ListTasks()
+			var parts []string
+			for _, tsk := range tasks {
+				parts = append(parts, fmt.Sprintf("%d:%s", tsk.ID, tsk.Status))
+			}
+			return strings.Join(parts, " ")
+		}
+
+		output := captureOutput(func() { doneCmd.Run(doneCmd, []string{"1", "3-4,9"}) })
+		if !strings.Contains(output, "Отмечено как выполненные задач: 3 (1, 3, 4)") || !strings.Contains(output, "Не найдены задачи: 9") {
+			t.Errorf("неверный итог done:\n%s", output)
+		}
+		if got := statuses(); got != "1:done 2:todo 3:done 4:done 5:todo 6:todo" {
+			t.Errorf("неверные статусы после done: %s", got)
+		}
+
+		// Фильтры выбирают задачи без ID; уже открытые задачи пропускаются
+		setFlags(t, reopenCmd, map[string]string{"tag": "sprint"})
+		output = captureOutput(func() { reopenCmd.Run(reopenCmd, []string{"1-2"}) })
+		if !strings.Contains(output, "Задача с ID 1 снова в работе") || strings.Contains(output, "Уже в работе") {
+			t.Errorf("неверный итог reopen по ID и тегу:\n%s", output)
+		}
+		resetFlags(t, reopenCmd)
+		setFlags(t, reopenCmd, map[string]string{"filter": "completed"})
+		output = captureOutput(func() { reopenCmd.Run(reopenCmd, nil) })
+		if !strings.Contains(output, "Возвращено в работу задач: 2 (3, 4)") {
+			t.Errorf("неверный итог reopen по фильтру:\n%s", output)
+		}
+		resetFlags(t, reopenCmd)
+		if got, _ := store.GetTask(3); !got.CompletedAt.IsZero() {
+			t.Errorf("reopen должен сбросить время выполнения: %+v", got)
+		}
+
+		// Операция атомарна: если одну задачу закрыть нельзя, не закрывается ни одна
+		if err := store.UpdateTask(task.Task{ID: 2, Title: "Задача 2", BlockedBy: []int{5}}); err != nil {
+			t.Fatalf("UpdateTask вернул ошибку: %v", err)
+		}
+		output = captureOutput(func() { doneCmd.Run(doneCmd, []string{"1-3"}) })
+		if !strings.Contains(output, "ждут невыполненные задачи: 5") || statuses() != "1:todo 2:todo 3:todo 4:todo 5:todo 6:todo" {
+			t.Errorf("ожидался отказ без изменений, получено %s:\n%s", statuses(), output)
+		}
+
+		setFlags(t, deleteCmd, map[string]string{"tag": "draft"})
+		output = captureOutput(func() { deleteCmd.Run(deleteCmd, nil) })
+		resetFlags(t, deleteCmd)
+		if !strings.Contains(output, "Удалено задач: 2 (5, 6)") {
+			t.Errorf("неверный итог delete:\n%s", output)
+		}
+		if got, _ := store.GetTask(2); len(got.BlockedBy) != 0 {
+			t.Errorf("удаление должно снять зависимости от удалённых задач: %+v", got)
+		}
+
+		for args, want := range map[string]string{
+			"7-3": "Некорректный ID задачи: 7-3",
+			"8 9": "задачи не найдены: 8, 9",
+			"":    "укажите ID задач",
+		} {
+			output = captureOutput(func() { doneCmd.Run(doneCmd, strings.Fields(args)) })
+			if !strings.Contains(output, want) {
+				t.Errorf("done %s: ожидалось %q, получено: %s", args, want, output)
+			}
+		}
+	})
+}
+
+// TestTrashCommands проверяет корзину: delete и clear перемещают задачи
+// в корзину, list их не показывает, trash restore возвращает, purge удаляет.
+func TestTrashCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		for i := 1; i <= 3; i++ {
+			if _, err := store.AddTask(task.Task{Title: fmt.Sprintf("Задача %d", i), CreatedAt: time.Now()}); err != nil {
+				t.Fatalf("AddTask вернул ошибку: %v", err)
+			}
+		}
+		if err := store.UpdateTask(task.Task{ID: 3, Title: "Задача 3", Status: task.StatusDone}); err != nil {
+			t.Fatalf("UpdateTask вернул ошибку: %v", err)
+		}
+
+		output := captureOutput(func() { deleteCmd.Run(deleteCmd, []string{"1"}) })
+		if !strings.Contains(output, "todo trash list") {
+			t.Errorf("delete должен подсказать про корзину:\n%s", output)
+		}
+		captureOutput(func() { clearCmd.Run(clearCmd, nil) })
+
+		resetFlags(t, listCmd)
+		output = captureOutput(func() { listCmd.Run(listCmd, nil) })
+		if strings.Contains(output, "Задача 1") || strings.Contains(output, "Задача 3") {
+			t.Errorf("list не должен показывать задачи из корзины:\n%s", output)
+		}
+
+		output = captureOutput(func() { trashListCmd.Run(trashListCmd, nil) })
+		if !strings.Contains(output, "Задача 1") || !strings.Contains(output, "Задача 3") ||
+			strings.Index(output, "Задача 3") > strings.Index(output, "Задача 1") {
+			t.Errorf("trash list должен показать задачи 3 и 1, недавние первыми:\n%s", output)
+		}
+
+		output = captureOutput(func() { trashRestoreCmd.Run(trashRestoreCmd, []string{"1"}) })
+		if !strings.Contains(output, "Задача с ID 1 восстановлена") {
+			t.Errorf("неверный вывод trash restore:\n%s", output)
+		}
+		if got, err := store.GetTask(1); err != nil || got.Title != "Задача 1" {
+			t.Errorf("задача 1 должна вернуться в список: %+v, %v", got, err)
+		}
+		output = captureOutput(func() { trashRestoreCmd.Run(trashRestoreCmd, []string{"1"}) })
+		if !strings.Contains(output, "задачи с ID 1 нет в корзине") {
+			t.Errorf("ожидалась ошибка повторного восстановления:\n%s", output)
+		}
+
+		// Свежие задачи --older-than не трогает
+		setFlags(t, trashPurgeCmd, map[string]string{"older-than": "30d"})
+		output = captureOutput(func() { trashPurgeCmd.Run(trashPurgeCmd, nil) })
+		if !strings.Contains(output, "Нет задач для окончательного удаления") {
+			t.Errorf("purge --older-than=30d не должен удалять свежие задачи:\n%s", output)
+		}
+		setFlags(t, trashPurgeCmd, map[string]string{"older-than": "месяц"})
+		output = captureOutput(func() { trashPurgeCmd.Run(trashPurgeCmd, nil) })
+		if !strings.Contains(output, "Ошибка в --older-than") {
+			t.Errorf("ожидалась ошибка разбора --older-than:\n%s", output)
+		}
+		resetFlags(t, trashPurgeCmd)
+		output = captureOutput(func() { trashPurgeCmd.Run(trashPurgeCmd, nil) })
+		if !strings.Contains(output, "Окончательно удалено задач: 1") {
+			t.Errorf("неверный вывод trash purge:\n%s", output)
+		}
+		output = captureOutput(func() { trashListCmd.Run(trashListCmd, nil) })
+		if !strings.Contains(output, "Корзина пуста") {
+			t.Errorf("после purge корзина должна быть пуста:\n%s", output)
+		}
+	})
+}
+
+// TestArchiveCommands проверяет перенос завершённых задач в архив
+// и поиск по нему через list --archived и search --include-archive.
+func TestArchiveCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		now := time.Now()
+		tasks := []task.Task{
+			{ID: 1, Title: "Старый релиз", Status: task.StatusDone, CompletedAt: now.AddDate(0, 0, -20)},
+			{ID: 2, Title: "Свежий релиз", Status: task.StatusDone, CompletedAt: now.AddDate(0, 0, -1)},
+			{ID: 3, Title: "Отменённый релиз", Status: task.StatusCancelled, UpdatedAt: now.AddDate(0, 0, -30)},
+			{ID: 4, Title: "Подзадача в работе", ParentID: 3},
+			{ID: 5, Title: "Текущий релиз"},
+		}
+		if err := store.OverwriteTasks(tasks); err != nil {
+			t.Fatalf("OverwriteTasks вернул ошибку: %v", err)
+		}
+
+		// Задача 3 остаётся: её подзадача не завершена
+		setFlags(t, archiveCmd, map[string]string{"older-than": "14d"})
+		output := captureOutput(func() { archiveCmd.Run(archiveCmd, nil) })
+		resetFlags(t, archiveCmd)
+		if !strings.Contains(output, "Задача с ID 1 перенесена в архив.") {
+			t.Errorf("неверный итог archive --older-than=14d:\n%s", output)
+		}
+		if entries, _ := os.ReadDir(tmpFile + ".archive"); len(entries) != 1 {
+			t.Errorf("ожидался один файл месяца в архиве, получено %d", len(entries))
+		}
+		if data, _ := os.ReadFile(tmpFile + ".trash"); strings.Contains(string(data), "Старый релиз") {
+			t.Errorf("архивация не должна класть задачи в корзину:\n%s", data)
+		}
+
+		output = captureOutput(func() { archiveCmd.Run(archiveCmd, nil) })
+		if !strings.Contains(output, "Задача с ID 2 перенесена в архив.") {
+			t.Errorf("неверный итог archive:\n%s", output)
+		}
+
+		resetFlags(t, listCmd)
+		output = captureOutput(func() { listCmd.Run(listCmd, nil) })
+		if strings.Contains(output, "Старый релиз") || !strings.Contains(output, "Текущий релиз") {
+			t.Errorf("list не должен показывать задачи архива:\n%s", output)
+		}
+		setFlags(t, listCmd, map[string]string{"archived": "true"})
+		output = captureOutput(func() { listCmd.Run(listCmd, nil) })
+		resetFlags(t, listCmd)
+		if !strings.Contains(output, "Старый релиз") || !strings.Contains(output, "Свежий релиз") || strings.Contains(output, "Текущий релиз") {
+			t.Errorf("list --archived должен показать только задачи архива:\n%s", output)
+		}
+
+		resetFlags(t, searchCmd)
+		output = captureOutput(func() { searchCmd.Run(searchCmd, []string{"релиз"}) })
+		if strings.Contains(output, "Старый релиз") {
+			t.Errorf("search без --include-archive не должен искать в архиве:\n%s", output)
+		}
+		setFlags(t, searchCmd, map[string]string{"include-archive": "true"})
+		output = captureOutput(func() { searchCmd.Run(searchCmd, []string{"релиз"}) })
+		resetFlags(t, searchCmd)
+		if !strings.Contains(output, "Старый релиз \033[90m(в архиве)") || !strings.Contains(output, "Текущий релиз") {
+			t.Errorf("search --include-archive должен найти задачи архива:\n%s", output)
+		}
+	})
+}
+
+// TestQueryCommands проверяет язык запросов в list --query и search.
+func TestQueryCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		tasks := []task.Task{
+			{ID: 1, Title: "Deploy API", Tags: []string{"infra"}, DueAt: time.Now().AddDate(0, 0, 2)},
+			{ID: 2, Title: "Deploy сайта", Priority: task.PriorityHigh},
+			{ID: 3, Title: "Купить хлеб", Priority: task.PriorityHigh},
+			{ID: 4, Title: "Deploy базы", Tags: []string{"infra"}, Status: task.StatusDone},
+		}
+		if err := store.OverwriteTasks(tasks); err != nil {
+			t.Fatalf("OverwriteTasks вернул ошибку: %v", err)
+		}
+
+		resetFlags(t, listCmd)
+		setFlags(t, listCmd, map[string]string{"query": `status:pending and (tag:infra or priority>=high) and title~"deploy"`})
+		output := captureOutput(func() { listCmd.Run(listCmd, nil) })
+		resetFlags(t, listCmd)
+		if !strings.Contains(output, "Deploy API") || !strings.Contains(output, "Deploy сайта") ||
+			strings.Contains(output, "Купить хлеб") || strings.Contains(output, "Deploy базы") {
+			t.Errorf("list --query вернул неверные задачи:\n%s", output)
+		}
+
+		setFlags(t, listCmd, map[string]string{"query": "tag:infra and priority>=срочно"})
+		output = captureOutput(func() { listCmd.Run(listCmd, nil) })
+		resetFlags(t, listCmd)
+		if !strings.Contains(output, "Ошибка в --query: столбец 25") || !strings.Contains(output, strings.Repeat(" ", 26)+"^") {
+			t.Errorf("ошибка разбора должна указывать на столбец:\n%s", output)
+		}
+
+		// Простое слово ищется в названии, как раньше
+		resetFlags(t, searchCmd)
+		output = captureOutput(func() { searchCmd.Run(searchCmd, []string{"хлеб"}) })
+		if !strings.Contains(output, "Купить хлеб") || strings.Contains(output, "Deploy") {
+			t.Errorf("неверный результат search хлеб:\n%s", output)
+		}
+		setFlags(t, searchCmd, map[string]string{"query": "status:done"})
+		output = captureOutput(func() { searchCmd.Run(searchCmd, []string{"deploy"}) })
+		if !strings.Contains(output, "Deploy базы") || strings.Contains(output, "Deploy API") {
+			t.Errorf("неверный результат search с запросом:\n%s", output)
+		}
+		setFlags(t, searchCmd, map[string]string{"query": "(deploy"})
+		output = captureOutput(func() { searchCmd.Run(searchCmd, nil) })
+		if !strings.Contains(output, "Ошибка в --query: столбец 1: незакрытая скобка") {
+			t.Errorf("ожидалась ошибка разбора запроса:\n%s", output)
+		}
+		resetFlags(t, searchCmd)
+	})
+}
+
+// TestSearchPlainText проверяет, что текст поиска без --query ищется
+// в названии целиком: двоеточия, скобки, кавычки и and/or/not — обычные символы.
+func TestSearchPlainText(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		for _, title := range []string{"fix: login", "Fix login page", "rock and roll", "Rock", `say "hi" (later)`} {
+			if _, err := store.AddTask(task.Task{Title: title, CreatedAt: time.Now()}); err != nil {
+				t.Fatalf("AddTask вернул ошибку: %v", err)
+			}
+		}
+		resetFlags(t, searchCmd)
+		defer resetFlags(t, searchCmd)
+
+		for _, tt := range []struct {
+			args []string
+			want []string
+			skip []string
+		}{
+			{[]string{"Fix: Login"}, []string{"fix: login"}, []string{"Fix login page"}},
+			{[]string{"rock", "and", "roll"}, []string{"rock and roll"}, []string{"] 4: Rock"}},
+			{[]string{`"hi" (later`}, []string{`say "hi" (later)`}, nil},
+			{[]string{"not done"}, nil, []string{"fix", "rock"}},
+		} {
+			output := captureOutput(func() { searchCmd.Run(searchCmd, tt.args) })
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("search %q: ожидалось %q:\n%s", tt.args, want, output)
+				}
+			}
+			for _, skip := range tt.skip {
+				if strings.Contains(output, skip) {
+					t.Errorf("search %q: не ожидалось %q:\n%s", tt.args, skip, output)
+				}
+			}
+			if strings.Contains(output, "Ошибка") {
+				t.Errorf("search %q: текст не должен разбираться как запрос:\n%s", tt.args, output)
+			}
+		}
+
+		output := captureOutput(func() { searchCmd.Run(searchCmd, nil) })
+		if !strings.Contains(output, "укажите текст для поиска или запрос --query") {
+			t.Errorf("ожидалась ошибка без текста и запроса:\n%s", output)
+		}
+	})
+}
+
+// TestViewCommands проверяет сохранение представлений, их запуск через
+// "todo <имя>" и "list --view" и приоритет флагов командной строки.
+func TestViewCommands(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		for _, tk := range []task.Task{
+			{Title: "Деплой", Project: "work", Tags: []string{"infra"}},
+			{Title: "Отчёт", Project: "work", Status: task.StatusDone},
+			{Title: "Хлеб"},
+		} {
+			tk.CreatedAt = time.Now()
+			if _, err := store.AddTask(tk); err != nil {
+				t.Fatalf("AddTask вернул ошибку: %v", err)
+			}
+		}
+		defer resetFlags(t, listCmd)
+		defer resetFlags(t, viewSaveCmd)
+
+		output := captureOutput(func() { viewListCmd.Run(viewListCmd, nil) })
+		if !strings.Contains(output, "Нет сохранённых представлений") {
+			t.Errorf("неверный вывод view list без представлений:\n%s", output)
+		}
+
+		// Запрос словами после имени и флаги list
+		resetFlags(t, viewSaveCmd)
+		setFlags(t, viewSaveCmd, map[string]string{"filter": "pending", "tag": "infra"})
+		output = captureOutput(func() { viewSaveCmd.Run(viewSaveCmd, []string{"work", "project:work"}) })
+		if !strings.Contains(output, `Представление "work" сохранено: todo list --filter=pending --query=project:work --tag=infra`) {
+			t.Errorf("неверный вывод view save:\n%s", output)
+		}
+		if _, err := os.Stat(tmpFile + ".views"); err != nil {
+			t.Errorf("представления должны лежать рядом с хранилищем: %v", err)
+		}
+
+		for _, tt := range []struct {
+			name  string
+			flags map[string]string
+			args  []string
+			want  string
+		}{
+			{"занятое имя", nil, []string{"list", "tag:x"}, `имя "list" занято командой todo list`},
+			{"без флагов", nil, []string{"empty"}, "укажите запрос или флаги list"},
+			{"ошибка запроса", nil, []string{"bad", "(tag:x"}, "Ошибка в запросе: столбец 1: незакрытая скобка"},
+			{"два запроса", map[string]string{"query": "tag:x"}, []string{"both", "tag:y"}, "либо после имени, либо через --query"},
+		} {
+			resetFlags(t, viewSaveCmd)
+			setFlags(t, viewSaveCmd, tt.flags)
+			output = captureOutput(func() { viewSaveCmd.Run(viewSaveCmd, tt.args) })
+			if !strings.Contains(output, tt.want) {
+				t.Errorf("%s: ожидалось %q:\n%s", tt.name, tt.want, output)
+			}
+		}
+
+		resetFlags(t, listCmd)
+		output = captureOutput(func() { _ = rootCmd.RunE(rootCmd, []string{"work"}) })
+		if !strings.Contains(output, "Деплой") || strings.Contains(output, "Отчёт") || strings.Contains(output, "Хлеб") {
+			t.Errorf("todo work должен показать только задачу Деплой:\n%s", output)
+		}
+
+		// Флаг командной строки важнее флага представления
+		resetFlags(t, listCmd)
+		setFlags(t, listCmd, map[string]string{"view": "work", "filter": "all", "tag": "-infra"})
+		output = captureOutput(func() { listCmd.Run(listCmd, nil) })
+		if !strings.Contains(output, "Отчёт") || strings.Contains(output, "Деплой") || strings.Contains(output, "Хлеб") {
+			t.Errorf("list --view=work --filter=all --tag=-infra должен показать только Отчёт:\n%s", output)
+		}
+
+		resetFlags(t, listCmd)
+		if err := rootCmd.RunE(rootCmd, []string{"wrok"}); err == nil || !strings.Contains(err.Error(), `неизвестная команда или представление "wrok"`) {
+			t.Errorf("ожидалась ошибка неизвестного представления, получено %v", err)
+		}
+
+		suggestions, _ := completeViewNames(rootCmd, nil, "")
+		if len(suggestions) != 1 || suggestions[0] != "work" {
+			t.Errorf("автодополнение должно предложить work, получено %v", suggestions)
+		}
+
+		output = captureOutput(func() { viewListCmd.Run(viewListCmd, nil) })
+		if !strings.Contains(output, "work") || !strings.Contains(output, "--query=project:work") {
+			t.Errorf("неверный вывод view list:\n%s", output)
+		}
+		output = captureOutput(func() { viewDeleteCmd.Run(viewDeleteCmd, []string{"work"}) })
+		if !strings.Contains(output, `Представление "work" удалено`) {
+			t.Errorf("неверный вывод view delete:\n%s", output)
+		}
+		output = captureOutput(func() { viewDeleteCmd.Run(viewDeleteCmd, []string{"work"}) })
+		if !strings.Contains(output, `представление "work" не найдено`) {
+			t.Errorf("ожидалась ошибка удаления несуществующего представления:\n%s", output)
+		}
+	})
+}
+
+// TestSortFlag проверяет сортировку по нескольким ключам с направлением
+// в list, pending, completed и search, прежние имена полей и ошибки разбора.
+func TestSortFlag(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		due := time.Now().AddDate(0, 0, 3)
+		for _, tk := range []task.Task{
+			{Title: "Яблоко задача", Priority: task.PriorityHigh},
+			{Title: "ёлка задача", Priority: task.PriorityHigh, DueAt: due},
+			{Title: "Арбуз задача", Priority: task.PriorityHigh},
+			{Title: "Жук задача", Priority: task.PriorityUrgent},
+			{Title: "Дыня задача", Status: task.StatusDone},
+		} {
+			tk.CreatedAt = time.Now()
+			if _, err := store.AddTask(tk); err != nil {
+				t.Fatalf("AddTask вернул ошибку: %v", err)
+			}
+		}
+		defer resetFlags(t, listCmd)
+		defer resetFlags(t, pendingCmd)
+		defer resetFlags(t, completedCmd)
+		defer resetFlags(t, searchCmd)
+
+		// order проверяет, что названия идут в выводе в указанном порядке
+		order := func(name, output string, titles ...string) {
+			t.Helper()
+			last := -1
+			for _, title := range titles {
+				i := strings.Index(output, title)
+				if i < 0 || i < last {
+					t.Errorf("%s: ожидался порядок %v:\n%s", name, titles, output)
+					return
+				}
+				last = i
+			}
+		}
+
+		for _, tt := range []struct {
+			cmd  *cobra.Command
+			args []string
+			sort string
+			want []string
+		}{
+			{listCmd, nil, "-priority,due,title", []string{"Жук", "ёлка", "Арбуз", "Яблоко", "Дыня"}},
+			{listCmd, nil, "name", []string{"Арбуз", "Дыня", "ёлка", "Жук", "Яблоко"}},
+			{listCmd, nil, "-title", []string{"Яблоко", "Жук", "ёлка", "Дыня", "Арбуз"}},
+			{pendingCmd, nil, "priority,-title", []string{"Яблоко", "ёлка", "Арбуз", "Жук"}},
+			{searchCmd, []string{"задача"}, "title", []string{"Арбуз", "Дыня", "ёлка", "Жук", "Яблоко"}},
+		} {
+			resetFlags(t, tt.cmd)
+			setFlags(t, tt.cmd, map[string]string{"sort": tt.sort})
+			output := captureOutput(func() { tt.cmd.Run(tt.cmd, tt.args) })
+			order(tt.cmd.Name()+" --sort="+tt.sort, output, tt.want...)
+		}
+
+		for _, c := range []*cobra.Command{listCmd, pendingCmd, completedCmd, searchCmd} {
+			resetFlags(t, c)
+			setFlags(t, c, map[string]string{"sort": "priority,colour"})
+			output := captureOutput(func() { c.Run(c, []string{"задача"}) })
+			if !strings.Contains(output, `неизвестное поле сортировки "colour"`) {
+				t.Errorf("%s: ожидалась ошибка --sort:\n%s", c.Name(), output)
+			}
+		}
+
+		suggestions, _ := completeSortKeys(listCmd, nil, "-priority,d")
+		if !slices.Contains(suggestions, "-priority,due") || !slices.Contains(suggestions, "-priority,-due") ||
+			slices.Contains(suggestions, "-priority,priority") {
+			t.Errorf("неверное автодополнение --sort: %v", suggestions)
+		}
+	})
+}
+
+// executeRoot запускает CLI целиком с аргументами args, как из терминала,
+// и возвращает stdout. Глобальный флаг --output и состояние вывода
+// после запуска сбрасываются.
+func executeRoot(t *testing.T, args ...string) string {
+	t.Helper()
+	defer func() {
+		outputFlag = "text"
+		out = outputState{}
+		rootCmd.SetArgs(nil)
+	}()
+
+	rootCmd.SetArgs(args)
+	return captureOutput(func() {
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("Execute(%v) вернул ошибку: %v", args, err)
+		}
+	})
+}
+
+// TestExecute_UnknownCommand проверяет, что опечатка в имени команды
+// завершает todo с кодом 1 и подсказкой. Execute вызывает os.Exit,
+// поэтому тест запускает сам себя в отдельном процессе.
+func TestExecute_UnknownCommand(t *testing.T) {
+	if os.Getenv("TODO_TEST_EXECUTE") == "1" {
+		os.Args = []string{"todo", "--file", filepath.Join(t.TempDir(), "tasks.json"), "lsit"}
+		Execute()
+		return
+	}
+
+	c := exec.Command(os.Args[0], "-test.run=^TestExecute_UnknownCommand$")
+	c.Env = append(os.Environ(), "TODO_TEST_EXECUTE=1")
+	output, err := c.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("ожидался код выхода 1, получено %v:\n%s", err, output)
+	}
+	if !strings.Contains(string(output), `неизвестная команда или представление "lsit"`) ||
+		!regexp.MustCompile(`Возможно, вы имели в виду: .*\blist\b`).Match(output) {
+		t.Errorf("неверное сообщение об ошибке:\n%s", output)
+	}
+}
+
+// TestOutputFlag проверяет машиночитаемый вывод: записи о задачах для
+// list, show и search, итог изменяющих команд и ошибку с outputFailed.
+func TestOutputFlag(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		for _, title := range []string{"Хлеб", "Молоко"} {
+			if _, err := store.AddTask(task.Task{Title: title, CreatedAt: time.Now(), Tags: []string{"shop"}}); err != nil {
+				t.Fatalf("AddTask вернул ошибку: %v", err)
+			}
+		}
+		defer resetFlags(t, listCmd)
+		defer resetFlags(t, doneCmd)
+		defer resetFlags(t, searchCmd)
+
+		var records []output.Task
+		stdout := executeRoot(t, "--file", tmpFile, "list", "--output=json", "--sort=-id")
+		if err := json.Unmarshal([]byte(stdout), &records); err != nil {
+			t.Fatalf("list --output=json вывел не JSON: %v\n%s", err, stdout)
+		}
+		if len(records) != 2 || records[0].Title != "Молоко" || records[0].Tags[0] != "shop" || records[1].Status != "todo" {
+			t.Errorf("неверные записи list: %+v", records)
+		}
+
+		stdout = executeRoot(t, "--file", tmpFile, "-o", "csv", "search", "хлеб")
+		if lines := strings.Split(strings.TrimSpace(stdout), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[0], "id,title,status") ||
+			!strings.HasPrefix(lines[1], "1,Хлеб,todo") {
+			t.Errorf("неверный CSV search:\n%s", stdout)
+		}
+
+		var record output.Task
+		stdout = executeRoot(t, "--file", tmpFile, "-o", "json", "show", "2")
+		if err := json.Unmarshal([]byte(stdout), &record); err != nil || record.ID != 2 {
+			t.Errorf("неверный вывод show --output=json: %v\n%s", err, stdout)
+		}
+
+		var result output.Result
+		stdout = executeRoot(t, "--file", tmpFile, "-o", "json", "done", "1")
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("done --output=json вывел не JSON: %v\n%s", err, stdout)
+		}
+		if result.Command != "done" || !result.OK || len(result.Tasks) != 1 || result.Tasks[0].Status != "done" || result.Tasks[0].CompletedAt == "" {
+			t.Errorf("неверный итог done: %+v", result)
+		}
+
+		stdout = executeRoot(t, "--file", tmpFile, "-o", "yaml", "delete", "2")
+		if !strings.Contains(stdout, "command: delete") || !strings.Contains(stdout, "removed:\n  - id: 2") {
+			t.Errorf("неверный итог delete в YAML:\n%s", stdout)
+		}
+
+		// Ошибка команды попадает в итог, а не в stdout текстом
+		result = output.Result{}
+		rootCmd.SetArgs([]string{"--file", tmpFile, "-o", "json", "done", "99"})
+		stdout = captureOutput(func() {
+			if err := rootCmd.Execute(); err != nil {
+				t.Errorf("Execute вернул ошибку: %v", err)
+			}
+		})
+		failed := outputFailed()
+		outputFlag, out = "text", outputState{}
+		rootCmd.SetArgs(nil)
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil || result.OK || result.Error != "задача с ID 99 не найдена" {
+			t.Errorf("ожидалась ошибка в итоге done 99: %+v, %v\n%s", result, err, stdout)
+		}
+		if !failed {
+			t.Error("outputFailed должен сообщить об ошибке, чтобы команда завершилась с кодом 1")
+		}
+	})
+}
+
+// TestOutputFlag_Reports проверяет записи отчётов в машиночитаемом выводе:
+// stdout команд trash list, view list, timesheet, undo --list, tags,
+// project list, history и log разбирается как JSON, а команды без записей
+// завершаются ошибкой.
+func TestOutputFlag_Reports(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		now := time.Now()
+		for _, tk := range []task.Task{
+			{Title: "Деплой", Project: "work", Tags: []string{"infra"}, TimeLog: []task.Interval{{Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)}}},
+			{Title: "Хлеб"},
+		} {
+			tk.CreatedAt = now
+			if _, err := store.AddTask(tk); err != nil {
+				t.Fatalf("AddTask вернул ошибку: %v", err)
+			}
+		}
+		defer resetFlags(t, deleteCmd)
+		defer resetFlags(t, viewSaveCmd)
+		defer resetFlags(t, undoCmd)
+		defer resetFlags(t, projectListCmd)
+		defer resetFlags(t, timesheetCmd)
+		defer resetFlags(t, logCmd)
+
+		executeRoot(t, "--file", tmpFile, "-o", "json", "delete", "2")
+		executeRoot(t, "--file", tmpFile, "-o", "json", "view", "save", "work", "project:work")
+
+		// decode разбирает весь stdout команды как JSON
+		decode := func(v any, args ...string) {
+			t.Helper()
+			stdout := executeRoot(t, append([]string{"--file", tmpFile, "-o", "json"}, args...)...)
+			if err := json.Unmarshal([]byte(stdout), v); err != nil {
+				t.Fatalf("%v: stdout не JSON: %v\n%s", args, err, stdout)
+			}
+		}
+
+		var trash []output.TrashEntry
+		decode(&trash, "trash", "list")
+		if len(trash) != 1 || trash[0].Task.ID != 2 || trash[0].DeletedAt == "" {
+			t.Errorf("неверные записи trash list: %+v", trash)
+		}
+
+		var views []output.View
+		decode(&views, "view", "list")
+		if len(views) != 1 || views[0].Name != "work" || !slices.Equal(views[0].Args, []string{"--query=project:work"}) {
+			t.Errorf("неверные записи view list: %+v", views)
+		}
+
+		var totals []output.TimeTotal
+		decode(&totals, "timesheet", "--by=task")
+		if len(totals) != 1 || totals[0].Group != "task" || totals[0].Key != "1" || totals[0].Seconds != 3600 {
+			t.Errorf("неверные записи timesheet: %+v", totals)
+		}
+
+		var ops []output.Operation
+		decode(&ops, "undo", "--list")
+		if len(ops) != 1 || ops[0].Kind != "delete" || !slices.Equal(ops[0].TaskIDs, []int{2}) {
+			t.Errorf("неверные записи undo --list: %+v", ops)
+		}
+
+		var tags []output.TagCount
+		decode(&tags, "tags")
+		if len(tags) != 1 || tags[0] != (output.TagCount{Tag: "infra", Total: 1, Pending: 1}) {
+			t.Errorf("неверные записи tags: %+v", tags)
+		}
+
+		var projects []output.Project
+		decode(&projects, "project", "list")
+		if len(projects) != 1 || projects[0].Project != "work" || projects[0].Total != 1 {
+			t.Errorf("неверные записи project list: %+v", projects)
+		}
+
+		for _, args := range [][]string{{"history", "2"}, {"log"}} {
+			var events []output.Event
+			decode(&events, args...)
+			if len(events) != 1 || events[0].Op != "delete" || events[0].Before == nil || events[0].After != nil {
+				t.Errorf("неверные записи %v: %+v", args, events)
+			}
+		}
+
+		// Группа команд выводит только справку — формат не поддерживается
+		rootCmd.SetArgs([]string{"--file", tmpFile, "-o", "json", "trash"})
+		stdout := captureOutput(func() {
+			if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "формат вывода json не поддерживается командой trash") {
+				t.Errorf("ожидалась ошибка формата вывода, получено %v", err)
+			}
+		})
+		outputFlag, out = "text", outputState{}
+		rootCmd.SetArgs(nil)
+		if stdout != "" {
+			t.Errorf("stdout должен быть пуст:\n%s", stdout)
+		}
+	})
+}
+
+// TestFormatFlag проверяет вывод задач по шаблону --format: встроенные
+// функции, шаблон из каталога настроек с header и footer, команды pending
+// и search и ошибки шаблона.
+func TestFormatFlag(t *testing.T) {
+	withTempStore(t, func(store *storage.JSONStore, tmpFile string) {
+		origConfig := configDir
+		configDir = t.TempDir()
+		defer func() { configDir = origConfig }()
+
+		tomorrow := time.Now().AddDate(0, 0, 1)
+		due := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.Local)
+		for _, tk := range []task.Task{
+			{Title: "Fix auth", DueAt: due, Tags: []string{"api", "auth"}},
+			{Title: "Write docs", Status: task.StatusDone},
+			{Title: "Release", BlockedBy: []int{1}},
+		} {
+			tk.CreatedAt = time.Now()
+			if _, err := store.AddTask(tk); err != nil {
+				t.Fatalf("AddTask вернул ошибку: %v", err)
+			}
+		}
+		defer resetFlags(t, listCmd)
+		defer resetFlags(t, pendingCmd)
+		defer resetFlags(t, searchCmd)
+
+		resetFlags(t, listCmd)
+		setFlags(t, listCmd, map[string]string{
+			"format": `- {{.Checkbox}} #{{.ID}} {{.Title}}{{with .DueAt | date "Mon"}} (due {{.}}, {{rel $.DueAt}}){{end}}`,
+		})
+		output := captureOutput(func() { listCmd.Run(listCmd, nil) })
+		want := fmt.Sprintf("- [ ] #1 Fix auth (due %s, завтра)\n- [x] #2 Write docs\n- [ ] #3 Release\n", due.Format("Mon"))
+		if output != want {
+			t.Errorf("list --format:\nполучено %q\nожидалось %q", output, want)
+		}
+
+		resetFlags(t, listCmd)
+		setFlags(t, listCmd, map[string]string{"format": `{{pad -3 .ID}}|{{pad 8 (color "red" .Title)}}|{{trunc 5 .Title}}|{{join "," .Tags}}|{{ids .Waiting}}`})
+		output = captureOutput(func() { listCmd.Run(listCmd, nil) })
+		want = "  1|\033[31mFix auth\033[0m|Fix …|api,auth|\n" +
+			"  2|\033[31mWrite docs\033[0m|Writ…||\n" +
+			"  3|\033[31mRelease\033[0m |Rele…||1\n"
+		if output != want {
+			t.Errorf("функции шаблона:\nполучено %q\nожидалось %q", output, want)
+		}
+
+		// Именованный шаблон из каталога настроек; пустая строка задачи пропускается
+		dir := filepath.Join(configDir, templatesDir)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		standup := "{{define \"header\"}}Задачи: {{.Count}}{{end}}{{define \"footer\"}}---{{end}}{{if not .IsClosed}}{{glyph .Status}} {{.Title}}{{end}}\n"
+		if err := os.WriteFile(filepath.Join(dir, "standup.tmpl"), []byte(standup), 0600); err != nil {
+			t.Fatal(err)
+		}
+		resetFlags(t, listCmd)
+		setFlags(t, listCmd, map[string]string{"format": "standup"})
+		output = captureOutput(func() { listCmd.Run(listCmd, nil) })
+		want = "Задачи: 3\n" + formatStatus(task.StatusTodo) + " Fix auth\n" + formatStatus(task.StatusTodo) + " Release\n---\n"
+		if output != want {
+			t.Errorf("list --format=standup:\nполучено %q\nожидалось %q", output, want)
+		}
+
+		resetFlags(t, pendingCmd)
+		setFlags(t, pendingCmd, map[string]string{"format": "{{.ID}}", "sort": "-id"})
+		if output := captureOutput(func() { pendingCmd.Run(pendingCmd, nil) }); output != "3\n1\n" {
+			t.Errorf("pending --format: %q", output)
+		}
+		resetFlags(t, searchCmd)
+		setFlags(t, searchCmd, map[string]string{"format": "{{.ID}} {{.Status}}"})
+		if output := captureOutput(func() { searchCmd.Run(searchCmd, []string{"docs"}) }); output != "2 done\n" {
+			t.Errorf("search --format: %q", output)
+		}
+
+		// Встроенный шаблон table — то же, что вывод без --format
+		resetFlags(t, listCmd)
+		table := captureOutput(func() { listCmd.Run(listCmd, nil) })
+		setFlags(t, listCmd, map[string]string{"format": "table"})
+		if output := captureOutput(func() { listCmd.Run(listCmd, nil) }); output != table || !strings.Contains(table, "(ждёт: 1)") {
+			t.Errorf("--format=table отличается от вывода по умолчанию:\n%s\n%s", output, table)
+		}
+
+		for value, wantErr := range map[string]string{
+			"{{.Nope}}":                 "can't evaluate field Nope",
+			"{{color \"pink\" .Title}}": `неизвестный цвет "pink"`,
+			"{{.ID":                     "Ошибка в --format",
+			"missing":                   `шаблон "missing" не найден, доступны: standup, table`,
+			"../secret":                 "некорректное имя шаблона",
+		} {
+			resetFlags(t, listCmd)
+			setFlags(t, listCmd, map[string]string{"format": value})
+			output := captureOutput(func() { listCmd.Run(listCmd, nil) })
+			if !strings.Contains(output, wantErr) || strings.Contains(output, "Fix auth") {
+				t.Errorf("--format=%s: ожидалась ошибка %q:\n%s", value, wantErr, output)
+			}
+		}
+	})
+}
+
+// TestRelativeDate проверяет даты относительно сегодняшнего дня для функции rel.
+func TestRelativeDate(t *testing.T) {
+	now := time.Date(2025, 3, 14, 10, 0, 0, 0, time.Local)
+	for _, tt := range []struct {
+		date time.Time
+		want string
+	}{
+		{time.Time{}, ""},
+		{time.Date(2025, 3, 14, 0, 0, 0, 0, time.Local), "сегодня"},
+		{time.Date(2025, 3, 15, 18, 30, 0, 0, time.Local), "завтра 18:30"},
+		{time.Date(2025, 3, 13, 0, 0, 0, 0, time.Local), "вчера"},
+		{time.Date(2025, 3, 21, 0, 0, 0, 0, time.Local), "через 7 дн."},
+		{time.Date(2025, 2, 28, 0, 0, 0, 0, time.Local), "14 дн. назад"},
+	} {
+		if got := relativeDate(tt.date, now); got != tt.want {
+			t.Errorf("relativeDate(%v) = %q, ожидалось %q", tt.date, got, tt.want)
+		}
+	}
+}
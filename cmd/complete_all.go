@@ -2,9 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
 )
 
 // completeAllCmd — подкоманда "complete-all", которая отмечает
@@ -17,34 +18,44 @@ var completeAllCmd = &cobra.Command{
 	Short: "Отметить все задачи как выполненные", // краткое описание
 	Run: func(cmd *cobra.Command, args []string) {
 		// Создаём хранилище задач
-		store := storage.NewJSONStore(tasksFile)
-
-		// Загружаем все задачи
-		tasks, err := store.ListTasks()
+		store, err := openStore()
 		if err != nil {
-			fmt.Println("Ошибка при загрузке задач:", err)
+			printError("Ошибка при открытии хранилища:", err)
 			return
 		}
+		defer func() { _ = store.Close() }()
 
-		// Обновляем статус всех задач
-		updated := 0
-		for i := range tasks {
-			if !tasks[i].Completed {
-				tasks[i].Completed = true
+		// Обновляем статус всех задач одной атомарной операцией,
+		// чтобы не потерять задачи, добавленные параллельно.
+		// Повторяющиеся задачи порождают следующие повторения, которые
+		// остаются невыполненными.
+		updated, spawned := 0, 0
+		err = modifyTasks(store, "complete-all", func(tasks []task.Task) ([]task.Task, error) {
+			now := time.Now()
+			for i := range tasks {
+				if tasks[i].IsClosed() {
+					continue
+				}
+				if next, ok := tasks[i].Complete(now); ok {
+					tasks = append(tasks, next)
+					spawned++
+				}
 				updated++
 			}
-		}
-
-		// Сохраняем изменения
-		if err := store.OverwriteTasks(tasks); err != nil {
-			fmt.Println("Ошибка при обновлении задач:", err)
+			return tasks, nil
+		})
+		if err != nil {
+			printError("Ошибка при обновлении задач:", err)
 			return
 		}
 
 		if updated > 0 {
-			fmt.Printf("Отмечено как выполненные задач: %d\n", updated)
+			fmt.Fprintf(textOut(), "Отмечено как выполненные задач: %d\n", updated)
+			if spawned > 0 {
+				fmt.Fprintf(textOut(), "Создано следующих повторений: %d\n", spawned)
+			}
 		} else {
-			fmt.Println("Все задачи уже выполнены.")
+			fmt.Fprintln(textOut(), "Все задачи уже выполнены.")
 		}
 	},
 }
@@ -1,41 +1,161 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
-	"strconv"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
 )
 
-// deleteCmd — подкоманда "delete", которая удаляет задачу по ID.
+// askChildrenMode спрашивает у пользователя, что делать с подзадачами
+// удаляемых задач. Возвращает "orphan", "remove" или "" (отмена).
+func askChildrenMode(ids []int, count int) string {
+	who := fmt.Sprintf("У задачи с ID %d", ids[0])
+	if len(ids) > 1 {
+		who = fmt.Sprintf("У задач с ID %s", formatIDs(ids))
+	}
+	fmt.Fprintf(textOut(), "%s есть подзадачи (%d). Что с ними сделать?\n"+
+		"  [o] оставить отдельными задачами\n  [r] удалить вместе с задачей\n  [c] отменить удаление\n> ", who, count)
+
+	answer, _ := bufio.NewReader(stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "o", "orphan":
+		return "orphan"
+	case "r", "remove":
+		return "remove"
+	}
+	return ""
+}
+
+// outsideDescendants возвращает подзадачи задач ids на любой глубине,
+// которые сами в ids не входят.
+func outsideDescendants(tasks []task.Task, ids []int) []int {
+	var result []int
+	for _, id := range ids {
+		for _, child := range task.Descendants(tasks, id) {
+			if !slices.Contains(ids, child) && !slices.Contains(result, child) {
+				result = append(result, child)
+			}
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// deleteWithChildren удаляет задачи ids из списка. При mode == "orphan"
+// их прямые подзадачи становятся задачами верхнего уровня, при "remove"
+// удаляются вместе со всеми своими подзадачами. Зависимости других задач
+// от удалённых снимаются. Возвращает новый список и удалённые подзадачи.
+func deleteWithChildren(tasks []task.Task, ids []int, mode string) ([]task.Task, []int) {
+	removed := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		removed[id] = true
+	}
+	var children []int
+	if mode == "remove" {
+		children = outsideDescendants(tasks, ids)
+		for _, child := range children {
+			removed[child] = true
+		}
+	}
+
+	result := make([]task.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if removed[t.ID] {
+			continue
+		}
+		if removed[t.ParentID] {
+			t.ParentID = 0
+		}
+		result = append(result, t)
+	}
+	return task.PruneBlockers(result), children
+}
+
+// deleteCmd — подкоманда "delete", которая удаляет задачи в корзину
+// (см. "todo trash"). Задачи выбираются по ID, диапазонам ID и фильтрам --tag и --filter
+// и удаляются одной операцией. Если у задач есть подзадачи, команда
+// спрашивает, оставить их отдельными задачами или удалить; ответ можно
+// передать флагом --children.
 // Пример использования:
 //
-//	todo delete 2  — удалит задачу с ID 2
+//	todo delete 2                    — удалит задачу с ID 2
+//	todo delete 2 4 10-12            — несколько задач сразу
+//	todo delete --tag=draft --filter=cancelled
+//	todo delete 2 --children=remove  — вместе со всеми подзадачами
 var deleteCmd = &cobra.Command{
-	Use:   "delete [task ID]",     // формат вызова
-	Short: "Удалить задачу по ID", // краткое описание
-	Args:  cobra.ExactArgs(1),     // ожидаем ровно один аргумент — ID задачи
+	Use:   "delete [task ID]...",  // формат вызова
+	Short: "Удалить задачи по ID", // краткое описание
 	Run: func(cmd *cobra.Command, args []string) {
-		// Конвертируем аргумент в int (ID задачи)
-		id, err := strconv.Atoi(args[0])
+		// Разбираем ID, диапазоны и фильтры
+		sel, err := parseSelection(cmd, args)
 		if err != nil {
-			fmt.Println("Некорректный ID задачи:", args[0])
+			printError(err)
 			return
 		}
 
 		// Создаём хранилище задач
-		store := storage.NewJSONStore(tasksFile)
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
 
-		// Удаляем задачу через публичный метод
-		err = store.DeleteTask(id)
+		// Заранее узнаём, есть ли у выбранных задач подзадачи,
+		// чтобы спросить о них до изменения
+		tasks, err := store.ListTasks()
+		if err != nil {
+			printError("Ошибка при загрузке задач:", err)
+			return
+		}
+		ids, _, err := sel.resolve(tasks)
 		if err != nil {
-			fmt.Println("Ошибка:", err)
+			printError("Ошибка:", err)
+			return
+		}
+		mode, _ := cmd.Flags().GetString("children")
+		if children := outsideDescendants(tasks, ids); len(children) > 0 && mode == "" {
+			mode = askChildrenMode(ids, len(children))
+			if mode == "" {
+				fmt.Fprintln(textOut(), "Удаление отменено.")
+				return
+			}
+		}
+		if mode != "" && mode != "orphan" && mode != "remove" {
+			printErrorf("Ошибка: неизвестное значение --children: %s (используйте orphan или remove)\n", mode)
+			return
+		}
+
+		// Выбор и удаление задач — одна атомарная операция
+		var (
+			result   bulkResult
+			subtasks []int
+		)
+		err = modifyTasks(store, "delete", func(tasks []task.Task) ([]task.Task, error) {
+			ids, missing, err := sel.resolve(tasks)
+			if err != nil {
+				return nil, err
+			}
+			result = bulkResult{Changed: ids, Missing: missing}
+			var updated []task.Task
+			updated, subtasks = deleteWithChildren(tasks, ids, mode)
+			return updated, nil
+		})
+		if err != nil {
+			printError("Ошибка:", err)
 			return
 		}
 
 		// Подтверждаем успешное удаление
-		fmt.Printf("Задача с ID %d успешно удалена.\n", id)
+		result.print("Задача с ID %d успешно удалена.", "Удалено задач:", "")
+		if len(subtasks) > 0 {
+			fmt.Fprintf(textOut(), "Вместе с ними удалены подзадачи: %s.\n", formatIDs(subtasks))
+		}
+		fmt.Fprintln(textOut(), "Удалённые задачи лежат в корзине: todo trash list")
 	},
 }
 
@@ -43,7 +163,11 @@ var deleteCmd = &cobra.Command{
 // Здесь мы подключаем подкоманду "delete" к rootCmd.
 func init() {
 	deleteCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		store := storage.NewJSONStore(tasksFile)
+		store, err := openStore()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		defer func() { _ = store.Close() }()
 		tasks, err := store.ListTasks()
 		if err != nil {
 			return nil, cobra.ShellCompDirectiveError
@@ -57,4 +181,13 @@ func init() {
 	}
 
 	rootCmd.AddCommand(deleteCmd)
+
+	// Флаги выбора задач: --tag и --filter
+	addSelectionFlags(deleteCmd)
+
+	// Флаг судьбы подзадач: без него команда спросит у пользователя
+	deleteCmd.Flags().String("children", "", "Что сделать с подзадачами: orphan — оставить, remove — удалить")
+	_ = deleteCmd.RegisterFlagCompletionFunc("children", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"orphan", "remove"}, cobra.ShellCompDirectiveNoFileComp
+	})
 }
@@ -2,40 +2,219 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
 )
 
-// doneCmd — подкоманда "done", которая отмечает задачу как выполненную.
+// openDescendants возвращает ID незавершённых подзадач задачи id на любой глубине.
+func openDescendants(tasks []task.Task, id int) []int {
+	byID := make(map[int]task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	var open []int
+	for _, child := range task.Descendants(tasks, id) {
+		if !byID[child].IsClosed() {
+			open = append(open, child)
+		}
+	}
+	return open
+}
+
+// externalBlockers возвращает ID невыполненных задач, от которых зависят
+// задачи closing, не считая самих задач closing.
+func externalBlockers(tasks []task.Task, closing []int) []int {
+	var blockers []int
+	for _, t := range tasks {
+		if !slices.Contains(closing, t.ID) {
+			continue
+		}
+		for _, id := range task.OpenBlockers(tasks, t) {
+			if !slices.Contains(closing, id) && !slices.Contains(blockers, id) {
+				blockers = append(blockers, id)
+			}
+		}
+	}
+	slices.Sort(blockers)
+	return blockers
+}
+
+// completeTasks отмечает выполненными задачи с указанными ID и добавляет
+// в конец списка следующие повторения повторяющихся задач.
+func completeTasks(tasks []task.Task, ids []int, now time.Time) []task.Task {
+	for i := range tasks {
+		if slices.Contains(ids, tasks[i].ID) {
+			if next, ok := tasks[i].Complete(now); ok {
+				tasks = append(tasks, next)
+			}
+		}
+	}
+	return tasks
+}
+
+// printNextOccurrences сообщает о повторениях, созданных после выполнения
+// задач: это задачи с ID больше, чем был максимальный ID до операции.
+func printNextOccurrences(store storage.Storage, maxID int) {
+	tasks, err := store.ListTasks()
+	if err != nil {
+		return
+	}
+	for _, t := range tasks {
+		if t.ID > maxID {
+			fmt.Fprintf(textOut(), "Следующее повторение [%d]: %s (срок: %s)\n", t.ID, t.Title, formatDue(t))
+		}
+	}
+}
+
+// maxTaskID возвращает наибольший ID в списке задач (0 для пустого списка).
+func maxTaskID(tasks []task.Task) int {
+	maxID := 0
+	for _, t := range tasks {
+		maxID = max(maxID, t.ID)
+	}
+	return maxID
+}
+
+// formatIDs форматирует список ID через запятую.
+func formatIDs(ids []int) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.Itoa(id))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// closeWithSubtasks — приватная функция, отмечает выполненными задачи ids
+// (уже выполненные пропускает) и добавляет повторения повторяющихся задач.
+// Незавершённые подзадачи, не вошедшие в ids, закрываются вместе с
+// родителем при cascade, иначе операция отклоняется. Задачи, ждущие
+// незавершённые задачи, без force тоже не закрываются. Возвращает
+// новый список, закрытые задачи, закрытые вместе с ними подзадачи
+// и пропущенные задачи.
+func closeWithSubtasks(tasks []task.Task, ids []int, cascade, force bool, now time.Time) (result []task.Task, closed, subtasks, skipped []int, err error) {
+	byID := make(map[int]task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	for _, id := range ids {
+		if byID[id].Status == task.StatusDone {
+			skipped = append(skipped, id)
+		} else {
+			closed = append(closed, id)
+		}
+	}
+	if len(closed) == 0 {
+		return tasks, nil, nil, skipped, nil
+	}
+
+	// Незавершённые подзадачи, которые не закрываются вместе с родителем
+	for _, id := range closed {
+		for _, child := range openDescendants(tasks, id) {
+			if slices.Contains(closed, child) || slices.Contains(subtasks, child) {
+				continue
+			}
+			if !cascade {
+				return nil, nil, nil, nil, fmt.Errorf("у задачи с ID %d есть невыполненные подзадачи: %s. "+
+					"Выполните их или используйте --cascade", id, formatIDs(openDescendants(tasks, id)))
+			}
+			subtasks = append(subtasks, child)
+		}
+	}
+	slices.Sort(subtasks)
+
+	// Проверяем, не ждут ли закрываемые задачи других задач
+	closing := append(slices.Clone(closed), subtasks...)
+	if blockers := externalBlockers(tasks, closing); len(blockers) > 0 && !force {
+		who := fmt.Sprintf("задача с ID %d ждёт", closed[0])
+		if len(closing) > 1 {
+			who = fmt.Sprintf("задачи %s ждут", formatIDs(closing))
+		}
+		return nil, nil, nil, nil, fmt.Errorf("%s невыполненные задачи: %s. "+
+			"Выполните их или используйте --force", who, formatIDs(blockers))
+	}
+
+	return completeTasks(tasks, closing, now), closed, subtasks, skipped, nil
+}
+
+// doneCmd — подкоманда "done", которая отмечает задачи как выполненные.
+// Задачи выбираются по ID, диапазонам ID и фильтрам --tag и --filter;
+// все выбранные задачи закрываются одной операцией.
+// Задачу с невыполненными подзадачами закрыть нельзя, пока не выполнены
+// подзадачи; флаг --cascade закрывает её вместе со всеми подзадачами.
+// Задачу, которая ждёт невыполненные задачи (см. "todo block"), закрыть
+// можно только с флагом --force. Выполнение повторяющейся задачи создаёт
+// её следующее повторение со сдвинутым сроком (см. "todo recur").
 // Пример использования:
 //
-//	todo done 2  — пометит задачу с ID 2 как выполненную
+//	todo done 2            — пометит задачу с ID 2 как выполненную
+//	todo done 2 5 7-9      — несколько задач сразу
+//	todo done --tag=sprint --filter=pending
+//	todo done 2 --cascade  — вместе со всеми подзадачами
+//	todo done 2 --force    — несмотря на невыполненные зависимости
 var doneCmd = &cobra.Command{
-	Use:   "done [task ID]",                  // формат вызова
-	Short: "Отметить задачу как выполненную", // краткое описание
-	Args:  cobra.ExactArgs(1),                // ожидаем ровно один аргумент — ID задачи
+	Use:   "done [task ID]...",               // формат вызова
+	Short: "Отметить задачи как выполненные", // краткое описание
 	Run: func(cmd *cobra.Command, args []string) {
-		// Конвертируем аргумент в int (ID задачи)
-		id, err := strconv.Atoi(args[0])
+		// Разбираем ID, диапазоны и фильтры
+		sel, err := parseSelection(cmd, args)
 		if err != nil {
-			fmt.Println("Некорректный ID задачи:", args[0])
+			printError(err)
 			return
 		}
+		cascade, _ := cmd.Flags().GetBool("cascade")
+		force, _ := cmd.Flags().GetBool("force")
 
 		// Создаём хранилище задач
-		store := storage.NewJSONStore(tasksFile)
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
 
-		// Отмечаем задачу как выполненную
-		err = store.MarkTaskDone(id)
+		// Выбор задач, проверки и выполнение — одна атомарная операция:
+		// либо закрываются все выбранные задачи, либо ни одной.
+		var (
+			result   bulkResult
+			subtasks []int
+			maxID    int
+		)
+		err = modifyTasks(store, "done", func(tasks []task.Task) ([]task.Task, error) {
+			ids, missing, err := sel.resolve(tasks)
+			if err != nil {
+				return nil, err
+			}
+			maxID = maxTaskID(tasks)
+			updated, closed, children, skipped, err := closeWithSubtasks(tasks, ids, cascade, force, time.Now())
+			if err != nil {
+				return nil, err
+			}
+			result = bulkResult{Changed: closed, Skipped: skipped, Missing: missing}
+			subtasks = children
+			return updated, nil
+		})
 		if err != nil {
-			fmt.Println("Ошибка:", err)
+			printError("Ошибка:", err)
 			return
 		}
 
 		// Подтверждаем успешное выполнение
-		fmt.Printf("Задача с ID %d отмечена как выполненная.\n", id)
+		result.print("Задача с ID %d отмечена как выполненная.", "Отмечено как выполненные задач:", "Уже выполнены")
+		if len(subtasks) > 0 {
+			with := "ними"
+			if len(result.Changed) == 1 {
+				with = "ней"
+			}
+			fmt.Fprintf(textOut(), "Вместе с %s выполнены подзадачи: %s.\n", with, formatIDs(subtasks))
+		}
+		printNextOccurrences(store, maxID)
 	},
 }
 
@@ -43,7 +222,11 @@ var doneCmd = &cobra.Command{
 // Здесь мы подключаем подкоманду "done" к rootCmd.
 func init() {
 	doneCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		store := storage.NewJSONStore(tasksFile)
+		store, err := openStore()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		defer func() { _ = store.Close() }()
 		tasks, err := store.ListTasks()
 		if err != nil {
 			return nil, cobra.ShellCompDirectiveError
@@ -51,7 +234,7 @@ func init() {
 
 		var suggestions []string
 		for _, t := range tasks {
-			if !t.Completed { // показываем только невыполненные
+			if !t.IsClosed() { // показываем только незавершённые
 				suggestions = append(suggestions, fmt.Sprint(t.ID))
 			}
 		}
@@ -59,4 +242,13 @@ func init() {
 	}
 
 	rootCmd.AddCommand(doneCmd)
+
+	// Флаги выбора задач: --tag и --filter
+	addSelectionFlags(doneCmd)
+
+	// Флаг каскадного выполнения подзадач
+	doneCmd.Flags().Bool("cascade", false, "Отметить выполненными и все невыполненные подзадачи")
+
+	// Флаг выполнения задачи, несмотря на невыполненные зависимости
+	doneCmd.Flags().Bool("force", false, "Отметить выполненной, даже если задача ждёт другие задачи")
 }
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// editErrorPrefix — начало строки с ошибкой, которую edit добавляет
+// в файл перед повторным открытием редактора.
+const editErrorPrefix = "# Ошибка: "
+
+// runEditor открывает файл path в редакторе пользователя ($VISUAL, $EDITOR,
+// по умолчанию vi) и ждёт его закрытия. В тестах подменяется.
+var runEditor = func(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// Редактор может быть указан с аргументами, например "code --wait"
+	fields := strings.Fields(editor)
+	c := exec.Command(fields[0], append(fields[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, textOut(), os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("редактор %q завершился с ошибкой: %w", editor, err)
+	}
+	return nil
+}
+
+// editText записывает content во временный файл, открывает его
+// в редакторе и возвращает отредактированный текст.
+func editText(content string) (string, error) {
+	f, err := os.CreateTemp("", "todo-*.md")
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	defer func() { _ = os.Remove(path) }()
+
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	if err := runEditor(path); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	return string(data), err
+}
+
+// withEditError добавляет в начало текста комментарий с ошибкой,
+// убрав комментарий предыдущей попытки. Номер строки в ошибке сдвигается
+// так, чтобы указывать на ту же строку в файле с новым комментарием.
+func withEditError(content string, err error) string {
+	removed := 0
+	for strings.HasPrefix(content, editErrorPrefix) {
+		_, content, _ = strings.Cut(content, "\n")
+		removed++
+	}
+	var editErr *task.EditError
+	if errors.As(err, &editErr) && editErr.Line > 0 {
+		shifted := *editErr
+		shifted.Line += 1 - removed
+		err = &shifted
+	}
+	return editErrorPrefix + err.Error() + ". Исправьте и сохраните файл.\n" + content
+}
+
+// editCmd — подкоманда "edit", которая открывает задачу в редакторе
+// ($VISUAL или $EDITOR): название, приоритет и срок — в заголовке файла,
+// заметки — после него. Если файл содержит ошибку, редактор открывается
+// снова с её описанием; пустой или не изменённый после ошибки файл
+// отменяет редактирование.
+// Пример использования:
+//
+//	todo edit 3
+//	EDITOR=nano todo edit 3
+var editCmd = &cobra.Command{
+	Use:               "edit [task ID]",              // формат вызова
+	Short:             "Изменить задачу в редакторе", // краткое описание
+	Args:              cobra.ExactArgs(1),            // ожидаем ровно один аргумент — ID задачи
+	ValidArgsFunction: completeTaskIDs,               // автодополнение ID задачи
+	Run: func(cmd *cobra.Command, args []string) {
+		// Конвертируем аргумент в int (ID задачи)
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			printError("Некорректный ID задачи:", args[0])
+			return
+		}
+
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		original, err := store.GetTask(id)
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		// Открываем редактор, пока файл не станет корректным
+		var edited task.Task
+		content := task.FormatEditable(original)
+		for attempt := 0; ; attempt++ {
+			text, err := editText(content)
+			if err != nil {
+				printError("Ошибка:", err)
+				return
+			}
+			if strings.TrimSpace(text) == "" || (attempt > 0 && text == content) {
+				fmt.Fprintln(textOut(), "Редактирование отменено.")
+				return
+			}
+
+			edited, err = task.ParseEditable(text, original, time.Now())
+			if err == nil {
+				break
+			}
+			printError("Ошибка:", err)
+			content = withEditError(text, err)
+		}
+
+		// Применяем изменения к текущей версии задачи: пока был открыт
+		// редактор, её могли изменить другие команды.
+		changed := false
+		err = modifyTasks(store, "edit", func(tasks []task.Task) ([]task.Task, error) {
+			i := taskIndex(tasks, id)
+			if i < 0 {
+				return nil, &storage.NotFoundError{ID: id}
+			}
+			t := &tasks[i]
+			changed = t.Title != edited.Title || t.Priority != edited.Priority ||
+				!t.DueAt.Equal(edited.DueAt) || t.Notes != edited.Notes
+			t.Title, t.Priority, t.DueAt, t.Notes = edited.Title, edited.Priority, edited.DueAt, edited.Notes
+			return tasks, nil
+		})
+		if err != nil {
+			printError("Ошибка при обновлении задачи:", err)
+			return
+		}
+
+		if !changed {
+			fmt.Fprintln(textOut(), "Изменений нет.")
+			return
+		}
+		fmt.Fprintf(textOut(), "Задача с ID %d успешно обновлена.\n", id)
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "edit" к rootCmd.
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
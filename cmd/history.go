@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/output"
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// formatEvent форматирует событие журнала аудита в одну строку:
+// время, пользователь, ID задачи, операция и что изменилось.
+func formatEvent(e storage.Event) string {
+	return fmt.Sprintf("%s  %-10s #%-4d %-13s %s",
+		e.Time.Local().Format("2006-01-02 15:04:05"), e.User, e.TaskID, e.Op, describeChange(e.Before, e.After))
+}
+
+// describeChange описывает изменение задачи: создание, удаление
+// или список изменившихся полей в виде "поле: было → стало".
+func describeChange(before, after *task.Task) string {
+	switch {
+	case before == nil && after == nil:
+		return ""
+	case before == nil:
+		return fmt.Sprintf("создана: %q", after.Title)
+	case after == nil:
+		return fmt.Sprintf("удалена: %q", before.Title)
+	}
+
+	// Сравниваем по JSON-полям, чтобы новые поля задачи
+	// попадали в историю без доработки этой функции.
+	b, errB := taskFields(*before)
+	a, errA := taskFields(*after)
+	if errB != nil || errA != nil {
+		return "изменена"
+	}
+
+	keys := make([]string, 0, len(a))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []string
+	for _, k := range keys {
+		if bytes.Equal(b[k], a[k]) {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s → %s", k, fieldValue(b[k]), fieldValue(a[k])))
+	}
+	if len(changes) == 0 {
+		return "без изменений"
+	}
+	return strings.Join(changes, ", ")
+}
+
+// writeEvents выводит события журнала аудита записями вместо текста.
+func writeEvents(events []storage.Event) {
+	now := time.Now()
+	state := func(t *task.Task) *output.Task {
+		if t == nil {
+			return nil
+		}
+		rec := output.FromTask(*t, now)
+		return &rec
+	}
+
+	records := make([]output.Event, 0, len(events))
+	for _, e := range events {
+		records = append(records, output.Event{
+			Time: e.Time.Format(time.RFC3339), User: e.User, Op: e.Op, TaskID: e.TaskID,
+			Change: describeChange(e.Before, e.After), Before: state(e.Before), After: state(e.After),
+		})
+	}
+	writeReport(records)
+}
+
+// taskFields раскладывает задачу на JSON-поля.
+func taskFields(t task.Task) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
+
+// fieldValue возвращает значение поля для вывода; отсутствующее поле — "—".
+func fieldValue(v json.RawMessage) string {
+	if len(v) == 0 {
+		return "—"
+	}
+	return string(v)
+}
+
+// historyCmd — подкоманда "history", которая показывает всю историю
+// изменений задачи: кто, когда и что поменял. Работает и для удалённых задач.
+// Пример использования:
+//
+//	todo history 3
+var historyCmd = &cobra.Command{
+	Use:   "history [task ID]",                 // формат вызова
+	Short: "Показать историю изменений задачи", // краткое описание
+	Args:  cobra.ExactArgs(1),                  // ожидаем ровно один аргумент — ID задачи
+	Run: func(cmd *cobra.Command, args []string) {
+		// Конвертируем аргумент в int (ID задачи)
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			printError("Некорректный ID задачи:", args[0])
+			return
+		}
+
+		// Открываем хранилище вместе с журналом аудита
+		store, err := openJournaledStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		events, err := store.Events.Read(func(e storage.Event) bool { return e.TaskID == id })
+		if err != nil {
+			printError("Ошибка при чтении истории:", err)
+			return
+		}
+		if structuredOutput() {
+			writeEvents(events)
+			return
+		}
+		if len(events) == 0 {
+			fmt.Fprintf(textOut(), "История задачи с ID %d пуста.\n", id)
+			return
+		}
+
+		fmt.Fprintf(textOut(), "История задачи с ID %d:\n", id)
+		for _, e := range events {
+			fmt.Fprintln(textOut(), formatEvent(e))
+		}
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "history" к rootCmd.
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
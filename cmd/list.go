@@ -1,127 +1,391 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
-	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/query"
 	"github.com/zen-flo/todo-cli/internal/task"
-	"os"
-	"sort"
+	"strings"
 	"time"
 )
 
+// statusGlyphs — цветные символы состояний задачи.
+var statusGlyphs = map[task.Status]string{
+	task.StatusTodo:       "\033[31m❌\033[0m",
+	task.StatusInProgress: "\033[34m🔄\033[0m",
+	task.StatusBlocked:    "\033[33m⛔\033[0m",
+	task.StatusReview:     "\033[35m👀\033[0m",
+	task.StatusDone:       "\033[32m✅\033[0m",
+	task.StatusCancelled:  "\033[90m🚫\033[0m",
+}
+
 // formatStatus возвращает цветной символ статуса задачи.
-// ✅ зелёный — выполнено, ❌ красный — невыполнено
-func formatStatus(completed bool) string {
-	if completed {
-		return "\033[32m✅\033[0m"
+// ❌ красный — к выполнению, 🔄 синий — в работе, ⛔ жёлтый — заблокирована,
+// 👀 фиолетовый — на проверке, ✅ зелёный — выполнена, 🚫 серый — отменена.
+func formatStatus(s task.Status) string {
+	return statusGlyphs[s]
+}
+
+// parseStatusFilter разбирает фильтр по статусу: all, pending (все
+// незавершённые), completed (выполненные) или имена состояний через запятую
+// (in-progress,review). Для all возвращает nil.
+func parseStatusFilter(s string) (func(task.Task) bool, error) {
+	switch value := strings.ToLower(strings.TrimSpace(s)); value {
+	case "", "all":
+		return nil, nil
+	case "pending":
+		return func(t task.Task) bool { return !t.IsClosed() }, nil
+	case "completed":
+		return func(t task.Task) bool { return t.Status == task.StatusDone }, nil
+	}
+
+	wanted := make(map[task.Status]bool)
+	for _, name := range strings.Split(s, ",") {
+		st, err := task.ParseStatus(name)
+		if err != nil {
+			return nil, err
+		}
+		wanted[st] = true
 	}
-	return "\033[31m❌\033[0m"
+	return func(t task.Task) bool { return wanted[t.Status] }, nil
+}
+
+// priorityIcons — значки приоритетов в списке задач.
+// У обычного и низкого приоритета значка нет.
+var priorityIcons = map[task.Priority]string{
+	task.PriorityHigh:   "🔥 ",
+	task.PriorityUrgent: "🚨 ",
 }
 
 // formatTaskTitle форматирует название задачи, добавляет значки и подсветку.
-// 🔥 — важная задача, ⏰ — просроченная (старше 7 дней и не выполнена)
+// 🔥 — высокий приоритет, 🚨 — срочная задача,
+// жёлтым выделяются просроченные (срок прошёл, не выполнена), проект, теги
+// правило повторения (🔁 every mon,thu) и значок заметок 📝 — серым,
+// идущий таймер (⏱ 25m) — зелёным.
 func formatTaskTitle(t task.Task) string {
-	title := t.Title
+	now := time.Now()
+	title := priorityIcons[t.Priority] + t.Title
 
-	if t.Important {
-		title = "🔥 " + title
-	}
-
-	if !t.Completed && time.Since(t.CreatedAt) > 7*24*time.Hour {
+	if t.IsOverdue(now) {
 		// Просрочена — жёлтый цвет
 		title = "\033[33m" + title + "\033[0m"
 	}
 
+	// Идущий таймер — зелёным, сколько он уже идёт
+	if t.Running() {
+		title += " \033[32m⏱ " + formatDuration(t.TimeLog[len(t.TimeLog)-1].Duration(now)) + "\033[0m"
+	}
+
+	// Проект и теги — серым после названия
+	var labels []string
+	if t.Project != "" {
+		labels = append(labels, "@"+t.Project)
+	}
+	for _, tag := range t.Tags {
+		labels = append(labels, "#"+tag)
+	}
+	if !t.Recur.IsZero() {
+		labels = append(labels, "🔁 "+t.Recur.String())
+	}
+	if t.Notes != "" {
+		labels = append(labels, "📝")
+	}
+	if len(labels) > 0 {
+		title += " \033[90m" + strings.Join(labels, " ") + "\033[0m"
+	}
+
 	return title
 }
 
-// printTasksTable выводит задачи в виде таблицы с выравниванием и цветным статусом.
-func printTasksTable(tasks []task.Task) {
-	// Заголовок таблицы
-	fmt.Printf("\033[36m%-4s %-7s %-20s %-16s\033[0m\n", "ID", "STATUS", "TITLE", "CREATED AT")
-	fmt.Println("-----------------------------------------------")
-
-	// Строки таблицы
-	for _, t := range tasks {
-		fmt.Printf("%-4d %-7s %-30s %-16s\n",
-			t.ID,
-			formatStatus(t.Completed),
-			formatTaskTitle(t),
-			t.CreatedAt.Format("2006-01-02 15:04"),
-		)
+// formatDue возвращает срок задачи: только дату, если время не указано,
+// и пустую строку, если срока нет.
+func formatDue(t task.Task) string {
+	switch {
+	case t.DueAt.IsZero():
+		return ""
+	case task.HasClock(t.DueAt):
+		return t.DueAt.Local().Format("2006-01-02 15:04")
+	default:
+		return t.DueAt.Format("2006-01-02")
+	}
+}
+
+// printQueryError выводит ошибку разбора запроса src с префиксом prefix.
+// Для синтаксической ошибки под запросом показывается стрелка на место ошибки.
+func printQueryError(prefix, src string, err error) {
+	printErrorf("%s: %v\n", prefix, err)
+	var syntaxErr *query.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		for _, line := range strings.Split(syntaxErr.Pointer(src), "\n") {
+			fmt.Fprintln(textOut(), "  "+line)
+		}
+	}
+}
+
+// formatTime возвращает момент в местном времени с точностью до минуты
+// и пустую строку для нулевого значения.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
 	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// tableOptions — настройки вывода задач по шаблону (см. templateRows).
+type tableOptions struct {
+	Long     bool                  // добавить столбцы времени изменения и выполнения
+	Tree     bool                  // выводить подзадачи деревом под родителем
+	Progress map[int]task.Progress // прогресс подзадач по ID родителя
+	Blocked  map[int][]int         // невыполненные зависимости по ID задачи
+}
+
+// treePrefix возвращает отступ с линиями дерева для узла.
+func treePrefix(node task.TreeNode) string {
+	if node.Depth == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, last := range node.Last[:node.Depth-1] {
+		if last {
+			b.WriteString("   ")
+		} else {
+			b.WriteString("│  ")
+		}
+	}
+	if node.Last[node.Depth-1] {
+		b.WriteString("└─ ")
+	} else {
+		b.WriteString("├─ ")
+	}
+	return b.String()
+}
+
+// parsePriorityFilter разбирает фильтр по приоритету вида "high", ">=high",
+// ">normal", "<=normal", "<urgent" или "=low" и возвращает функцию проверки.
+func parsePriorityFilter(s string) (func(task.Priority) bool, error) {
+	value := strings.TrimSpace(s)
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if !strings.HasPrefix(value, op) {
+			continue
+		}
+		p, err := task.ParsePriority(value[len(op):])
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case ">=":
+			return func(x task.Priority) bool { return x >= p }, nil
+		case "<=":
+			return func(x task.Priority) bool { return x <= p }, nil
+		case ">":
+			return func(x task.Priority) bool { return x > p }, nil
+		case "<":
+			return func(x task.Priority) bool { return x < p }, nil
+		}
+		return func(x task.Priority) bool { return x == p }, nil
+	}
+
+	p, err := task.ParsePriority(value)
+	if err != nil {
+		return nil, err
+	}
+	return func(x task.Priority) bool { return x == p }, nil
 }
 
 // listCmd — подкоманда "list", которая выводит все задачи.
-// Поддерживает флаг --sort=name/date
+// Поддерживает сортировку по нескольким ключам (--sort=-priority,due,title)
+// и фильтры по сроку и приоритету.
 // Пример использования:
 //
 //	todo list
+//	todo list --due-before=fri --sort=due
+//	todo list --priority=">=high" --sort=-priority,due
+//	todo list --tag=infra --tag=-oncall
+//	todo list --project=work
+//	todo list --tree
+//	todo list --ready     — только задачи, за которые можно браться
+//	todo list --filter=in-progress,review
+//	todo list --long --sort=completed  — со временем изменения и выполнения
+//	todo list --archived  — задачи из архива завершённых (см. todo archive)
+//	todo list -q 'status:pending and (tag:infra or priority>=high) and due<+7d'
+//	todo list --view=work --long  — сохранённое представление (см. todo view)
+//	todo list --format '{{.ID}} {{.Title}}'  — свой шаблон вывода
 var listCmd = &cobra.Command{
 	Use:   "list",                // формат вызова
 	Short: "Показать все задачи", // краткое описание
 	Run: func(cmd *cobra.Command, args []string) {
-		// Получаем флаг сортировки и проверяем
-		sortBy, _ := cmd.Flags().GetString("sort") //сортировка: name, date
-		if sortBy != "" && sortBy != "title" && sortBy != "created" {
-			fmt.Printf("Ошибка: неизвестный способ сортировки: %s\n", sortBy)
+		// Подставляем флаги сохранённого представления (--view)
+		if err := applyView(cmd); err != nil {
+			printError("Ошибка в --view:", err)
+			return
+		}
+
+		// Получаем ключи сортировки и проверяем
+		sortKeys, err := sortFlag(cmd)
+		if err != nil {
+			printError("Ошибка: неизвестный способ сортировки:", err)
+			return
+		}
+
+		// Разбираем границы срока, если они заданы
+		var (
+			now                 = time.Now()
+			dueBefore, dueAfter time.Time
+		)
+		if value, _ := cmd.Flags().GetString("due-before"); value != "" {
+			if dueBefore, err = task.ParseDate(value, now); err != nil {
+				printError("Ошибка в --due-before:", err)
+				return
+			}
+		}
+		if value, _ := cmd.Flags().GetString("due-after"); value != "" {
+			if dueAfter, err = task.ParseDate(value, now); err != nil {
+				printError("Ошибка в --due-after:", err)
+				return
+			}
+		}
+
+		// Разбираем фильтр по приоритету; --important — то же, что >=high
+		var priorityOK func(task.Priority) bool
+		if value, _ := cmd.Flags().GetString("priority"); value != "" {
+			if priorityOK, err = parsePriorityFilter(value); err != nil {
+				printError("Ошибка в --priority:", err)
+				return
+			}
+		} else if importantOnly, _ := cmd.Flags().GetBool("important"); importantOnly {
+			priorityOK = func(p task.Priority) bool { return p >= task.PriorityHigh }
+		}
+
+		// Разбираем фильтр по тегам: "x" — тег нужен, "-x" — тега быть не должно
+		tagValues, _ := cmd.Flags().GetStringArray("tag")
+		includeTags, excludeTags, err := parseTagArgs(tagValues)
+		if err != nil {
+			printError("Ошибка в --tag:", err)
+			return
+		}
+
+		// Фильтр по статусу: all, pending, completed или имена состояний
+		filter, _ := cmd.Flags().GetString("filter")
+		statusOK, err := parseStatusFilter(filter)
+		if err != nil {
+			printError("Ошибка в --filter:", err)
+			return
+		}
+
+		// Фильтр по проекту (без него скрываются архивные проекты)
+		inProject, err := projectFilter(cmd)
+		if err != nil {
+			printError("Ошибка в --project:", err)
+			return
+		}
+
+		// Шаблон вывода --format; без него — таблица (встроенный шаблон table)
+		tmpl, err := formatFlag(cmd)
+		if err != nil {
+			printError("Ошибка в --format:", err)
+			return
+		}
+
+		// Запрос на языке фильтров, например: tag:infra or priority>=high
+		queryText, _ := cmd.Flags().GetString("query")
+		q, err := query.Parse(queryText, now)
+		if err != nil {
+			printQueryError("Ошибка в --query", queryText, err)
 			return
 		}
 
 		// Создаём хранилище задач
-		store := storage.NewJSONStore(tasksFile)
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
 
-		// Получаем список всех задач
-		tasks, err := store.ListTasks()
+		// Получаем список всех задач — активных или из архива
+		var tasks []task.Task
+		archived, _ := cmd.Flags().GetBool("archived")
+		if archived {
+			tasks, err = archivedTasks(store)
+		} else {
+			tasks, err = store.ListTasks()
+		}
 		if err != nil {
-			fmt.Println("Ошибка при загрузке задач:", err)
+			printError("Ошибка при загрузке задач:", err)
 			return
 		}
 
-		if len(tasks) == 0 {
-			fmt.Println("Список задач пуст. Добавьте новую с помощью: todo add \"Название задачи\"")
+		if len(tasks) == 0 && archived && !structuredOutput() && tmpl == nil {
+			fmt.Fprintln(textOut(), "Архив пуст. Перенести завершённые задачи в архив: todo archive")
+			return
+		}
+		if len(tasks) == 0 && !structuredOutput() && tmpl == nil {
+			fmt.Fprintln(textOut(), "Список задач пуст. Добавьте новую с помощью: todo add \"Название задачи\"")
 			return
 		}
 
-		// Получаем флаги
-		filter, _ := cmd.Flags().GetString("filter")         // фильтр: all, pending, completed
-		importantOnly, _ := cmd.Flags().GetBool("important") // фильтр: важные. Да/нет.
+		// Только незавершённые задачи без открытых зависимостей
+		ready, _ := cmd.Flags().GetBool("ready")
 
-		// Фильтрация по статусу
+		// Фильтрация задач
 		filtered := make([]task.Task, 0)
 		for _, t := range tasks {
-			if importantOnly && !t.Important {
+			if statusOK != nil && !statusOK(t) {
+				continue
+			}
+			if priorityOK != nil && !priorityOK(t.Priority) {
+				continue
+			}
+			if !matchTags(t, includeTags, excludeTags) || !inProject(t) {
 				continue
 			}
-			switch filter {
-			case "pending":
-				if !t.Completed {
-					filtered = append(filtered, t)
-				}
-			case "completed":
-				if t.Completed {
-					filtered = append(filtered, t)
-				}
-			default:
-				filtered = append(filtered, t)
+			if ready && !task.IsReady(tasks, t) {
+				continue
+			}
+			if !q.Match(t, tasks) {
+				continue
+			}
+			// Фильтры по сроку отбрасывают задачи без срока
+			if !dueBefore.IsZero() && (t.DueAt.IsZero() || !t.DueAt.Before(dueBefore)) {
+				continue
+			}
+			if !dueAfter.IsZero() && (t.DueAt.IsZero() || !t.DueAt.After(dueAfter)) {
+				continue
 			}
+			filtered = append(filtered, t)
 		}
 
-		// Сортировка задач
-		switch sortBy {
-		case "name":
-			sort.Slice(filtered, func(i, j int) bool { return filtered[i].Title < filtered[j].Title })
-		case "date":
-			sort.Slice(filtered, func(i, j int) bool { return filtered[i].CreatedAt.Before(filtered[j].CreatedAt) })
-		case "":
-		default:
-			fmt.Println("Неизвестный параметр сортировки. Используйте name или date.")
-			os.Exit(1)
+		// Сортировка задач по ключам --sort
+		task.Sort(filtered, sortKeys, sortLanguage())
+
+		// В машиночитаемом формате (--output) — записи вместо таблицы
+		if structuredOutput() {
+			if archived {
+				writeRecords(nil, filtered)
+			} else {
+				writeRecords(filtered, nil)
+			}
+			return
 		}
 
-		// Вывод заголовков таблицы
-		printTasksTable(filtered)
+		// Вывод задач по шаблону
+		if tmpl == nil {
+			tmpl, _ = loadTemplate("table")
+		}
+		tree, _ := cmd.Flags().GetBool("tree")
+		long, _ := cmd.Flags().GetBool("long")
+		rows := templateRows(filtered, tableOptions{
+			Long:     long,
+			Tree:     tree,
+			Progress: task.ChildProgress(tasks),
+			Blocked:  openBlockersByID(tasks),
+		})
+		for i := range rows {
+			rows[i].InArchive = archived
+		}
+		printTemplate(tmpl, rows, long)
 	},
 }
 
@@ -129,24 +393,56 @@ var listCmd = &cobra.Command{
 // Здесь мы подключаем подкоманду "list" к rootCmd.
 func init() {
 	rootCmd.AddCommand(listCmd)
+	addListFlags(listCmd)
 
-	// Флаги
-	listCmd.Flags().StringP("sort", "s", "", "Сортировка: name или date")
-	listCmd.Flags().StringP("filter", "f", "all", "Фильтр: all, pending, completed")
-	listCmd.Flags().BoolP("important", "i", false, "Показать только важные задачи")
+	// Флаг --view — сохранённое представление (см. todo view)
+	listCmd.Flags().String("view", "", "Применить сохранённое представление (флаги командной строки важнее)")
+	_ = listCmd.RegisterFlagCompletionFunc("view", completeViewNames)
+}
 
-	// Автодополнение для флага --sort
-	_ = listCmd.RegisterFlagCompletionFunc("sort", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"name", "date"}, cobra.ShellCompDirectiveNoFileComp
-	})
+// completeQueryFields — автодополнение флага --query: имена полей запроса.
+func completeQueryFields(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var fields []string
+	for _, name := range query.FieldNames() {
+		fields = append(fields, name+":")
+	}
+	return fields, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// addListFlags подключает к команде флаги отбора, сортировки и вывода
+// команды list. Их же принимает "todo view save", чтобы сохранить представление.
+func addListFlags(cmd *cobra.Command) {
+	addSortFlag(cmd)
+	cmd.Flags().StringP("filter", "f", "all", "Фильтр по статусу: all, pending, completed или состояния через запятую (in-progress,review)")
+	cmd.Flags().BoolP("important", "i", false, "Показать только важные задачи (то же, что --priority=\">=high\")")
+	cmd.Flags().StringP("priority", "p", "", "Фильтр по приоритету: high, >=high, <normal...")
+	cmd.Flags().StringArrayP("tag", "t", nil, "Фильтр по тегу: x — с тегом, -x — без него (можно повторять)")
+	addProjectFilterFlag(cmd)
+	cmd.Flags().BoolP("long", "l", false, "Показать время последнего изменения и выполнения")
+	addFormatFlag(cmd)
+	cmd.Flags().Bool("tree", false, "Показать подзадачи деревом под родительскими задачами")
+	cmd.Flags().StringP("query", "q", "", "Запрос на языке фильтров: status:pending and (tag:infra or priority>=high) and due<+7d")
+	cmd.Flags().Bool("archived", false, "Показать задачи из архива завершённых задач (см. todo archive)")
+	cmd.Flags().Bool("ready", false, "Показать только невыполненные задачи, не ждущие других задач")
+	cmd.Flags().String("due-before", "", "Показать задачи со сроком раньше даты (2025-03-14, tomorrow, +3d, fri)")
+	cmd.Flags().String("due-after", "", "Показать задачи со сроком позже даты (2025-03-14, tomorrow, +3d, fri)")
 
 	// Автодополнение для флага --filter
-	_ = listCmd.RegisterFlagCompletionFunc("filter", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"all", "pending", "completed"}, cobra.ShellCompDirectiveNoFileComp
+	_ = cmd.RegisterFlagCompletionFunc("filter", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return append([]string{"all", "pending", "completed"}, task.StatusNames()...), cobra.ShellCompDirectiveNoFileComp
 	})
 
+	// Автодополнение для флага --tag
+	_ = cmd.RegisterFlagCompletionFunc("tag", completeTags)
+
+	// Автодополнение для флага --query: имена полей запроса
+	_ = cmd.RegisterFlagCompletionFunc("query", completeQueryFields)
+
+	// Автодополнение для флага --priority
+	_ = cmd.RegisterFlagCompletionFunc("priority", completePriorities)
+
 	// Автодополнение для флага --important
-	_ = listCmd.RegisterFlagCompletionFunc("important", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	_ = cmd.RegisterFlagCompletionFunc("important", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"true", "false"}, cobra.ShellCompDirectiveNoFileComp
 	})
 }
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/storage"
+)
+
+// parseAgo разбирает длительность (90m, 24h, 7d, 2w) и возвращает момент,
+// который был на столько раньше now. Второе значение — удалось ли разобрать.
+func parseAgo(s string, now time.Time) (time.Time, bool) {
+	// Дни и недели time.ParseDuration не понимает — считаем сами.
+	if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+		if count, err := strconv.Atoi(s[:n-1]); err == nil && count >= 0 {
+			days := count
+			if s[n-1] == 'w' {
+				days *= 7
+			}
+			return now.AddDate(0, 0, -days), true
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), true
+	}
+	return time.Time{}, false
+}
+
+// parseSince разбирает значение флага --since относительно момента now.
+// Поддерживаются длительности (90m, 24h, 7d, 2w), слова today и yesterday,
+// даты 2006-01-02 и 2006-01-02 15:04 в местном времени, а также RFC 3339.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch s {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if t, ok := parseAgo(s, now); ok {
+		return t, nil
+	}
+
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("не удалось разобрать %q: используйте 24h, 7d, today или дату 2006-01-02", s)
+}
+
+// logCmd — подкоманда "log", которая показывает журнал изменений всех задач.
+// Пример использования:
+//
+//	todo log               — все изменения
+//	todo log --since=24h   — изменения за последние сутки
+//	todo log --since=2025-01-31
+var logCmd = &cobra.Command{
+	Use:   "log",                                  // формат вызова
+	Short: "Показать журнал изменений всех задач", // краткое описание
+	Args:  cobra.NoArgs,                           // аргументы не нужны
+	Run: func(cmd *cobra.Command, args []string) {
+		// Определяем начало периода
+		var since time.Time
+		if value, _ := cmd.Flags().GetString("since"); value != "" {
+			t, err := parseSince(value, time.Now())
+			if err != nil {
+				printError("Некорректное значение --since:", err)
+				return
+			}
+			since = t
+		}
+
+		// Открываем хранилище вместе с журналом аудита
+		store, err := openJournaledStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		events, err := store.Events.Read(func(e storage.Event) bool { return !e.Time.Before(since) })
+		if err != nil {
+			printError("Ошибка при чтении журнала изменений:", err)
+			return
+		}
+		if structuredOutput() {
+			writeEvents(events)
+			return
+		}
+		if len(events) == 0 {
+			fmt.Fprintln(textOut(), "Изменений не найдено.")
+			return
+		}
+
+		for _, e := range events {
+			fmt.Fprintln(textOut(), formatEvent(e))
+		}
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "log" к rootCmd.
+func init() {
+	rootCmd.AddCommand(logCmd)
+
+	logCmd.Flags().String("since", "", "Показать изменения начиная с момента (24h, 7d, today, 2006-01-02)")
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// logTimeCmd — подкоманда "log-time", которая записывает на задачу время,
+// потраченное без таймера. По умолчанию отрезок заканчивается сейчас;
+// флаг --date задаёт момент его начала.
+// Пример использования:
+//
+//	todo log-time 3 1h30m
+//	todo log-time 3 45m --date=yesterday
+//	todo log-time 3 2h --date="2025-03-14 10:00"
+var logTimeCmd = &cobra.Command{
+	Use:               "log-time [task ID] [duration]",        // формат вызова
+	Short:             "Записать потраченное на задачу время", // краткое описание
+	Args:              cobra.ExactArgs(2),                     // ожидаем ID задачи и длительность
+	ValidArgsFunction: completeTaskIDs,                        // автодополнение ID задачи
+	Run: func(cmd *cobra.Command, args []string) {
+		// Конвертируем аргумент в int (ID задачи)
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			printError("Некорректный ID задачи:", args[0])
+			return
+		}
+
+		d, err := time.ParseDuration(args[1])
+		if err != nil || d <= 0 {
+			printErrorf("Ошибка: некорректная длительность %q: используйте, например, 1h30m или 45m\n", args[1])
+			return
+		}
+
+		// Начало отрезка: по умолчанию — так, чтобы он закончился сейчас
+		now := time.Now()
+		start := now.Add(-d)
+		if value, _ := cmd.Flags().GetString("date"); value != "" {
+			if start, err = task.ParseDate(value, now); err != nil {
+				printError("Ошибка в --date:", err)
+				return
+			}
+		}
+
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		var logged task.Task
+		err = modifyTasks(store, "log-time", func(tasks []task.Task) ([]task.Task, error) {
+			i := taskIndex(tasks, id)
+			if i < 0 {
+				return nil, &storage.NotFoundError{ID: id}
+			}
+			tasks[i].LogTime(start, d)
+			logged = tasks[i]
+			return tasks, nil
+		})
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		fmt.Fprintf(textOut(), "На задачу с ID %d записано %s (всего %s).\n", id, formatDuration(d), formatDuration(logged.Tracked(now)))
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "log-time" к rootCmd.
+func init() {
+	rootCmd.AddCommand(logTimeCmd)
+
+	logTimeCmd.Flags().String("date", "", "Начало отрезка: 2025-03-14 10:00, yesterday, mon (по умолчанию отрезок заканчивается сейчас)")
+}
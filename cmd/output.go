@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/output"
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// outputFlag — формат вывода, задаётся глобальным флагом --output:
+// text, json, yaml, csv или tsv.
+var outputFlag = "text"
+
+// outputState — состояние машиночитаемого вывода текущей команды.
+// В машиночитаемом формате текст для человека (подсказки, ошибки) уходит
+// в stderr, а в stdout попадают только записи или итог команды.
+type outputState struct {
+	format  output.Format     // выбранный формат
+	command string            // имя команды для итога, например "tag add"
+	err     string            // первая ошибка команды (см. printError)
+	written bool              // команда сама вывела записи
+	changed map[int]task.Task // созданные и изменённые задачи по ID
+	removed map[int]task.Task // убранные из списка задачи по ID
+}
+
+// out — машиночитаемый вывод текущей команды.
+var out outputState
+
+// structuredOutput сообщает, что выбран машиночитаемый формат вывода.
+func structuredOutput() bool {
+	return out.format.Structured()
+}
+
+// textOut возвращает, куда выводить текст для человека: таблицы,
+// подсказки и ошибки. В машиночитаемом режиме это stderr, чтобы
+// в stdout попадали только записи.
+func textOut() io.Writer {
+	if structuredOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// textOnly сообщает, что команда выводит только текст для человека —
+// справку группы команд (например, todo trash) или скрипт автодополнения —
+// и записей для машиночитаемого вывода у неё нет.
+func textOnly(cmd *cobra.Command) bool {
+	if cmd.HasParent() && cmd.HasSubCommands() {
+		return true
+	}
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		if c.Name() == "help" || c.Name() == "completion" {
+			return true
+		}
+	}
+	return false
+}
+
+// startOutput разбирает флаг --output перед запуском команды. Команды
+// без машиночитаемого вывода в формате, отличном от text, не запускаются.
+func startOutput(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(outputFlag)
+	if err != nil {
+		return err
+	}
+	out = outputState{
+		format:  format,
+		command: strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		changed: make(map[int]task.Task),
+		removed: make(map[int]task.Task),
+	}
+	if format.Structured() && textOnly(cmd) {
+		return fmt.Errorf("формат вывода %s не поддерживается командой %s", format, out.command)
+	}
+	return nil
+}
+
+// finishOutput выводит итог команды, если она не вывела записи сама:
+// затронутые задачи и результат.
+func finishOutput(cmd *cobra.Command, args []string) error {
+	if !structuredOutput() || out.written {
+		return nil
+	}
+
+	now := time.Now()
+	result := output.Result{Command: out.command, OK: out.err == "", Error: out.err}
+	for _, id := range slices.Sorted(maps.Keys(out.changed)) {
+		result.Tasks = append(result.Tasks, output.FromTask(out.changed[id], now))
+	}
+	for _, id := range slices.Sorted(maps.Keys(out.removed)) {
+		result.Removed = append(result.Removed, output.FromTask(out.removed[id], now))
+	}
+	return output.WriteResult(os.Stdout, out.format, result)
+}
+
+// recordChange запоминает задачи, затронутые операцией хранилища,
+// для итога команды (см. JournaledStore.OnChange).
+func recordChange(op storage.Operation) {
+	if !structuredOutput() {
+		return
+	}
+	after := make(map[int]bool, len(op.After))
+	for _, t := range op.After {
+		after[t.ID] = true
+		out.changed[t.ID] = t
+		delete(out.removed, t.ID)
+	}
+	for _, t := range op.Before {
+		if !after[t.ID] {
+			out.removed[t.ID] = t
+			delete(out.changed, t.ID)
+		}
+	}
+}
+
+// writeRecords выводит записи о задачах в выбранном формате вместо таблицы.
+// Задачи из inArchive помечаются как лежащие в архиве завершённых задач.
+func writeRecords(tasks []task.Task, inArchive []task.Task) {
+	now := time.Now()
+	records := output.FromTasks(tasks, now)
+	for _, t := range inArchive {
+		rec := output.FromTask(t, now)
+		rec.InArchive = true
+		records = append(records, rec)
+	}
+	out.written = true
+	if err := output.WriteTasks(os.Stdout, out.format, records); err != nil {
+		printError("Ошибка при выводе задач:", err)
+	}
+}
+
+// writeRecord выводит запись об одной задаче в выбранном формате.
+func writeRecord(t task.Task) {
+	out.written = true
+	if err := output.WriteTask(os.Stdout, out.format, output.FromTask(t, time.Now())); err != nil {
+		printError("Ошибка при выводе задачи:", err)
+	}
+}
+
+// writeReport выводит записи отчёта (корзины, журнала, тегов и т. п.)
+// в выбранном формате вместо таблицы.
+func writeReport[R output.Report](records []R) {
+	out.written = true
+	if err := output.WriteReport(os.Stdout, out.format, records); err != nil {
+		printError("Ошибка при выводе отчёта:", err)
+	}
+}
+
+// printError выводит сообщение об ошибке, как fmt.Println, и запоминает
+// её для итога команды: в машиночитаемом режиме команда завершится с кодом 1.
+func printError(a ...any) {
+	msg := fmt.Sprintln(a...)
+	recordError(a, msg)
+	fmt.Fprint(textOut(), msg)
+}
+
+// printErrorf — то же, что printError, с форматированием как у fmt.Printf.
+func printErrorf(format string, a ...any) {
+	msg := strings.TrimSuffix(fmt.Sprintf(format, a...), "\n") + "\n"
+	recordError(a, msg)
+	fmt.Fprint(textOut(), msg)
+}
+
+// recordError — приватная функция, запоминает первую ошибку команды для
+// поля error итога. Туда попадает только текст самой ошибки из аргументов
+// a, без пояснений для человека; если ошибки среди них нет — сообщение msg
+// без префикса "Ошибка:".
+func recordError(a []any, msg string) {
+	if out.err != "" {
+		return
+	}
+	for _, v := range a {
+		if err, ok := v.(error); ok {
+			out.err = err.Error()
+			return
+		}
+	}
+	out.err = strings.TrimPrefix(strings.TrimSpace(msg), "Ошибка: ")
+}
+
+// outputFailed сообщает, что команда в машиночитаемом режиме завершилась ошибкой.
+func outputFailed() bool {
+	return structuredOutput() && out.err != ""
+}
+
+// init подключает глобальный флаг --output.
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", outputFlag, "Формат вывода: "+strings.Join(output.Names(), ", "))
+	_ = rootCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return output.Names(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	rootCmd.PersistentPreRunE = startOutput
+	rootCmd.PersistentPostRunE = finishOutput
+}
@@ -4,34 +4,80 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
 )
 
 // pendingCmd — подкоманда "pending", которая выводит только невыполненные задачи.
 // Пример использования:
 //
 //	todo pending
+//	todo pending --project=work
+//	todo pending --sort=-priority,due
+//	todo pending --format=standup  — шаблон из каталога настроек (templates/standup.tmpl)
 var pendingCmd = &cobra.Command{
 	Use:   "pending",                              // формат вызова
 	Short: "Показать только невыполненные задачи", // краткое описание
 	Run: func(cmd *cobra.Command, args []string) {
+		// Ключи сортировки --sort
+		sortKeys, err := sortFlag(cmd)
+		if err != nil {
+			printError("Ошибка: неизвестный способ сортировки:", err)
+			return
+		}
+
+		// Шаблон вывода --format
+		tmpl, err := formatFlag(cmd)
+		if err != nil {
+			printError("Ошибка в --format:", err)
+			return
+		}
+
+		// Фильтр по проекту (без него скрываются архивные проекты)
+		inProject, err := projectFilter(cmd)
+		if err != nil {
+			printError("Ошибка в --project:", err)
+			return
+		}
+
 		// Создаём хранилище задач
-		store := storage.NewJSONStore(tasksFile)
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
 
 		// Получаем список всех задач
 		tasks, err := store.ListTasks()
 		if err != nil {
-			fmt.Println("Ошибка при загрузке задач:", err)
+			printError("Ошибка при загрузке задач:", err)
 			return
 		}
 
-		// Выводим все невыполненные задачи
-		fmt.Println("Невыполненные задачи:")
+		// Отбираем и сортируем задачи
+		var matched []task.Task
 		for _, t := range tasks {
-			if !t.Completed {
-				fmt.Printf("[%d] %s\n", t.ID, t.Title)
+			if !t.IsClosed() && inProject(t) {
+				matched = append(matched, t)
 			}
 		}
+		task.Sort(matched, sortKeys, sortLanguage())
+		if structuredOutput() {
+			writeRecords(matched, nil)
+			return
+		}
+		if tmpl != nil {
+			printTemplate(tmpl, templateRows(matched, tableOptions{
+				Progress: task.ChildProgress(tasks),
+				Blocked:  openBlockersByID(tasks),
+			}), false)
+			return
+		}
+
+		fmt.Fprintln(textOut(), "Невыполненные задачи:")
+		for _, t := range matched {
+			fmt.Fprintf(textOut(), "[%d] %s\n", t.ID, t.Title)
+		}
 	},
 }
 
@@ -39,4 +85,9 @@ var pendingCmd = &cobra.Command{
 // Здесь мы подключаем подкоманду "delete" к rootCmd.
 func init() {
 	rootCmd.AddCommand(pendingCmd)
+
+	// Фильтр по проекту, сортировка и шаблон вывода
+	addProjectFilterFlag(pendingCmd)
+	addSortFlag(pendingCmd)
+	addFormatFlag(pendingCmd)
 }
@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/output"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// addProjectFilterFlag подключает к команде флаг --project
+// с автодополнением имён проектов.
+func addProjectFilterFlag(cmd *cobra.Command) {
+	cmd.Flags().String("project", "", "Показать задачи проекта и его подпроектов (например, work или work.release)")
+	_ = cmd.RegisterFlagCompletionFunc("project", completeProjects)
+}
+
+// projectFilter возвращает функцию отбора задач по флагу --project.
+// Без флага скрываются задачи архивных проектов; явно указанный проект
+// показывается целиком, даже если он в архиве.
+func projectFilter(cmd *cobra.Command) (func(task.Task) bool, error) {
+	value, _ := cmd.Flags().GetString("project")
+	project, err := task.NormalizeProject(value)
+	if err != nil {
+		return nil, err
+	}
+	if project == "" {
+		return func(t task.Task) bool { return !t.Archived }, nil
+	}
+	return func(t task.Task) bool { return task.InProject(t.Project, project) }, nil
+}
+
+// projectSummary — сводка по проекту, включая все его подпроекты.
+type projectSummary struct {
+	Name     string // полное имя проекта ("" — задачи без проекта)
+	Total    int    // всего задач
+	Done     int    // из них выполнено
+	Archived bool   // все задачи проекта в архиве
+}
+
+// Percent возвращает долю выполненных задач в процентах.
+func (s projectSummary) Percent() int {
+	if s.Total == 0 {
+		return 0
+	}
+	return s.Done * 100 / s.Total
+}
+
+// summarizeProjects считает прогресс по каждому проекту. Задача подпроекта
+// учитывается и во всех родительских проектах. Результат отсортирован
+// по имени, так что подпроекты идут сразу за родителем, а задачи
+// без проекта — первыми.
+func summarizeProjects(tasks []task.Task) []projectSummary {
+	byName := make(map[string]*projectSummary)
+	archived := make(map[string]bool)
+	for _, t := range tasks {
+		names := task.ProjectAncestors(t.Project)
+		if len(names) == 0 {
+			names = []string{""}
+		}
+		for _, name := range names {
+			s, ok := byName[name]
+			if !ok {
+				s = &projectSummary{Name: name}
+				byName[name] = s
+				archived[name] = true
+			}
+			s.Total++
+			if t.IsClosed() {
+				s.Done++
+			}
+			archived[name] = archived[name] && t.Archived
+		}
+	}
+
+	result := make([]projectSummary, 0, len(byName))
+	for name, s := range byName {
+		s.Archived = archived[name]
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// progressBar рисует полосу прогресса шириной width символов.
+func progressBar(percent, width int) string {
+	filled := percent * width / 100
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
+
+// completeProjects — автодополнение имён существующих проектов.
+func completeProjects(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := openStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	defer func() { _ = store.Close() }()
+	tasks, err := store.ListTasks()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var suggestions []string
+	for _, s := range summarizeProjects(tasks) {
+		if s.Name != "" {
+			suggestions = append(suggestions, s.Name)
+		}
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjectArg — автодополнение проекта в первом аргументе подкоманд project.
+func completeProjectArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeProjects(cmd, args, toComplete)
+}
+
+// projectCmd — подкоманда "project", группирующая действия с проектами.
+// Проект задаётся при добавлении задачи: todo add "..." --project=work.release
+var projectCmd = &cobra.Command{
+	Use:   "project",                    // формат вызова
+	Short: "Управление проектами задач", // краткое описание
+}
+
+// projectListCmd — подкоманда "project list", которая показывает проекты
+// с прогрессом выполнения.
+// Пример использования:
+//
+//	todo project list
+//	todo project list --all   — вместе с архивными
+var projectListCmd = &cobra.Command{
+	Use:   "list",                                   // формат вызова
+	Short: "Показать проекты и прогресс выполнения", // краткое описание
+	Args:  cobra.NoArgs,                             // аргументы не нужны
+	Run: func(cmd *cobra.Command, args []string) {
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		// Получаем список всех задач
+		tasks, err := store.ListTasks()
+		if err != nil {
+			printError("Ошибка при загрузке задач:", err)
+			return
+		}
+
+		showAll, _ := cmd.Flags().GetBool("all")
+		summaries := summarizeProjects(tasks)
+		if structuredOutput() {
+			records := make([]output.Project, 0, len(summaries))
+			for _, s := range summaries {
+				if s.Archived && !showAll {
+					continue
+				}
+				records = append(records, output.Project{
+					Project: s.Name, Total: s.Total, Done: s.Done, Percent: s.Percent(), Archived: s.Archived,
+				})
+			}
+			writeReport(records)
+			return
+		}
+		if len(summaries) == 0 {
+			fmt.Fprintln(textOut(), "Список задач пуст. Добавьте новую с помощью: todo add \"Название задачи\" --project=work")
+			return
+		}
+
+		fmt.Fprintf(textOut(), "\033[36m%-30s %-9s %s\033[0m\n", "PROJECT", "DONE", "PROGRESS")
+		for _, s := range summaries {
+			if s.Archived && !showAll {
+				continue
+			}
+
+			// Подпроекты выводим с отступом по глубине вложенности
+			name := "(без проекта)"
+			if s.Name != "" {
+				depth := strings.Count(s.Name, task.ProjectSeparator)
+				name = strings.Repeat("  ", depth) + s.Name
+			}
+			if s.Archived {
+				name += " (в архиве)"
+			}
+			fmt.Fprintf(textOut(), "%-30s %-9s %s %3d%%\n", name, fmt.Sprintf("%d/%d", s.Done, s.Total), progressBar(s.Percent(), 20), s.Percent())
+		}
+	},
+}
+
+// projectRenameCmd — подкоманда "project rename", которая переименовывает
+// проект вместе со всеми подпроектами.
+// Пример использования:
+//
+//	todo project rename work.release work.ship
+var projectRenameCmd = &cobra.Command{
+	Use:               "rename [project] [new name]", // формат вызова
+	Short:             "Переименовать проект",        // краткое описание
+	Args:              cobra.ExactArgs(2),            // старое и новое имя
+	ValidArgsFunction: completeProjectArg,            // автодополнение имени проекта
+	Run: func(cmd *cobra.Command, args []string) {
+		from, err := task.NormalizeProject(args[0])
+		if err == nil && from == "" {
+			err = fmt.Errorf("нужно указать имя проекта")
+		}
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+		to, err := task.NormalizeProject(args[1])
+		if err == nil && to == "" {
+			err = fmt.Errorf("нужно указать новое имя проекта")
+		}
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+		if task.InProject(to, from) && to != from {
+			printErrorf("Ошибка: нельзя переместить проект %s внутрь самого себя\n", from)
+			return
+		}
+
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		renamed := 0
+		err = modifyTasks(store, "project-rename", func(tasks []task.Task) ([]task.Task, error) {
+			for i := range tasks {
+				if task.InProject(tasks[i].Project, from) {
+					tasks[i].Project = task.RenameProject(tasks[i].Project, from, to)
+					renamed++
+				}
+			}
+			if renamed == 0 {
+				return nil, fmt.Errorf("проект %s не найден", from)
+			}
+			return tasks, nil
+		})
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		fmt.Fprintf(textOut(), "Проект %s переименован в %s (задач: %d).\n", from, to, renamed)
+	},
+}
+
+// projectArchiveCmd — подкоманда "project archive", которая отправляет проект
+// со всеми подпроектами в архив: его задачи пропадают из списков, пока
+// проект не указан явно через --project.
+// Пример использования:
+//
+//	todo project archive work.release
+//	todo project archive work.release --restore
+var projectArchiveCmd = &cobra.Command{
+	Use:               "archive [project]",        // формат вызова
+	Short:             "Отправить проект в архив", // краткое описание
+	Args:              cobra.ExactArgs(1),         // ожидаем имя проекта
+	ValidArgsFunction: completeProjectArg,         // автодополнение имени проекта
+	Run: func(cmd *cobra.Command, args []string) {
+		project, err := task.NormalizeProject(args[0])
+		if err == nil && project == "" {
+			err = fmt.Errorf("нужно указать имя проекта")
+		}
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+		restore, _ := cmd.Flags().GetBool("restore")
+
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		kind := "project-archive"
+		if restore {
+			kind = "project-restore"
+		}
+		found := 0
+		err = modifyTasks(store, kind, func(tasks []task.Task) ([]task.Task, error) {
+			for i := range tasks {
+				if task.InProject(tasks[i].Project, project) {
+					tasks[i].Archived = !restore
+					found++
+				}
+			}
+			if found == 0 {
+				return nil, fmt.Errorf("проект %s не найден", project)
+			}
+			return tasks, nil
+		})
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		if restore {
+			fmt.Fprintf(textOut(), "Проект %s возвращён из архива (задач: %d).\n", project, found)
+		} else {
+			fmt.Fprintf(textOut(), "Проект %s отправлен в архив (задач: %d).\n", project, found)
+		}
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "project" и её действия к rootCmd.
+func init() {
+	projectListCmd.Flags().BoolP("all", "a", false, "Показать и архивные проекты")
+	projectArchiveCmd.Flags().Bool("restore", false, "Вернуть проект из архива")
+
+	projectCmd.AddCommand(projectListCmd, projectRenameCmd, projectArchiveCmd)
+	rootCmd.AddCommand(projectCmd)
+}
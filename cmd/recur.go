@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// recurExamples — подсказки для автодополнения правила повторения.
+var recurExamples = []string{
+	"daily", "weekly", "monthly", "every mon,thu", "every 2 weeks on fri",
+	"monthly on 15", "every 3 days after done", "none",
+}
+
+// recurCmd — подкоманда "recur", которая задаёт правило повторения задачи.
+// После выполнения повторяющейся задачи появляется её следующее повторение
+// со сдвинутым сроком. Правило none делает задачу разовой.
+// Пример использования:
+//
+//	todo recur 3 daily
+//	todo recur 3 "every mon,thu"
+//	todo recur 3 "monthly on 15"
+//	todo recur 3 "every 10 days after done"   — от даты выполнения
+//	todo recur 3 none
+var recurCmd = &cobra.Command{
+	Use:   "recur [task ID] [rule]",           // формат вызова
+	Short: "Задать правило повторения задачи", // краткое описание
+	Args:  cobra.ExactArgs(2),                 // ожидаем ID задачи и правило
+	Run: func(cmd *cobra.Command, args []string) {
+		// Конвертируем аргумент в int (ID задачи)
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			printError("Некорректный ID задачи:", args[0])
+			return
+		}
+
+		rule, err := task.ParseRecurrence(args[1])
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		err = modifyTasks(store, "recur", func(tasks []task.Task) ([]task.Task, error) {
+			i := taskIndex(tasks, id)
+			if i < 0 {
+				return nil, &storage.NotFoundError{ID: id}
+			}
+			tasks[i].Recur = rule
+			return tasks, nil
+		})
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		if rule.IsZero() {
+			fmt.Fprintf(textOut(), "Задача с ID %d больше не повторяется.\n", id)
+		} else {
+			fmt.Fprintf(textOut(), "Задача с ID %d повторяется: %s\n", id, rule)
+		}
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "recur" к rootCmd.
+func init() {
+	recurCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completeTaskIDs(cmd, args, toComplete)
+		}
+		return recurExamples, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	rootCmd.AddCommand(recurCmd)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// redoCmd — подкоманда "redo", которая повторяет операцию,
+// отменённую последней командой "undo".
+// Пример использования:
+//
+//	todo redo
+var redoCmd = &cobra.Command{
+	Use:   "redo",                          // формат вызова
+	Short: "Повторить отменённую операцию", // краткое описание
+	Args:  cobra.NoArgs,                    // аргументы не нужны
+	Run: func(cmd *cobra.Command, args []string) {
+		// Открываем хранилище вместе с журналом операций
+		store, err := openJournaledStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		op, err := store.Redo()
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+		fmt.Fprintln(textOut(), "Повторена операция:", formatOperation(op))
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "redo" к rootCmd.
+func init() {
+	rootCmd.AddCommand(redoCmd)
+}
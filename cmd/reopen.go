@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// reopenCmd — подкоманда "reopen", которая возвращает выполненные
+// и отменённые задачи в работу (статус todo). Задачи выбираются так же,
+// как в "todo done": по ID, диапазонам ID и фильтрам --tag и --filter,
+// и открываются одной операцией.
+// Пример использования:
+//
+//	todo reopen 3
+//	todo reopen 3 5 7-9
+//	todo reopen --tag=sprint --filter=completed
+var reopenCmd = &cobra.Command{
+	Use:   "reopen [task ID]...",                 // формат вызова
+	Short: "Вернуть завершённые задачи в работу", // краткое описание
+	Run: func(cmd *cobra.Command, args []string) {
+		// Разбираем ID, диапазоны и фильтры
+		sel, err := parseSelection(cmd, args)
+		if err != nil {
+			printError(err)
+			return
+		}
+
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		// Выбор и изменение задач — одна атомарная операция
+		var result bulkResult
+		err = modifyTasks(store, "reopen", func(tasks []task.Task) ([]task.Task, error) {
+			ids, missing, err := sel.resolve(tasks)
+			if err != nil {
+				return nil, err
+			}
+			result = bulkResult{Missing: missing}
+			for _, id := range ids {
+				i := taskIndex(tasks, id)
+				if !tasks[i].IsClosed() {
+					result.Skipped = append(result.Skipped, id)
+					continue
+				}
+				tasks[i].Reopen()
+				result.Changed = append(result.Changed, id)
+			}
+			return tasks, nil
+		})
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		result.print("Задача с ID %d снова в работе.", "Возвращено в работу задач:", "Уже в работе")
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "reopen" к rootCmd.
+func init() {
+	reopenCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		store, err := openStore()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		defer func() { _ = store.Close() }()
+		tasks, err := store.ListTasks()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		var suggestions []string
+		for _, t := range tasks {
+			if t.IsClosed() { // показываем только завершённые
+				suggestions = append(suggestions, fmt.Sprint(t.ID))
+			}
+		}
+		return suggestions, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	rootCmd.AddCommand(reopenCmd)
+
+	// Флаги выбора задач: --tag и --filter
+	addSelectionFlags(reopenCmd)
+}
@@ -1,33 +1,214 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
 )
 
 // tasksFile — путь к JSON-хранилищу задач.
 // По умолчанию используется "tasks.json", в тестах можно подменить.
 var tasksFile = "tasks.json"
 
+// dbFile — путь к базе SQLite, используется при --backend=sqlite.
+var dbFile = "tasks.db"
+
+// lockTimeout — сколько ждать, пока другой процесс освободит файл задач.
+var lockTimeout = storage.DefaultLockTimeout
+
+// backend — тип хранилища задач: json или sqlite.
+// Задаётся глобальным флагом --backend и учитывается всеми командами.
+var backend = "json"
+
+// configDir — каталог настроек (например, workflow.json). Задаётся
+// переменной окружения TODO_CONFIG_DIR; пустое значение — подкаталог todo
+// системного каталога настроек (~/.config/todo в Linux).
+var configDir = os.Getenv("TODO_CONFIG_DIR")
+
+// stdin — источник ответов пользователя на вопросы команд
+// (например, delete задачи с подзадачами). В тестах подменяется.
+var stdin io.Reader = os.Stdin
+
 // rootCmd — это корневая команда CLI.
 // К ней будут добавляться все подкоманды (например, add, list, done).
 var rootCmd = &cobra.Command{
 	Use:   "todo",                              // имя исполняемой команды
 	Short: "ToDo CLI — простой менеджер задач", // краткое описание
 	Long: `Todo CLI — это минималистичный менеджер задач.
-Позволяет добавлять, просматривать, отмечать и удалять задачи прямо из терминала.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Используйте подкоманды, например: todo add \"купить хлеб\"")
+Позволяет добавлять, просматривать, отмечать и удалять задачи прямо из терминала.
+
+"todo <имя>" показывает задачи сохранённого представления (см. todo view).`,
+	Args:                       cobra.MaximumNArgs(1), // имя сохранённого представления
+	ValidArgsFunction:          completeViewNames,     // автодополнение имён представлений
+	SuggestionsMinimumDistance: 2,                     // подсказки команд при опечатке, как у cobra
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			fmt.Fprintln(textOut(), "Используйте подкоманды, например: todo add \"купить хлеб\"")
+			return nil
+		}
+
+		// "todo <имя>" — то же, что "todo list --view=<имя>"
+		views, err := openViews()
+		if err != nil {
+			printError("Ошибка при открытии представлений:", err)
+			return nil
+		}
+		if _, err := views.Get(args[0]); err != nil {
+			var notFound *storage.ViewNotFoundError
+			if !errors.As(err, &notFound) {
+				printError("Ошибка при чтении представлений:", err)
+				return nil
+			}
+			// Опечатка в имени команды — ошибка с кодом выхода 1,
+			// как у cobra для неизвестной команды, но без справки.
+			cmd.SilenceUsage = true
+			msg := fmt.Sprintf("неизвестная команда или представление %q. Список команд: todo help, представлений: todo view list", args[0])
+			if suggestions := cmd.SuggestionsFor(args[0]); len(suggestions) > 0 {
+				msg += "\nВозможно, вы имели в виду: " + strings.Join(suggestions, ", ")
+			}
+			return errors.New(msg)
+		}
+		runView(args[0])
+		return nil
 	},
 }
 
+// openStore открывает хранилище задач, выбранное флагом --backend.
+// Все изменения через него записываются в журнал для undo/redo.
+// Вызывающий обязан закрыть хранилище через Close.
+func openStore() (storage.Storage, error) {
+	store, err := openJournaledStore()
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// storePath возвращает путь к хранилищу, выбранному флагом --backend.
+// Рядом с ним лежат служебные файлы: журнал, корзина, архив, представления
+// (путь хранилища с суффиксом, например tasks.json.trash).
+func storePath() (string, error) {
+	switch backend {
+	case "json":
+		return tasksFile, nil
+	case "sqlite":
+		return dbFile, nil
+	}
+	return "", fmt.Errorf("неизвестное хранилище %q, используйте json или sqlite", backend)
+}
+
+// openJournaledStore открывает хранилище, выбранное флагом --backend,
+// вместе с журналом операций, журналом аудита, корзиной и архивом. Все они
+// лежат рядом с файлом задач (например, tasks.json.journal, tasks.json.history,
+// tasks.json.trash и каталог tasks.json.archive).
+func openJournaledStore() (*storage.JournaledStore, error) {
+	path, err := storePath()
+	if err != nil {
+		return nil, err
+	}
+
+	var base storage.Storage
+	switch backend {
+	case "json":
+		store := storage.NewJSONStore(path)
+		store.LockTimeout = lockTimeout
+		base = store
+	case "sqlite":
+		store, err := storage.NewSQLiteStore(path)
+		if err != nil {
+			return nil, err
+		}
+		base = store
+	}
+
+	journal := storage.NewJournal(path + ".journal")
+	journal.LockTimeout = lockTimeout
+	events := storage.NewEventLog(path + ".history")
+	events.LockTimeout = lockTimeout
+	trash := storage.NewTrash(path + ".trash")
+	trash.LockTimeout = lockTimeout
+	archive := storage.NewArchive(path + ".archive")
+	archive.LockTimeout = lockTimeout
+
+	store := storage.NewJournaledStore(base, journal)
+	store.Events = events
+	store.Trash = trash
+	store.Archive = archive
+	store.OnChange = recordChange
+	return store, nil
+}
+
+// modifyTasks выполняет массовую операцию над задачами и подписывает её
+// в журнале именем команды, чтобы "todo undo --list" показывал, что именно
+// будет отменено.
+func modifyTasks(store storage.Storage, kind string, fn func(tasks []task.Task) ([]task.Task, error)) error {
+	if js, ok := store.(*storage.JournaledStore); ok {
+		return js.ModifyAs(kind, fn)
+	}
+	return store.Modify(fn)
+}
+
+// configPath возвращает путь к файлу name в каталоге настроек.
+func configPath(name string) (string, error) {
+	dir := configDir
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("не удалось определить каталог настроек: %w", err)
+		}
+		dir = filepath.Join(base, "todo")
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// completeTaskIDs — автодополнение ID всех задач (для флагов вроде --parent).
+func completeTaskIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := openStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	defer func() { _ = store.Close() }()
+	tasks, err := store.ListTasks()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var suggestions []string
+	for _, t := range tasks {
+		suggestions = append(suggestions, fmt.Sprint(t.ID))
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
 // Execute — функция, которая запускает корневую команду.
-// Если возникнет ошибка, приложение завершится с кодом 1.
+// Если возникнет ошибка, приложение завершится с кодом 1; с --output
+// в машиночитаемом формате — и при ошибке, о которой сообщила сама команда.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(textOut(), err)
 		os.Exit(1)
 	}
+	if outputFailed() {
+		os.Exit(1)
+	}
+}
+
+// init подключает глобальные флаги, общие для всех подкоманд.
+func init() {
+	rootCmd.PersistentFlags().StringVar(&backend, "backend", backend, "Хранилище задач: json или sqlite")
+	rootCmd.PersistentFlags().StringVar(&tasksFile, "file", tasksFile, "Путь к JSON-файлу задач")
+	rootCmd.PersistentFlags().StringVar(&dbFile, "db", dbFile, "Путь к базе SQLite (для --backend=sqlite)")
+	rootCmd.PersistentFlags().DurationVar(&lockTimeout, "lock-timeout", lockTimeout, "Сколько ждать блокировку файла задач другим процессом")
+
+	// Автодополнение для флага --backend
+	_ = rootCmd.RegisterFlagCompletionFunc("backend", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json", "sqlite"}, cobra.ShellCompDirectiveNoFileComp
+	})
 }
@@ -3,50 +3,133 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/query"
+	"github.com/zen-flo/todo-cli/internal/task"
 )
 
-// searchCmd — подкоманда "search", которая ищет задачи по ключевому слову
-// в их названии (Title). Поиск нечувствителен к регистру.
+// searchCmd — подкоманда "search", которая ищет задачи по тексту в названии
+// без учёта регистра. Текст ищется целиком, как есть: двоеточия, скобки,
+// кавычки и слова and/or/not не имеют особого смысла. Запрос на языке
+// фильтров (см. internal/query) задаётся флагом --query и дополняет текст.
 // Пример использования:
 //
 //	todo search хлеб
+//	todo search "fix: login"
+//	todo search --query 'title~"deploy" and status:pending'
+//	todo search релиз -q 'tag:infra or priority>=high'
+//	todo search релиз --project=work --sort=-priority,title
+//	todo search релиз --include-archive  — искать и в архиве завершённых задач
+//	todo search релиз --format '{{.ID}} {{.Title}}'
 var searchCmd = &cobra.Command{
-	Use:   "search [keyword]",                // формат вызова
-	Short: "Найти задачи по ключевому слову", // краткое описание
-	Args:  cobra.ExactArgs(1),                // ожидаем ровно один аргумент — слово для поиска
+	Use:   "search [text]",                      // формат вызова
+	Short: "Найти задачи по тексту или запросу", // краткое описание
+	Args:  cobra.ArbitraryArgs,                  // текст может быть разбит оболочкой на несколько аргументов
 	Run: func(cmd *cobra.Command, args []string) {
-		keyword := strings.ToLower(args[0]) // приводим к нижнему регистру для нечувствительного поиска
+		// Текст ищется в названии целиком; запрос --query разбирается отдельно
+		text := strings.Join(args, " ")
+		queryText, _ := cmd.Flags().GetString("query")
+		if strings.TrimSpace(text) == "" && strings.TrimSpace(queryText) == "" {
+			printError("Ошибка: укажите текст для поиска или запрос --query")
+			return
+		}
+		q, err := query.Parse(queryText, time.Now())
+		if err != nil {
+			printQueryError("Ошибка в --query", queryText, err)
+			return
+		}
+		keyword := strings.ToLower(text)
+		match := func(t task.Task, tasks []task.Task) bool {
+			return strings.Contains(strings.ToLower(t.Title), keyword) && q.Match(t, tasks)
+		}
+
+		// Ключи сортировки --sort
+		sortKeys, err := sortFlag(cmd)
+		if err != nil {
+			printError("Ошибка: неизвестный способ сортировки:", err)
+			return
+		}
+
+		// Шаблон вывода --format
+		tmpl, err := formatFlag(cmd)
+		if err != nil {
+			printError("Ошибка в --format:", err)
+			return
+		}
+
+		// Фильтр по проекту (без него скрываются архивные проекты)
+		inProject, err := projectFilter(cmd)
+		if err != nil {
+			printError("Ошибка в --project:", err)
+			return
+		}
 
 		// Создаём хранилище задач
-		store := storage.NewJSONStore(tasksFile)
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
 
 		// Загружаем все задачи
 		tasks, err := store.ListTasks()
 		if err != nil {
-			fmt.Println("Ошибка при загрузке задач:", err)
+			printError("Ошибка при загрузке задач:", err)
 			return
 		}
 
-		// Фильтруем задачи по ключевому слову
-		fmt.Printf("Результаты поиска по \"%s\":\n", args[0])
-		found := false
+		// По флагу --include-archive ищем и среди задач архива
+		var archived []task.Task
+		if includeArchive, _ := cmd.Flags().GetBool("include-archive"); includeArchive {
+			if archived, err = archivedTasks(store); err != nil {
+				printError("Ошибка при загрузке архива:", err)
+				return
+			}
+		}
+
+		// Фильтруем задачи по запросу и сортируем: найденные в архиве — после активных
+		var found, foundArchived []task.Task
 		for _, t := range tasks {
-			if strings.Contains(strings.ToLower(t.Title), keyword) {
-				status := "❌"
-				if t.Completed {
-					status = "✅"
-				}
-				fmt.Printf("[%s] %d: %s\n", status, t.ID, t.Title)
-				found = true
+			if match(t, tasks) && inProject(t) {
+				found = append(found, t)
+			}
+		}
+		for _, t := range archived {
+			if match(t, tasks) && inProject(t) {
+				foundArchived = append(foundArchived, t)
+			}
+		}
+		task.Sort(found, sortKeys, sortLanguage())
+		task.Sort(foundArchived, sortKeys, sortLanguage())
+		if structuredOutput() {
+			writeRecords(found, foundArchived)
+			return
+		}
+		if tmpl != nil {
+			opts := tableOptions{Progress: task.ChildProgress(tasks), Blocked: openBlockersByID(tasks)}
+			rows := templateRows(found, opts)
+			for _, row := range templateRows(foundArchived, opts) {
+				row.InArchive = true
+				rows = append(rows, row)
 			}
+			printTemplate(tmpl, rows, false)
+			return
+		}
+
+		fmt.Fprintf(textOut(), "Результаты поиска по \"%s\":\n", strings.TrimSpace(strings.Join([]string{text, queryText}, " ")))
+		for _, t := range found {
+			fmt.Fprintf(textOut(), "[%s] %d: %s\n", formatStatus(t.Status), t.ID, t.Title)
+		}
+		for _, t := range foundArchived {
+			fmt.Fprintf(textOut(), "[%s] %d: %s \033[90m(в архиве)\033[0m\n", formatStatus(t.Status), t.ID, t.Title)
 		}
 
 		// Если задач не найдено — выводим сообщение
-		if !found {
-			fmt.Println("Задачи не найдены.")
+		if len(found) == 0 && len(foundArchived) == 0 {
+			fmt.Fprintln(textOut(), "Задачи не найдены.")
 		}
 	},
 }
@@ -55,4 +138,16 @@ var searchCmd = &cobra.Command{
 // Здесь мы подключаем подкоманду "search" к rootCmd.
 func init() {
 	rootCmd.AddCommand(searchCmd)
+
+	// Фильтр по проекту, сортировка и шаблон вывода
+	addProjectFilterFlag(searchCmd)
+	addSortFlag(searchCmd)
+	addFormatFlag(searchCmd)
+
+	// Запрос на языке фильтров
+	searchCmd.Flags().StringP("query", "q", "", "Запрос на языке фильтров: status:pending and (tag:infra or priority>=high)")
+	_ = searchCmd.RegisterFlagCompletionFunc("query", completeQueryFields)
+
+	// Поиск в архиве завершённых задач
+	searchCmd.Flags().Bool("include-archive", false, "Искать также среди задач архива (см. todo archive)")
 }
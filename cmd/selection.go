@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// maxRangeSize — сколько ID можно выбрать одним диапазоном (3-7).
+const maxRangeSize = 10000
+
+// selection — задачи, выбранные аргументами массовой команды (done, reopen,
+// delete): ID, диапазоны ID и фильтры --tag и --filter.
+type selection struct {
+	ids   []int                // явно указанные ID по возрастанию, без повторов
+	match func(task.Task) bool // фильтр (nil — без фильтра)
+}
+
+// addSelectionFlags добавляет команде флаги выбора задач --tag и --filter.
+func addSelectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayP("tag", "t", nil, "Выбрать задачи по тегу: x — с тегом, -x — без него (можно повторять)")
+	cmd.Flags().StringP("filter", "f", "", "Выбрать задачи по статусу: all, pending, completed или состояния через запятую")
+
+	_ = cmd.RegisterFlagCompletionFunc("tag", completeTags)
+	_ = cmd.RegisterFlagCompletionFunc("filter", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return append([]string{"all", "pending", "completed"}, task.StatusNames()...), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// parseIDArgs разбирает ID задач: отдельные ID (3), диапазоны (3-7)
+// и списки через запятую (1,4-6). Возвращает ID по возрастанию без повторов.
+func parseIDArgs(args []string) ([]int, error) {
+	var ids []int
+	for _, arg := range args {
+		for _, part := range strings.Split(arg, ",") {
+			part = strings.TrimSpace(part)
+			from, to, isRange := strings.Cut(part, "-")
+			if !isRange {
+				to = from
+			}
+			first, errFrom := strconv.Atoi(from)
+			last, errTo := strconv.Atoi(to)
+			if errFrom != nil || errTo != nil || first < 1 || last < first {
+				return nil, fmt.Errorf("Некорректный ID задачи: %s", part)
+			}
+			if last-first >= maxRangeSize {
+				return nil, fmt.Errorf("Слишком большой диапазон ID: %s (не больше %d задач)", part, maxRangeSize)
+			}
+			for id := first; id <= last; id++ {
+				ids = append(ids, id)
+			}
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids), nil
+}
+
+// parseSelection разбирает аргументы и флаги выбора задач. Нужно указать
+// хотя бы один ID или фильтр; если указано и то и другое, из перечисленных
+// задач выбираются подходящие под фильтр.
+func parseSelection(cmd *cobra.Command, args []string) (selection, error) {
+	ids, err := parseIDArgs(args)
+	if err != nil {
+		return selection{}, err
+	}
+	sel := selection{ids: ids}
+
+	var filters []func(task.Task) bool
+	tagValues, _ := cmd.Flags().GetStringArray("tag")
+	if len(tagValues) > 0 {
+		include, exclude, err := parseTagArgs(tagValues)
+		if err != nil {
+			return selection{}, fmt.Errorf("Ошибка в --tag: %w", err)
+		}
+		filters = append(filters, func(t task.Task) bool { return matchTags(t, include, exclude) })
+	}
+	if cmd.Flags().Changed("filter") {
+		value, _ := cmd.Flags().GetString("filter")
+		statusOK, err := parseStatusFilter(value)
+		if err != nil {
+			return selection{}, fmt.Errorf("Ошибка в --filter: %w", err)
+		}
+		if statusOK == nil {
+			statusOK = func(task.Task) bool { return true } // all — все задачи
+		}
+		filters = append(filters, statusOK)
+	}
+
+	if len(ids) == 0 && len(filters) == 0 {
+		return selection{}, errors.New("Ошибка: укажите ID задач, диапазон (3-7) или фильтр --tag/--filter")
+	}
+	if len(filters) > 0 {
+		sel.match = func(t task.Task) bool {
+			for _, ok := range filters {
+				if !ok(t) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return sel, nil
+}
+
+// resolve возвращает ID выбранных задач из списка tasks и явно указанные ID,
+// которых в списке нет. Если ни одна задача не выбрана, возвращает ошибку.
+func (s selection) resolve(tasks []task.Task) (ids, missing []int, err error) {
+	byID := make(map[int]task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	if len(s.ids) == 0 {
+		for _, t := range tasks {
+			if s.match(t) {
+				ids = append(ids, t.ID)
+			}
+		}
+	}
+	for _, id := range s.ids {
+		t, ok := byID[id]
+		switch {
+		case !ok:
+			missing = append(missing, id)
+		case s.match == nil || s.match(t):
+			ids = append(ids, id)
+		}
+	}
+
+	switch {
+	case len(ids) > 0:
+		return ids, missing, nil
+	case len(missing) == 1 && len(s.ids) == 1:
+		return nil, missing, &storage.NotFoundError{ID: missing[0]}
+	case len(missing) == len(s.ids) && len(missing) > 0:
+		return nil, missing, fmt.Errorf("задачи не найдены: %s", formatIDs(missing))
+	}
+	return nil, missing, errors.New("нет задач, подходящих под условия выбора")
+}
+
+// bulkResult — итог массовой операции над задачами.
+type bulkResult struct {
+	Changed []int // задачи, к которым применена операция
+	Skipped []int // задачи, которые уже были в нужном состоянии
+	Missing []int // указанные ID, которых нет в списке задач
+}
+
+// print выводит итог операции: для одной задачи — фразой single
+// (с её ID), для нескольких — фразой many (с числом и списком ID),
+// затем пропущенные задачи с пояснением skipped и ненайденные ID.
+func (r bulkResult) print(single, many, skipped string) {
+	switch len(r.Changed) {
+	case 0:
+	case 1:
+		fmt.Fprintf(textOut(), single+"\n", r.Changed[0])
+	default:
+		fmt.Fprintf(textOut(), many+" %d (%s).\n", len(r.Changed), formatIDs(r.Changed))
+	}
+	if len(r.Skipped) > 0 {
+		fmt.Fprintf(textOut(), "%s: %s.\n", skipped, formatIDs(r.Skipped))
+	}
+	if len(r.Missing) > 0 {
+		printErrorf("Не найдены задачи: %s.\n", formatIDs(r.Missing))
+	}
+}
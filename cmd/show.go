@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// taskRef форматирует ссылку на задачу: [3] Название.
+// Задачу, которой нет в списке, показывает только по ID.
+func taskRef(byID map[int]task.Task, id int) string {
+	if t, ok := byID[id]; ok {
+		return fmt.Sprintf("[%d] %s", id, t.Title)
+	}
+	return fmt.Sprintf("[%d]", id)
+}
+
+// printTaskDetails выводит все сведения о задаче t; tasks нужны,
+// чтобы показать родителя, подзадачи и зависимости.
+func printTaskDetails(t task.Task, tasks []task.Task) {
+	now := time.Now()
+	byID := make(map[int]task.Task, len(tasks))
+	for _, other := range tasks {
+		byID[other.ID] = other
+	}
+	field := func(name, value string) {
+		fmt.Fprintf(textOut(), "%-13s %s\n", name+":", value)
+	}
+
+	fmt.Fprintf(textOut(), "\033[36m[%d] %s\033[0m\n", t.ID, priorityIcons[t.Priority]+t.Title)
+
+	field("Статус", formatStatus(t.Status)+" "+t.Status.String())
+	field("Приоритет", t.Priority.String())
+	if !t.DueAt.IsZero() {
+		due := formatDue(t)
+		if t.IsOverdue(now) {
+			due += " \033[33m(просрочена)\033[0m"
+		}
+		field("Срок", due)
+	}
+	field("Создана", t.CreatedAt.Format("2006-01-02 15:04"))
+	if !t.UpdatedAt.IsZero() {
+		field("Изменена", formatTime(t.UpdatedAt))
+	}
+	if !t.CompletedAt.IsZero() {
+		field("Выполнена", formatTime(t.CompletedAt))
+	}
+	if t.Project != "" {
+		project := t.Project
+		if t.Archived {
+			project += " (архив)"
+		}
+		field("Проект", project)
+	}
+	if len(t.Tags) > 0 {
+		field("Теги", "#"+strings.Join(t.Tags, " #"))
+	}
+	if t.ParentID != 0 {
+		field("Родитель", taskRef(byID, t.ParentID))
+	}
+	if p, ok := task.ChildProgress(tasks)[t.ID]; ok {
+		field("Подзадачи", p.String()+" выполнено")
+	}
+	if len(t.BlockedBy) > 0 {
+		refs := make([]string, 0, len(t.BlockedBy))
+		for _, id := range t.BlockedBy {
+			ref := taskRef(byID, id)
+			if byID[id].IsClosed() {
+				ref += " ✅"
+			}
+			refs = append(refs, ref)
+		}
+		field("Ждёт", strings.Join(refs, ", "))
+	}
+	if !t.Recur.IsZero() {
+		field("Повторение", t.Recur.String())
+	}
+	if len(t.TimeLog) > 0 {
+		tracked := formatDuration(t.Tracked(now))
+		if t.Running() {
+			tracked += " (идёт таймер: " + formatDuration(t.TimeLog[len(t.TimeLog)-1].Duration(now)) + ")"
+		}
+		field("Учтено", tracked)
+	}
+
+	if t.Notes != "" {
+		fmt.Fprintln(textOut())
+		fmt.Fprintln(textOut(), "Заметки:")
+		for _, line := range strings.Split(t.Notes, "\n") {
+			fmt.Fprintln(textOut(), "  "+line)
+		}
+	}
+}
+
+// showCmd — подкоманда "show", которая выводит все сведения о задаче:
+// статус, приоритет, срок, проект, теги, подзадачи, зависимости,
+// учтённое время и заметки.
+// Пример использования:
+//
+//	todo show 3
+var showCmd = &cobra.Command{
+	Use:               "show [task ID]",                 // формат вызова
+	Short:             "Показать все сведения о задаче", // краткое описание
+	Args:              cobra.ExactArgs(1),               // ожидаем ровно один аргумент — ID задачи
+	ValidArgsFunction: completeTaskIDs,                  // автодополнение ID задачи
+	Run: func(cmd *cobra.Command, args []string) {
+		// Конвертируем аргумент в int (ID задачи)
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			printError("Некорректный ID задачи:", args[0])
+			return
+		}
+
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		tasks, err := store.ListTasks()
+		if err != nil {
+			printError("Ошибка при загрузке задач:", err)
+			return
+		}
+		i := taskIndex(tasks, id)
+		if i < 0 {
+			printErrorf("Ошибка: задача с ID %d не найдена\n", id)
+			return
+		}
+
+		if structuredOutput() {
+			writeRecord(tasks[i])
+			return
+		}
+		printTaskDetails(tasks[i], tasks)
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "show" к rootCmd.
+func init() {
+	rootCmd.AddCommand(showCmd)
+}
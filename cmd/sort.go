@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/task"
+	"golang.org/x/text/language"
+)
+
+// addSortFlag добавляет команде флаг --sort с ключами сортировки через
+// запятую (-priority,due,title) и автодополнением полей.
+func addSortFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("sort", "s", "", "Сортировка по полям через запятую, минус — по убыванию: -priority,due,title (поля: "+
+		strings.Join(task.SortFieldNames(), ", ")+")")
+	_ = cmd.RegisterFlagCompletionFunc("sort", completeSortKeys)
+}
+
+// sortFlag разбирает ключи сортировки из флага --sort.
+func sortFlag(cmd *cobra.Command) ([]task.SortKey, error) {
+	value, _ := cmd.Flags().GetString("sort")
+	return task.ParseSort(value)
+}
+
+// sortLanguage возвращает язык для сравнения названий задач по переменным
+// окружения LC_ALL, LC_COLLATE и LANG (ru_RU.UTF-8 → ru-RU). Без них,
+// а также для C и POSIX используется общий для всех языков порядок.
+func sortLanguage() language.Tag {
+	for _, name := range []string{"LC_ALL", "LC_COLLATE", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		value, _, _ = strings.Cut(value, ".")
+		value, _, _ = strings.Cut(value, "@")
+		if value == "C" || value == "POSIX" {
+			return language.Und
+		}
+		tag, err := language.Parse(strings.ReplaceAll(value, "_", "-"))
+		if err != nil {
+			return language.Und
+		}
+		return tag
+	}
+	return language.Und
+}
+
+// completeSortKeys — автодополнение флага --sort: очередное поле после
+// запятой, по возрастанию или с минусом по убыванию. Уже выбранные поля
+// не предлагаются.
+func completeSortKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix = toComplete[:i+1]
+	}
+	var used []string
+	for _, part := range strings.Split(prefix, ",") {
+		used = append(used, strings.TrimLeft(strings.TrimSpace(part), "+-"))
+	}
+
+	var suggestions []string
+	for _, field := range task.SortFieldNames() {
+		if slices.Contains(used, field) {
+			continue
+		}
+		suggestions = append(suggestions, prefix+field, prefix+"-"+field)
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// workflowFile — имя файла рабочего процесса в каталоге настроек.
+const workflowFile = "workflow.json"
+
+// loadWorkflow читает допустимые переходы между статусами из workflow.json
+// в каталоге настроек. Файл задаёт переходы для перечисленных в нём статусов,
+// для остальных действуют переходы по умолчанию. Без файла используется
+// рабочий процесс по умолчанию.
+//
+//	{"todo": ["in-progress"], "in-progress": ["review"], "review": ["done", "in-progress"]}
+func loadWorkflow() (task.Workflow, error) {
+	workflow := task.DefaultWorkflow()
+
+	path, err := configPath(workflowFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return workflow, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var custom task.Workflow
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return nil, fmt.Errorf("некорректный файл %s: %w", path, err)
+	}
+	maps.Copy(workflow, custom)
+	return workflow, nil
+}
+
+// setStatus переводит задачу id в статус to в момент now. Выполнение
+// подчиняется тем же правилам, что и "todo done" (см. closeWithSubtasks):
+// задачу с невыполненными подзадачами можно закрыть только вместе с ними
+// (cascade), а задачу, которая ждёт другие задачи, — только с force.
+// Отмена тоже требует cascade для невыполненных подзадач и отменяет их
+// вместе с задачей, останавливая таймеры. Возвращает новый список задач
+// и подзадачи, закрытые вместе с задачей.
+func setStatus(tasks []task.Task, id int, to task.Status, cascade, force bool, now time.Time) ([]task.Task, []int, error) {
+	switch to {
+	case task.StatusDone:
+		updated, _, subtasks, _, err := closeWithSubtasks(tasks, []int{id}, cascade, force, now)
+		return updated, subtasks, err
+	case task.StatusCancelled:
+		if tasks[taskIndex(tasks, id)].Status == task.StatusCancelled {
+			return tasks, nil, nil
+		}
+		open := openDescendants(tasks, id)
+		if len(open) > 0 && !cascade {
+			return nil, nil, fmt.Errorf("у задачи с ID %d есть невыполненные подзадачи: %s. "+
+				"Завершите их или используйте --cascade", id, formatIDs(open))
+		}
+		for i := range tasks {
+			if tasks[i].ID == id || slices.Contains(open, tasks[i].ID) {
+				tasks[i].StopTimer(now)
+				tasks[i].Status = task.StatusCancelled
+			}
+		}
+		return tasks, open, nil
+	}
+	tasks[taskIndex(tasks, id)].Status = to
+	return tasks, nil, nil
+}
+
+// statusCmd — подкоманда "status", которая переводит задачу в другой статус:
+// todo, in-progress, blocked, review, done или cancelled. Допустимые переходы
+// задаются файлом workflow.json в каталоге настроек (см. loadWorkflow),
+// флаг --force позволяет их обойти. Команды done и complete-all — короткий
+// путь к статусу done без проверки переходов. Для done и cancelled
+// действуют правила "todo done": задачу с невыполненными подзадачами можно
+// закрыть только с --cascade, а задачу, которая ждёт другие задачи, выполнить
+// только с --force.
+// Пример использования:
+//
+//	todo status 3 in-progress
+//	todo status 3 review
+//	todo status 3 todo --force
+//	todo status 3 cancelled --cascade  — вместе с невыполненными подзадачами
+var statusCmd = &cobra.Command{
+	Use:   "status [task ID] [state]", // формат вызова
+	Short: "Изменить статус задачи",   // краткое описание
+	Args:  cobra.ExactArgs(2),         // ожидаем ID задачи и статус
+	Run: func(cmd *cobra.Command, args []string) {
+		// Конвертируем аргумент в int (ID задачи)
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			printError("Некорректный ID задачи:", args[0])
+			return
+		}
+
+		to, err := task.ParseStatus(args[1])
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		workflow, err := loadWorkflow()
+		if err != nil {
+			printError("Ошибка при чтении рабочего процесса:", err)
+			return
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		cascade, _ := cmd.Flags().GetBool("cascade")
+
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		// Проверка перехода и смена статуса — одна операция
+		var (
+			from     task.Status
+			maxID    int
+			subtasks []int
+		)
+		err = modifyTasks(store, "status", func(tasks []task.Task) ([]task.Task, error) {
+			i := taskIndex(tasks, id)
+			if i < 0 {
+				return nil, &storage.NotFoundError{ID: id}
+			}
+			from, maxID = tasks[i].Status, maxTaskID(tasks)
+			if !force {
+				if err := workflow.Check(from, to); err != nil {
+					return nil, err
+				}
+			}
+			updated, children, err := setStatus(tasks, id, to, cascade, force, time.Now())
+			subtasks = children
+			return updated, err
+		})
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		if from == to {
+			fmt.Fprintf(textOut(), "Задача с ID %d уже в статусе %s.\n", id, to)
+			return
+		}
+		fmt.Fprintf(textOut(), "Статус задачи с ID %d: %s → %s %s\n", id, from, formatStatus(to), to)
+		if len(subtasks) > 0 {
+			fmt.Fprintf(textOut(), "Вместе с ней закрыты подзадачи: %s.\n", formatIDs(subtasks))
+		}
+		printNextOccurrences(store, maxID)
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "status" к rootCmd.
+func init() {
+	statusCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completeTaskIDs(cmd, args, toComplete)
+		}
+		return task.StatusNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	rootCmd.AddCommand(statusCmd)
+
+	// Флаг перехода в обход рабочего процесса
+	statusCmd.Flags().Bool("force", false, "Сменить статус, даже если переход не разрешён рабочим процессом или задача ждёт другие задачи")
+
+	// Флаг закрытия задачи вместе с невыполненными подзадачами
+	statusCmd.Flags().Bool("cascade", false, "Для done и cancelled — закрыть и все невыполненные подзадачи")
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// changeTags добавляет (add = true) или удаляет теги задачи с указанным ID
+// одной операцией и возвращает теги, которые действительно изменились.
+func changeTags(store storage.Storage, id int, tags []string, add bool) ([]string, error) {
+	var changed []string
+	err := modifyTasks(store, "tag", func(tasks []task.Task) ([]task.Task, error) {
+		for i := range tasks {
+			if tasks[i].ID != id {
+				continue
+			}
+			for _, tag := range tags {
+				if (add && tasks[i].AddTag(tag)) || (!add && tasks[i].RemoveTag(tag)) {
+					changed = append(changed, tag)
+				}
+			}
+			return tasks, nil
+		}
+		return nil, &storage.NotFoundError{ID: id}
+	})
+	return changed, err
+}
+
+// runTagChange — общий обработчик "tag add" и "tag remove".
+func runTagChange(args []string, add bool) {
+	// Конвертируем первый аргумент в int (ID задачи)
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		printError("Некорректный ID задачи:", args[0])
+		return
+	}
+
+	tags := make([]string, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		tag, err := task.NormalizeTag(arg)
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+		tags = append(tags, tag)
+	}
+
+	// Создаём хранилище задач
+	store, err := openStore()
+	if err != nil {
+		printError("Ошибка при открытии хранилища:", err)
+		return
+	}
+	defer func() { _ = store.Close() }()
+
+	changed, err := changeTags(store, id, tags, add)
+	if err != nil {
+		printError("Ошибка:", err)
+		return
+	}
+
+	switch {
+	case len(changed) == 0 && add:
+		fmt.Fprintf(textOut(), "У задачи с ID %d уже есть эти теги.\n", id)
+	case len(changed) == 0:
+		fmt.Fprintf(textOut(), "У задачи с ID %d нет этих тегов.\n", id)
+	case add:
+		fmt.Fprintf(textOut(), "Задаче с ID %d добавлены теги: %s\n", id, strings.Join(changed, ", "))
+	default:
+		fmt.Fprintf(textOut(), "У задачи с ID %d удалены теги: %s\n", id, strings.Join(changed, ", "))
+	}
+}
+
+// completeTagArgs возвращает автодополнение для "tag add/remove":
+// сначала ID задачи, затем теги — те, которых у задачи ещё нет (add),
+// или те, что уже есть (remove).
+func completeTagArgs(add bool) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		store, err := openStore()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		defer func() { _ = store.Close() }()
+		tasks, err := store.ListTasks()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		var suggestions []string
+		if len(args) == 0 {
+			for _, t := range tasks {
+				suggestions = append(suggestions, fmt.Sprint(t.ID))
+			}
+			return suggestions, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		id, _ := strconv.Atoi(args[0])
+		var current task.Task
+		for _, t := range tasks {
+			if t.ID == id {
+				current = t
+			}
+		}
+
+		if !add {
+			return current.Tags, cobra.ShellCompDirectiveNoFileComp
+		}
+		for _, c := range countTags(tasks) {
+			if !current.HasTag(c.Tag) {
+				suggestions = append(suggestions, c.Tag)
+			}
+		}
+		return suggestions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// tagCmd — подкоманда "tag", группирующая действия с тегами задачи.
+// Пример использования:
+//
+//	todo tag add 3 backend infra
+//	todo tag remove 3 infra
+var tagCmd = &cobra.Command{
+	Use:   "tag",                              // формат вызова
+	Short: "Добавить или удалить теги задачи", // краткое описание
+}
+
+// tagAddCmd — подкоманда "tag add", которая добавляет задаче теги.
+var tagAddCmd = &cobra.Command{
+	Use:               "add [task ID] [tag]...", // формат вызова
+	Short:             "Добавить задаче теги",   // краткое описание
+	Args:              cobra.MinimumNArgs(2),    // ID и хотя бы один тег
+	ValidArgsFunction: completeTagArgs(true),    // автодополнение ID и тегов
+	Run:               func(cmd *cobra.Command, args []string) { runTagChange(args, true) },
+}
+
+// tagRemoveCmd — подкоманда "tag remove", которая удаляет теги задачи.
+var tagRemoveCmd = &cobra.Command{
+	Use:               "remove [task ID] [tag]...", // формат вызова
+	Aliases:           []string{"rm"},              // короткий синоним
+	Short:             "Удалить теги задачи",       // краткое описание
+	Args:              cobra.MinimumNArgs(2),       // ID и хотя бы один тег
+	ValidArgsFunction: completeTagArgs(false),      // автодополнение ID и тегов задачи
+	Run:               func(cmd *cobra.Command, args []string) { runTagChange(args, false) },
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "tag" и её действия к rootCmd.
+func init() {
+	tagCmd.AddCommand(tagAddCmd, tagRemoveCmd)
+	rootCmd.AddCommand(tagCmd)
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/output"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// parseTagArgs разбирает значения флага --tag: "x" (или "+x") — тег,
+// который нужен, "-x" — тег, которого быть не должно.
+// Возвращает нормализованные списки тегов.
+func parseTagArgs(values []string) (include, exclude []string, err error) {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		target := &include
+		switch {
+		case strings.HasPrefix(v, "-"):
+			target, v = &exclude, v[1:]
+		case strings.HasPrefix(v, "+"):
+			v = v[1:]
+		}
+
+		tag, err := task.NormalizeTag(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		*target = append(*target, tag)
+	}
+	return include, exclude, nil
+}
+
+// matchTags сообщает, есть ли у задачи все теги include и нет ни одного из exclude.
+func matchTags(t task.Task, include, exclude []string) bool {
+	for _, tag := range include {
+		if !t.HasTag(tag) {
+			return false
+		}
+	}
+	for _, tag := range exclude {
+		if t.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// tagCount — сколько задач отмечено тегом.
+type tagCount struct {
+	Tag     string // имя тега
+	Total   int    // всего задач с тегом
+	Pending int    // из них невыполненных
+}
+
+// countTags считает задачи по тегам и сортирует теги
+// по убыванию числа задач, а при равенстве — по имени.
+func countTags(tasks []task.Task) []tagCount {
+	byTag := make(map[string]*tagCount)
+	for _, t := range tasks {
+		for _, tag := range t.Tags {
+			c, ok := byTag[tag]
+			if !ok {
+				c = &tagCount{Tag: tag}
+				byTag[tag] = c
+			}
+			c.Total++
+			if !t.IsClosed() {
+				c.Pending++
+			}
+		}
+	}
+
+	counts := make([]tagCount, 0, len(byTag))
+	for _, c := range byTag {
+		counts = append(counts, *c)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Total != counts[j].Total {
+			return counts[i].Total > counts[j].Total
+		}
+		return counts[i].Tag < counts[j].Tag
+	})
+	return counts
+}
+
+// completeTags — автодополнение имён существующих тегов для флага --tag.
+// Если пользователь начал ввод с "-", предлагаются исключающие варианты.
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := openStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	defer func() { _ = store.Close() }()
+	tasks, err := store.ListTasks()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	prefix := ""
+	if strings.HasPrefix(toComplete, "-") {
+		prefix = "-"
+	}
+
+	var suggestions []string
+	for _, c := range countTags(tasks) {
+		suggestions = append(suggestions, prefix+c.Tag)
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// tagsCmd — подкоманда "tags", которая показывает все теги
+// и число отмеченных ими задач.
+// Пример использования:
+//
+//	todo tags
+var tagsCmd = &cobra.Command{
+	Use:   "tags",                             // формат вызова
+	Short: "Показать все теги с числом задач", // краткое описание
+	Args:  cobra.NoArgs,                       // аргументы не нужны
+	Run: func(cmd *cobra.Command, args []string) {
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		// Получаем список всех задач
+		tasks, err := store.ListTasks()
+		if err != nil {
+			printError("Ошибка при загрузке задач:", err)
+			return
+		}
+
+		counts := countTags(tasks)
+		if structuredOutput() {
+			records := make([]output.TagCount, 0, len(counts))
+			for _, c := range counts {
+				records = append(records, output.TagCount{Tag: c.Tag, Total: c.Total, Pending: c.Pending})
+			}
+			writeReport(records)
+			return
+		}
+		if len(counts) == 0 {
+			fmt.Fprintln(textOut(), "Тегов пока нет. Добавьте их с помощью: todo tag add <ID> <тег>")
+			return
+		}
+
+		fmt.Fprintf(textOut(), "\033[36m%-20s %-6s %-7s\033[0m\n", "TAG", "TASKS", "PENDING")
+		for _, c := range counts {
+			fmt.Fprintf(textOut(), "%-20s %-6d %-7d\n", c.Tag, c.Total, c.Pending)
+		}
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "tags" к rootCmd.
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+}
@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// templatesDir — подкаталог каталога настроек с именованными шаблонами
+// вывода: файл standup.tmpl задаёт шаблон --format=standup.
+const templatesDir = "templates"
+
+// tableTemplate — встроенный шаблон table: таблица команды list.
+// Заголовок выводится один раз, строка — для каждой задачи.
+const tableTemplate = `
+{{- define "header" -}}
+	{{- $header := printf "%-4s %-14s %-20s %-16s %-16s" "ID" "STATUS" "TITLE" "DUE" "CREATED AT" -}}
+	{{- $rule := "-----------------------------------------------------------------------" -}}
+	{{- if .Long -}}
+		{{- $header = printf "%s %-16s %-16s" $header "UPDATED AT" "COMPLETED AT" -}}
+		{{- $rule = print $rule "----------------------------------" -}}
+	{{- end -}}
+	{{- println (color "cyan" $header) -}}
+	{{- println $rule -}}
+{{- end -}}
+
+{{- $title := print .Tree .Label -}}
+{{- with .Progress}}{{$title = printf "%s (%s)" $title .}}{{end -}}
+{{- if and .Waiting (not .IsClosed)}}{{$title = printf "%s %s" $title (color "gray" (printf "(ждёт: %s)" (ids .Waiting)))}}{{end -}}
+{{- printf "%-4d %s %-11s %-30s %-16s %-16s" .ID (glyph .Status) .Status $title .Due (.CreatedAt.Format "2006-01-02 15:04") -}}
+{{- if .Long}}{{printf " %-16s %-16s" (datetime .UpdatedAt) (datetime .CompletedAt)}}{{end -}}
+`
+
+// builtinTemplates — встроенные именованные шаблоны вывода.
+var builtinTemplates = map[string]string{
+	"table": tableTemplate,
+}
+
+// colorCodes — цвета функции color в шаблонах.
+var colorCodes = map[string]string{
+	"red":     "\033[31m",
+	"green":   "\033[32m",
+	"yellow":  "\033[33m",
+	"blue":    "\033[34m",
+	"magenta": "\033[35m",
+	"cyan":    "\033[36m",
+	"gray":    "\033[90m",
+	"bold":    "\033[1m",
+}
+
+// ansiPattern — управляющие последовательности цвета, которые не занимают
+// места на экране.
+var ansiPattern = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// visibleWidth возвращает число видимых символов строки без кодов цвета.
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(ansiPattern.ReplaceAllString(s, ""))
+}
+
+// relativeDate возвращает дату относительно сегодняшнего дня: "сегодня",
+// "завтра", "вчера", "через 3 дн.", "2 дн. назад", со временем, если оно
+// указано ("завтра 18:00"). Для нулевого значения — пустая строка.
+func relativeDate(t time.Time, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	clock := task.HasClock(t)
+	if clock {
+		t = t.Local()
+	}
+	day := func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC) }
+	days := int(day(t).Sub(day(now.Local())) / (24 * time.Hour))
+
+	var s string
+	switch {
+	case days == 0:
+		s = "сегодня"
+	case days == 1:
+		s = "завтра"
+	case days == -1:
+		s = "вчера"
+	case days > 1:
+		s = fmt.Sprintf("через %d дн.", days)
+	default:
+		s = fmt.Sprintf("%d дн. назад", -days)
+	}
+	if clock {
+		s += " " + t.Format("15:04")
+	}
+	return s
+}
+
+// templateFuncs — функции, доступные в шаблонах вывода:
+//
+//	glyph .Status          — цветной символ статуса, как в todo list
+//	color "red" .Title     — текст в цвете: red, green, yellow, blue, magenta, cyan, gray, bold
+//	pad 20 .Title          — дополнить пробелами до ширины (минус — выравнивание вправо), цвет не считается
+//	trunc 20 .Title        — обрезать до ширины с многоточием
+//	rel .DueAt             — дата относительно сегодня: завтра, через 3 дн.
+//	date "02.01" .DueAt    — дата в формате Go; для пустой даты — пустая строка
+//	datetime .UpdatedAt    — дата и время, как в todo list --long
+//	duration (.Tracked now) — длительность: 1h30m
+//	ids .BlockedBy         — список ID через запятую
+//	join ", " .Tags        — строки через разделитель
+var templateFuncs = template.FuncMap{
+	"glyph": formatStatus,
+	"color": func(name, s string) (string, error) {
+		code, ok := colorCodes[name]
+		if !ok {
+			names := slices.Sorted(maps.Keys(colorCodes))
+			return "", fmt.Errorf("неизвестный цвет %q, используйте: %s", name, strings.Join(names, ", "))
+		}
+		return code + s + "\033[0m", nil
+	},
+	"pad": func(width int, v any) string {
+		s := fmt.Sprint(v)
+		n := abs(width) - visibleWidth(s)
+		if n <= 0 {
+			return s
+		}
+		if width < 0 {
+			return strings.Repeat(" ", n) + s
+		}
+		return s + strings.Repeat(" ", n)
+	},
+	"trunc": func(width int, v any) string {
+		s := fmt.Sprint(v)
+		if width <= 0 || utf8.RuneCountInString(s) <= width {
+			return s
+		}
+		runes := []rune(s)
+		return string(runes[:width-1]) + "…"
+	},
+	"rel": func(t time.Time) string { return relativeDate(t, time.Now()) },
+	"date": func(layout string, t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(layout)
+	},
+	"datetime": formatTime,
+	"duration": formatDuration,
+	"ids":      formatIDs,
+	"join":     func(sep string, s []string) string { return strings.Join(s, sep) },
+	"now":      time.Now,
+}
+
+// abs возвращает модуль числа.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// templateTask — данные шаблона для одной задачи: все поля задачи
+// (.ID, .Title, .Status, .DueAt, .Tags...) и подготовленные для вывода значения.
+type templateTask struct {
+	task.Task
+	Tree      string // отступ с линиями дерева (list --tree)
+	Progress  string // прогресс подзадач: "3/5", пусто — подзадач нет
+	Waiting   []int  // ID невыполненных задач, которых ждёт задача
+	InArchive bool   // задача из архива завершённых задач
+	Long      bool   // подробный вывод (--long)
+}
+
+// Label возвращает название со значками приоритета, проектом, тегами
+// и подсветкой, как в todo list.
+func (t templateTask) Label() string {
+	return formatTaskTitle(t.Task)
+}
+
+// Due возвращает срок, как в todo list: дату или дату со временем.
+func (t templateTask) Due() string {
+	return formatDue(t.Task)
+}
+
+// Overdue сообщает, что срок задачи прошёл, а она не завершена.
+func (t templateTask) Overdue() bool {
+	return t.IsOverdue(time.Now())
+}
+
+// Checkbox возвращает "[x]" для завершённой задачи и "[ ]" для остальных.
+func (t templateTask) Checkbox() string {
+	if t.IsClosed() {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+// templateHeader — данные шаблонов header и footer.
+type templateHeader struct {
+	Count int  // число задач
+	Long  bool // подробный вывод (--long)
+}
+
+// templateRows готовит данные шаблона для задач tasks. Прогресс и
+// зависимости берутся из opts; в режиме дерева подзадачи идут под родителем.
+func templateRows(tasks []task.Task, opts tableOptions) []templateTask {
+	nodes := make([]task.TreeNode, 0, len(tasks))
+	if opts.Tree {
+		nodes = task.Tree(tasks)
+	} else {
+		for _, t := range tasks {
+			nodes = append(nodes, task.TreeNode{Task: t})
+		}
+	}
+
+	rows := make([]templateTask, 0, len(nodes))
+	for _, node := range nodes {
+		row := templateTask{
+			Task:    node.Task,
+			Tree:    treePrefix(node),
+			Waiting: opts.Blocked[node.Task.ID],
+			Long:    opts.Long,
+		}
+		if p, ok := opts.Progress[node.Task.ID]; ok {
+			row.Progress = p.String()
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// parseTemplate разбирает шаблон вывода. Основной текст выводится для
+// каждой задачи; необязательные {{define "header"}} и {{define "footer"}}
+// — один раз до и после задач.
+func parseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Parse(text)
+}
+
+// loadTemplate возвращает шаблон по значению флага --format: текст
+// шаблона, если в нём есть "{{", иначе имя встроенного шаблона (table) или
+// файла <имя>.tmpl в подкаталоге templates каталога настроек.
+func loadTemplate(value string) (*template.Template, error) {
+	if strings.Contains(value, "{{") {
+		return parseTemplate("format", value)
+	}
+	if text, ok := builtinTemplates[value]; ok {
+		return parseTemplate(value, text)
+	}
+
+	if value == "" || strings.ContainsAny(value, `/\`) || strings.HasPrefix(value, ".") {
+		return nil, fmt.Errorf("некорректное имя шаблона %q", value)
+	}
+	path, err := configPath(filepath.Join(templatesDir, value+".tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("шаблон %q не найден, доступны: %s; свои шаблоны — файлы <имя>.tmpl в %s",
+			value, strings.Join(templateNames(), ", "), filepath.Dir(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать шаблон %q: %w", value, err)
+	}
+	return parseTemplate(value, string(data))
+}
+
+// templateNames возвращает имена встроенных шаблонов и шаблонов из
+// каталога настроек по алфавиту.
+func templateNames() []string {
+	names := make([]string, 0, len(builtinTemplates))
+	for name := range builtinTemplates {
+		names = append(names, name)
+	}
+	if path, err := configPath(templatesDir); err == nil {
+		entries, _ := os.ReadDir(path)
+		for _, entry := range entries {
+			if name, ok := strings.CutSuffix(entry.Name(), ".tmpl"); ok && !entry.IsDir() && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// renderTemplate выводит задачи rows по шаблону tmpl в w. Строка каждой
+// задачи завершается переводом строки, если шаблон не вывел его сам;
+// пустая строка (шаблон вывел только пробелы и переводы строк) пропускается. Вывод собирается
+// целиком, поэтому при ошибке шаблона в w ничего не попадает.
+func renderTemplate(w io.Writer, tmpl *template.Template, rows []templateTask, long bool) error {
+	var buf bytes.Buffer
+	line := func(name string, data any) error {
+		start := buf.Len()
+		if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+			return err
+		}
+		switch {
+		case len(bytes.TrimSpace(buf.Bytes()[start:])) == 0:
+			buf.Truncate(start)
+		case !bytes.HasSuffix(buf.Bytes(), []byte("\n")):
+			buf.WriteByte('\n')
+		}
+		return nil
+	}
+
+	header := templateHeader{Count: len(rows), Long: long}
+	if tmpl.Lookup("header") != nil {
+		if err := line("header", header); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		if err := line(tmpl.Name(), row); err != nil {
+			return err
+		}
+	}
+	if tmpl.Lookup("footer") != nil {
+		if err := line("footer", header); err != nil {
+			return err
+		}
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// printTemplate выводит задачи rows по шаблону tmpl для человека
+// (см. textOut) и сообщает об ошибке выполнения шаблона.
+func printTemplate(tmpl *template.Template, rows []templateTask, long bool) {
+	if err := renderTemplate(textOut(), tmpl, rows, long); err != nil {
+		printError("Ошибка в --format:", err)
+	}
+}
+
+// addFormatFlag добавляет команде флаг --format с шаблоном вывода задач
+// и автодополнением имён шаблонов.
+func addFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().String("format", "", "Шаблон вывода задач (Go text/template): '{{.ID}} {{.Title}}' или имя шаблона из каталога настроек")
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return templateNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// formatFlag возвращает шаблон из флага --format или nil, если флаг не задан.
+// Шаблон нельзя сочетать с машиночитаемым выводом (--output).
+func formatFlag(cmd *cobra.Command) (*template.Template, error) {
+	value, _ := cmd.Flags().GetString("format")
+	if value == "" {
+		return nil, nil
+	}
+	if structuredOutput() {
+		return nil, fmt.Errorf("шаблон нельзя сочетать с --output=%s", out.format)
+	}
+	return loadTemplate(value)
+}
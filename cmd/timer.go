@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// formatDuration форматирует длительность с точностью до минуты: 1h30m, 45m, 2h.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h, m := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%dm", h, m)
+	}
+}
+
+// startCmd — подкоманда "start", которая запускает таймер задачи.
+// Одновременно может идти только один таймер.
+// Пример использования:
+//
+//	todo start 3
+var startCmd = &cobra.Command{
+	Use:               "start [task ID]",         // формат вызова
+	Short:             "Запустить таймер задачи", // краткое описание
+	Args:              cobra.ExactArgs(1),        // ожидаем ровно один аргумент — ID задачи
+	ValidArgsFunction: completeTaskIDs,           // автодополнение ID задачи
+	Run: func(cmd *cobra.Command, args []string) {
+		// Конвертируем аргумент в int (ID задачи)
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			printError("Некорректный ID задачи:", args[0])
+			return
+		}
+
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		// Проверка и запуск — одна операция, чтобы два процесса
+		// не запустили таймеры одновременно.
+		var started task.Task
+		err = modifyTasks(store, "start", func(tasks []task.Task) ([]task.Task, error) {
+			i := taskIndex(tasks, id)
+			if i < 0 {
+				return nil, &storage.NotFoundError{ID: id}
+			}
+			if r := task.RunningTimer(tasks); r >= 0 && tasks[r].ID != id {
+				return nil, fmt.Errorf("уже запущен таймер задачи [%d] %s; остановите его: todo stop",
+					tasks[r].ID, tasks[r].Title)
+			}
+			if err := tasks[i].StartTimer(time.Now()); err != nil {
+				return nil, err
+			}
+			started = tasks[i]
+			return tasks, nil
+		})
+		if errors.Is(err, task.ErrTimerRunning) {
+			fmt.Fprintf(textOut(), "Таймер задачи с ID %d уже запущен.\n", id)
+			return
+		}
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		fmt.Fprintf(textOut(), "Таймер запущен: [%d] %s\n", started.ID, started.Title)
+	},
+}
+
+// stopCmd — подкоманда "stop", которая останавливает идущий таймер.
+// Пример использования:
+//
+//	todo stop
+var stopCmd = &cobra.Command{
+	Use:   "stop",                     // формат вызова
+	Short: "Остановить идущий таймер", // краткое описание
+	Args:  cobra.NoArgs,               // аргументы не нужны: таймер всегда один
+	Run: func(cmd *cobra.Command, args []string) {
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		var (
+			stopped  task.Task
+			interval task.Interval
+		)
+		now := time.Now()
+		err = modifyTasks(store, "stop", func(tasks []task.Task) ([]task.Task, error) {
+			i := task.RunningTimer(tasks)
+			if i < 0 {
+				return tasks, nil
+			}
+			interval, _ = tasks[i].StopTimer(now)
+			stopped = tasks[i]
+			return tasks, nil
+		})
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+		if stopped.ID == 0 {
+			fmt.Fprintln(textOut(), "Нет запущенного таймера.")
+			return
+		}
+
+		fmt.Fprintf(textOut(), "Таймер остановлен: [%d] %s — %s (всего %s)\n", stopped.ID, stopped.Title,
+			formatDuration(interval.Duration(now)), formatDuration(stopped.Tracked(now)))
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманды "start" и "stop" к rootCmd.
+func init() {
+	rootCmd.AddCommand(startCmd, stopCmd)
+}
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/output"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// shortWeekdays — сокращённые названия дней недели для отчёта.
+var shortWeekdays = [...]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"}
+
+// timesheetRow — строка отчёта: что учтено и сколько времени.
+type timesheetRow struct {
+	Key      string // ID задачи, тег или дата 2006-01-02 для машиночитаемого вывода
+	Label    string
+	Duration time.Duration
+}
+
+// timesheetPeriod возвращает границы отчёта [from, to) по флагам
+// --week, --from и --to и его заголовок. Без флагов отчёт строится
+// за всё время.
+func timesheetPeriod(cmd *cobra.Command, now time.Time) (from, to time.Time, title string, err error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from, to = time.Date(1, 1, 1, 0, 0, 0, 0, now.Location()), now
+
+	if week, _ := cmd.Flags().GetBool("week"); week {
+		from = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7)) // понедельник
+		to = from.AddDate(0, 0, 7)
+	}
+	if value, _ := cmd.Flags().GetString("from"); value != "" {
+		if from, err = task.ParseDate(value, now); err != nil {
+			return from, to, "", fmt.Errorf("ошибка в --from: %w", err)
+		}
+	}
+	if value, _ := cmd.Flags().GetString("to"); value != "" {
+		if to, err = task.ParseDate(value, now); err != nil {
+			return from, to, "", fmt.Errorf("ошибка в --to: %w", err)
+		}
+		// Дата без времени включает весь указанный день
+		if !task.HasClock(to) {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+
+	if from.Year() == 1 {
+		return from, to, "за всё время", nil
+	}
+	return from, to, fmt.Sprintf("%s — %s", from.Format("2006-01-02"), to.Add(-time.Nanosecond).Format("2006-01-02")), nil
+}
+
+// summarizeTime группирует записи учёта времени по задачам, тегам и дням.
+// Задача с несколькими тегами учитывается в каждом из них.
+func summarizeTime(tasks []task.Task, entries []task.TimeEntry) (byTask, byTag, byDay []timesheetRow) {
+	byID := make(map[int]task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	taskTotals := make(map[int]time.Duration)
+	tagTotals := make(map[string]time.Duration)
+	dayTotals := make(map[time.Time]time.Duration)
+	for _, e := range entries {
+		taskTotals[e.TaskID] += e.Duration
+		dayTotals[e.Day] += e.Duration
+		tags := byID[e.TaskID].Tags
+		if len(tags) == 0 {
+			tagTotals[""] += e.Duration
+		}
+		for _, tag := range tags {
+			tagTotals[tag] += e.Duration
+		}
+	}
+
+	for id, d := range taskTotals {
+		byTask = append(byTask, timesheetRow{Key: strconv.Itoa(id), Label: fmt.Sprintf("[%d] %s", id, byID[id].Title), Duration: d})
+	}
+	for tag, d := range tagTotals {
+		label := "#" + tag
+		if tag == "" {
+			label = "(без тегов)"
+		}
+		byTag = append(byTag, timesheetRow{Key: tag, Label: label, Duration: d})
+	}
+	// По задачам и тегам — от большего времени к меньшему
+	longestFirst := func(a, b timesheetRow) int {
+		return cmp.Or(cmp.Compare(b.Duration, a.Duration), strings.Compare(a.Label, b.Label))
+	}
+	slices.SortFunc(byTask, longestFirst)
+	slices.SortFunc(byTag, longestFirst)
+
+	// По дням — в хронологическом порядке
+	days := make([]time.Time, 0, len(dayTotals))
+	for day := range dayTotals {
+		days = append(days, day)
+	}
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+	for _, day := range days {
+		key := day.Format("2006-01-02")
+		byDay = append(byDay, timesheetRow{Key: key, Label: key + " " + shortWeekdays[day.Weekday()], Duration: dayTotals[day]})
+	}
+	return byTask, byTag, byDay
+}
+
+// timeTotals возвращает строки раздела отчёта group (task, tag или day)
+// записями для машиночитаемого вывода.
+func timeTotals(group string, rows []timesheetRow) []output.TimeTotal {
+	records := make([]output.TimeTotal, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, output.TimeTotal{Group: group, Key: row.Key, Label: row.Label, Seconds: int64(row.Duration / time.Second)})
+	}
+	return records
+}
+
+// printTimesheetSection выводит раздел отчёта с выравниванием.
+func printTimesheetSection(title string, rows []timesheetRow) {
+	fmt.Fprintf(textOut(), "\033[36m%s\033[0m\n", title)
+	for _, row := range rows {
+		fmt.Fprintf(textOut(), "  %-40s %8s\n", row.Label, formatDuration(row.Duration))
+	}
+}
+
+// timesheetCmd — подкоманда "timesheet", которая показывает отчёт
+// об учтённом времени по задачам, тегам и дням.
+// Пример использования:
+//
+//	todo timesheet --week
+//	todo timesheet --from=2025-03-01 --to=2025-03-31
+//	todo timesheet --week --by=tag
+var timesheetCmd = &cobra.Command{
+	Use:   "timesheet",                 // формат вызова
+	Short: "Отчёт об учтённом времени", // краткое описание
+	Args:  cobra.NoArgs,                // аргументы не нужны
+	Run: func(cmd *cobra.Command, args []string) {
+		by, _ := cmd.Flags().GetString("by")
+		if by != "" && by != "task" && by != "tag" && by != "day" {
+			printErrorf("Ошибка: неизвестная группировка: %s (используйте task, tag или day)\n", by)
+			return
+		}
+
+		now := time.Now()
+		from, to, period, err := timesheetPeriod(cmd, now)
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		// Создаём хранилище задач
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		tasks, err := store.ListTasks()
+		if err != nil {
+			printError("Ошибка при загрузке задач:", err)
+			return
+		}
+		// Время задач в архиве тоже учитывается в отчёте
+		archived, err := archivedTasks(store)
+		if err != nil {
+			printError("Ошибка при чтении архива:", err)
+			return
+		}
+		tasks = append(tasks, archived...)
+
+		entries := task.TimeEntries(tasks, from, to, now)
+		if structuredOutput() {
+			byTask, byTag, byDay := summarizeTime(tasks, entries)
+			var records []output.TimeTotal
+			if by == "" || by == "task" {
+				records = append(records, timeTotals("task", byTask)...)
+			}
+			if by == "" || by == "tag" {
+				records = append(records, timeTotals("tag", byTag)...)
+			}
+			if by == "" || by == "day" {
+				records = append(records, timeTotals("day", byDay)...)
+			}
+			writeReport(records)
+			return
+		}
+		fmt.Fprintf(textOut(), "Учёт времени %s\n", period)
+		if len(entries) == 0 {
+			fmt.Fprintln(textOut(), "Нет учтённого времени.")
+			return
+		}
+
+		byTask, byTag, byDay := summarizeTime(tasks, entries)
+		if by == "" || by == "task" {
+			printTimesheetSection("По задачам:", byTask)
+		}
+		if by == "" || by == "tag" {
+			printTimesheetSection("По тегам:", byTag)
+		}
+		if by == "" || by == "day" {
+			printTimesheetSection("По дням:", byDay)
+		}
+
+		var total time.Duration
+		for _, e := range entries {
+			total += e.Duration
+		}
+		fmt.Fprintf(textOut(), "Итого: %s\n", formatDuration(total))
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "timesheet" к rootCmd.
+func init() {
+	rootCmd.AddCommand(timesheetCmd)
+
+	timesheetCmd.Flags().Bool("week", false, "Отчёт за текущую неделю (с понедельника)")
+	timesheetCmd.Flags().String("from", "", "Начало периода: 2025-03-01, yesterday, -7d")
+	timesheetCmd.Flags().String("to", "", "Конец периода включительно: 2025-03-31, today")
+	timesheetCmd.Flags().String("by", "", "Показать только одну группировку: task, tag или day")
+	_ = timesheetCmd.RegisterFlagCompletionFunc("by", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"task", "tag", "day"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
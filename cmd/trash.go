@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/output"
+)
+
+// trashCmd — подкоманда "trash", объединяющая работу с корзиной:
+// delete и clear не удаляют задачи насовсем, а перемещают их в корзину
+// вместе с временем удаления.
+// Пример использования:
+//
+//	todo trash list
+//	todo trash restore 3
+//	todo trash purge --older-than=30d
+var trashCmd = &cobra.Command{
+	Use:   "trash",                   // формат вызова
+	Short: "Корзина удалённых задач", // краткое описание
+	Args:  cobra.NoArgs,              // только подкоманды
+	Run:   func(cmd *cobra.Command, args []string) { _ = cmd.Help() },
+}
+
+// trashListCmd — подкоманда "trash list", которая показывает удалённые задачи.
+var trashListCmd = &cobra.Command{
+	Use:   "list",                      // формат вызова
+	Short: "Показать задачи в корзине", // краткое описание
+	Args:  cobra.NoArgs,                // аргументы не нужны
+	Run: func(cmd *cobra.Command, args []string) {
+		// Открываем хранилище вместе с корзиной
+		store, err := openJournaledStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		entries, err := store.Trash.List()
+		if err != nil {
+			printError("Ошибка при чтении корзины:", err)
+			return
+		}
+		if structuredOutput() {
+			now := time.Now()
+			records := make([]output.TrashEntry, 0, len(entries))
+			for i := len(entries) - 1; i >= 0; i-- {
+				records = append(records, output.TrashEntry{
+					DeletedAt: entries[i].DeletedAt.Format(time.RFC3339),
+					Task:      output.FromTask(entries[i].Task, now),
+				})
+			}
+			writeReport(records)
+			return
+		}
+		if len(entries) == 0 {
+			fmt.Fprintln(textOut(), "Корзина пуста.")
+			return
+		}
+
+		fmt.Fprintf(textOut(), "%-4s %-16s %s\n", "ID", "DELETED AT", "TITLE")
+		for i := len(entries) - 1; i >= 0; i-- { // сначала недавно удалённые
+			e := entries[i]
+			fmt.Fprintf(textOut(), "%-4d %-16s %s %s\n", e.Task.ID, formatTime(e.DeletedAt), formatStatus(e.Task.Status), e.Task.Title)
+		}
+	},
+}
+
+// trashRestoreCmd — подкоманда "trash restore", которая возвращает задачу
+// из корзины в список. Если её ID уже занят новой задачей, восстановленная
+// задача получает новый ID.
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore [task ID]",              // формат вызова
+	Short: "Восстановить задачу из корзины", // краткое описание
+	Args:  cobra.ExactArgs(1),               // ожидаем ровно один аргумент — ID задачи
+	Run: func(cmd *cobra.Command, args []string) {
+		// Конвертируем аргумент в int (ID задачи)
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			printError("Некорректный ID задачи:", args[0])
+			return
+		}
+
+		// Открываем хранилище вместе с корзиной
+		store, err := openJournaledStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		restored, err := store.Restore(id)
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+		if restored.ID != id {
+			fmt.Fprintf(textOut(), "Задача восстановлена с новым ID %d (ID %d уже занят): %s\n", restored.ID, id, restored.Title)
+			return
+		}
+		fmt.Fprintf(textOut(), "Задача с ID %d восстановлена: %s\n", id, restored.Title)
+	},
+}
+
+// trashPurgeCmd — подкоманда "trash purge", которая окончательно удаляет
+// задачи из корзины: все или только удалённые раньше, чем --older-than назад.
+var trashPurgeCmd = &cobra.Command{
+	Use:   "purge",                                  // формат вызова
+	Short: "Окончательно удалить задачи из корзины", // краткое описание
+	Args:  cobra.NoArgs,                             // аргументы не нужны
+	Run: func(cmd *cobra.Command, args []string) {
+		var before time.Time
+		if value, _ := cmd.Flags().GetString("older-than"); value != "" {
+			t, ok := parseAgo(value, time.Now())
+			if !ok {
+				printErrorf("Ошибка в --older-than: не удалось разобрать %q, используйте 30d, 2w или 12h\n", value)
+				return
+			}
+			before = t
+		}
+
+		// Открываем хранилище вместе с корзиной
+		store, err := openJournaledStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		purged, err := store.Trash.Purge(before)
+		if err != nil {
+			printError("Ошибка при очистке корзины:", err)
+			return
+		}
+		if len(purged) == 0 {
+			fmt.Fprintln(textOut(), "Нет задач для окончательного удаления.")
+			return
+		}
+		fmt.Fprintf(textOut(), "Окончательно удалено задач: %d\n", len(purged))
+	},
+}
+
+// completeTrashIDs — автодополнение ID задач из корзины.
+func completeTrashIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	store, err := openJournaledStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	defer func() { _ = store.Close() }()
+	entries, err := store.Trash.List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var suggestions []string
+	for _, e := range entries {
+		suggestions = append(suggestions, fmt.Sprint(e.Task.ID))
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "trash" и её подкоманды к rootCmd.
+func init() {
+	trashRestoreCmd.ValidArgsFunction = completeTrashIDs
+
+	trashCmd.AddCommand(trashListCmd, trashRestoreCmd, trashPurgeCmd)
+	rootCmd.AddCommand(trashCmd)
+
+	// Флаг возраста удалённых задач
+	trashPurgeCmd.Flags().String("older-than", "", "Удалить только задачи, попавшие в корзину раньше (например, 30d, 2w, 12h)")
+}
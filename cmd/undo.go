@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zen-flo/todo-cli/internal/output"
+	"github.com/zen-flo/todo-cli/internal/storage"
+)
+
+// formatOperation форматирует запись журнала в одну строку:
+// номер, время, тип операции и ID затронутых задач.
+func formatOperation(op storage.Operation) string {
+	ids := make([]string, 0, len(op.TaskIDs))
+	for _, id := range op.TaskIDs {
+		ids = append(ids, fmt.Sprint(id))
+	}
+
+	line := fmt.Sprintf("#%-4d %s  %-13s задачи: %s",
+		op.ID, op.Time.Format("2006-01-02 15:04"), op.Kind, strings.Join(ids, ", "))
+	if op.Undone {
+		line += " (отменена)"
+	}
+	return line
+}
+
+// undoCmd — подкоманда "undo", которая отменяет последнюю изменяющую операцию
+// (add, update, done, delete, clear, complete-all).
+// Пример использования:
+//
+//	todo undo          — отменить последнюю операцию
+//	todo undo --list   — показать последние операции журнала
+var undoCmd = &cobra.Command{
+	Use:   "undo",                        // формат вызова
+	Short: "Отменить последнюю операцию", // краткое описание
+	Args:  cobra.NoArgs,                  // аргументы не нужны
+	Run: func(cmd *cobra.Command, args []string) {
+		// Открываем хранилище вместе с журналом операций
+		store, err := openJournaledStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		// Режим просмотра журнала
+		if list, _ := cmd.Flags().GetBool("list"); list {
+			limit, _ := cmd.Flags().GetInt("limit")
+			ops, err := store.Journal.List(limit)
+			if err != nil {
+				printError("Ошибка при чтении журнала:", err)
+				return
+			}
+			if structuredOutput() {
+				records := make([]output.Operation, 0, len(ops))
+				for _, op := range ops {
+					records = append(records, output.Operation{
+						ID: op.ID, Kind: op.Kind, Time: op.Time.Format(time.RFC3339),
+						TaskIDs: append([]int{}, op.TaskIDs...), Undone: op.Undone,
+					})
+				}
+				writeReport(records)
+				return
+			}
+			if len(ops) == 0 {
+				fmt.Fprintln(textOut(), "Журнал операций пуст.")
+				return
+			}
+			fmt.Fprintln(textOut(), "Последние операции:")
+			for _, op := range ops {
+				fmt.Fprintln(textOut(), formatOperation(op))
+			}
+			return
+		}
+
+		op, err := store.Undo()
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+		fmt.Fprintln(textOut(), "Отменена операция:", formatOperation(op))
+	},
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "undo" к rootCmd.
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	// Флаги просмотра журнала
+	undoCmd.Flags().BoolP("list", "l", false, "Показать последние операции вместо отмены")
+	undoCmd.Flags().IntP("limit", "n", 10, "Сколько последних операций показать с --list")
+}
@@ -2,56 +2,179 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
 )
 
-// updateCmd — подкоманда "update", которая изменяет название задачи по ID.
+// updateFlags — флаги updateCmd, каждый из которых сам по себе
+// является изменением задачи и позволяет не указывать новое название.
+var updateFlags = []string{"priority", "important", "due", "tag", "project", "parent"}
+
+// hasUpdateFlags сообщает, указан ли явно хотя бы один флаг изменения.
+func hasUpdateFlags(cmd *cobra.Command) bool {
+	for _, name := range updateFlags {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveParent разбирает значение --parent для задачи id ("none" — без
+// родителя) и проверяет, что новый родитель существует и не является
+// самой задачей или её подзадачей, иначе в дереве появился бы цикл.
+func resolveParent(store storage.Storage, id int, value string) (int, error) {
+	if value == "none" || value == "0" {
+		return 0, nil
+	}
+	parentID, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("некорректный ID родительской задачи: %s", value)
+	}
+
+	tasks, err := store.ListTasks()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := store.GetTask(parentID); err != nil {
+		return 0, err
+	}
+	if parentID == id {
+		return 0, fmt.Errorf("задача %d не может быть подзадачей самой себя", id)
+	}
+	if slices.Contains(task.Descendants(tasks, id), parentID) {
+		return 0, fmt.Errorf("задача %d не может стать подзадачей своей же подзадачи %d", id, parentID)
+	}
+	return parentID, nil
+}
+
+// updateCmd — подкоманда "update", которая изменяет задачу по ID.
+// Название можно не указывать, если меняются только флаги.
 // Пример использования:
 //
 //	todo update 2 "Новое название задачи"
+//	todo update 2 --due=+3d
+//	todo update 2 --priority=urgent
+//	todo update 2 --tag=infra --tag=-backend   — добавить infra, убрать backend
+//	todo update 2 --project=work.release       — перенести в проект (none — убрать)
+//	todo update 5 --parent=2                   — сделать подзадачей задачи 2 (none — убрать)
+//	todo update 2 --due=none   — убрать срок
 var updateCmd = &cobra.Command{
 	Use:   "update [task ID] [new title]",   // формат вызова
 	Short: "Изменить название задачи по ID", // краткое описание
-	Args: func(cmd *cobra.Command, args []string) error { // ожидаем ровно два аргумента: ID и новый заголовок
-		if len(args) < 2 {
-			fmt.Println("Ошибка: нужно указать ID и новое название задачи.")
+	Args: func(cmd *cobra.Command, args []string) error { // ожидаем ID и новый заголовок (или флаги)
+		if len(args) < 2 && (len(args) == 0 || !hasUpdateFlags(cmd)) {
+			printError("Ошибка: нужно указать ID и новое название задачи.")
 			return fmt.Errorf("недостаточно аргументов")
 		}
 		return nil
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Проверяем количество аргументов прямо в Run
-		if len(args) < 2 {
-			fmt.Println("Ошибка: нужно указать ID и новое название задачи.")
+		if len(args) < 2 && (len(args) == 0 || !hasUpdateFlags(cmd)) {
+			printError("Ошибка: нужно указать ID и новое название задачи.")
 			return
 		}
 		// Конвертируем аргумент в int (ID задачи)
 		id, err := strconv.Atoi(args[0])
 		if err != nil {
-			fmt.Println("Некорректный ID задачи:", args[0])
+			printError("Некорректный ID задачи:", args[0])
+			return
+		}
+
+		// Считываем приоритет (--important — синоним --priority=high)
+		priority, priorityChanged, err := priorityFromFlags(cmd)
+		if err != nil {
+			printError("Ошибка:", err)
 			return
 		}
 
-		newTitle := args[1]
+		// Разбираем новый срок: "none" убирает срок
+		var due time.Time
+		if value, _ := cmd.Flags().GetString("due"); cmd.Flags().Changed("due") && value != "none" {
+			due, err = task.ParseDate(value, time.Now())
+			if err != nil {
+				printError("Ошибка:", err)
+				return
+			}
+		}
 
-		// Считываем флаг, что задача важная
-		important, _ := cmd.Flags().GetBool("important")
+		// Разбираем теги: "x" добавляет тег, "-x" — удаляет
+		tagValues, _ := cmd.Flags().GetStringArray("tag")
+		addTags, removeTags, err := parseTagArgs(tagValues)
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		// Проверяем новый проект: "none" убирает задачу из проекта
+		projectValue, _ := cmd.Flags().GetString("project")
+		if projectValue == "none" {
+			projectValue = ""
+		}
+		project, err := task.NormalizeProject(projectValue)
+		if err != nil {
+			printError("Ошибка:", err)
+			return
+		}
 
 		// Создаём хранилище задач
-		store := storage.NewJSONStore(tasksFile)
+		store, err := openStore()
+		if err != nil {
+			printError("Ошибка при открытии хранилища:", err)
+			return
+		}
+		defer func() { _ = store.Close() }()
+
+		// Загружаем текущую версию задачи
+		t, err := store.GetTask(id)
+		if err != nil {
+			printError("Ошибка при обновлении задачи:", err)
+			return
+		}
+
+		// Меняем название, а приоритет и срок — только если флаг указан явно,
+		// чтобы "todo update 1 текст" не сбрасывал их.
+		if len(args) > 1 {
+			t.Title = args[1]
+		}
+		if priorityChanged {
+			t.Priority = priority
+		}
+		if cmd.Flags().Changed("due") {
+			t.DueAt = due
+		}
+		if cmd.Flags().Changed("project") {
+			t.Project = project
+		}
+		if cmd.Flags().Changed("parent") {
+			value, _ := cmd.Flags().GetString("parent")
+			if t.ParentID, err = resolveParent(store, t.ID, value); err != nil {
+				printError("Ошибка при обновлении задачи:", err)
+				return
+			}
+		}
+		for _, tag := range addTags {
+			t.AddTag(tag)
+		}
+		for _, tag := range removeTags {
+			t.RemoveTag(tag)
+		}
 
-		// Обновляем название задачи через публичный метод UpdateTask
-		err = store.UpdateTask(id, newTitle, important)
+		// Сохраняем задачу через публичный метод UpdateTask
+		err = store.UpdateTask(t)
 		if err != nil {
-			fmt.Println("Ошибка при обновлении задачи:", err)
+			printError("Ошибка при обновлении задачи:", err)
 			return
 		}
 
 		// Подтверждаем успешное обновление
-		fmt.Printf("Задача с ID %d успешно обновлена.\n", id)
+		fmt.Fprintf(textOut(), "Задача с ID %d успешно обновлена.\n", id)
 	},
 }
 
@@ -60,8 +183,25 @@ var updateCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(updateCmd)
 
-	// Флаг важности
-	updateCmd.Flags().BoolP("important", "i", false, "Сделать задачу важной")
+	// Флаги приоритета
+	updateCmd.Flags().StringP("priority", "p", "normal", "Новый приоритет: low, normal, high или urgent")
+	updateCmd.Flags().BoolP("important", "i", false, "Сделать задачу важной (то же, что --priority=high)")
+	_ = updateCmd.RegisterFlagCompletionFunc("priority", completePriorities)
+
+	// Флаг срока выполнения
+	updateCmd.Flags().StringP("due", "d", "", "Новый срок: 2025-03-14, tomorrow, +3d, fri или none")
+
+	// Флаг тегов: "x" добавляет тег, "-x" удаляет (можно повторять)
+	updateCmd.Flags().StringArrayP("tag", "t", nil, "Добавить тег или убрать его с префиксом - (можно повторять)")
+	_ = updateCmd.RegisterFlagCompletionFunc("tag", completeTags)
+
+	// Флаг проекта
+	updateCmd.Flags().String("project", "", "Перенести задачу в проект (none — убрать из проекта)")
+	_ = updateCmd.RegisterFlagCompletionFunc("project", completeProjects)
+
+	// Флаг родительской задачи
+	updateCmd.Flags().String("parent", "", "ID новой родительской задачи (none — сделать задачей верхнего уровня)")
+	_ = updateCmd.RegisterFlagCompletionFunc("parent", completeTaskIDs)
 
 	// Автодополнение для аргументов
 	updateCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -69,7 +209,11 @@ func init() {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
 
-		store := storage.NewJSONStore(tasksFile)
+		store, err := openStore()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		defer func() { _ = store.Close() }()
 		tasks, err := store.ListTasks()
 		if err != nil {
 			return nil, cobra.ShellCompDirectiveError
@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/zen-flo/todo-cli/internal/output"
+	"github.com/zen-flo/todo-cli/internal/query"
+	"github.com/zen-flo/todo-cli/internal/storage"
+)
+
+// openViews открывает сохранённые представления текущего хранилища
+// (например, tasks.json.views).
+func openViews() (*storage.Views, error) {
+	path, err := storePath()
+	if err != nil {
+		return nil, err
+	}
+	views := storage.NewViews(path + ".views")
+	views.LockTimeout = lockTimeout
+	return views, nil
+}
+
+// discardValue — значение флага, которое принимает и отбрасывает любой ввод.
+// Используется для флагов представления, уже заданных в командной строке.
+type discardValue string
+
+func (d discardValue) String() string   { return "" }
+func (d discardValue) Set(string) error { return nil }
+func (d discardValue) Type() string     { return string(d) }
+
+// isViewFlag сообщает, может ли флаг входить в представление: годятся
+// флаги отбора, сортировки и вывода list, но не глобальные флаги вроде
+// --file, не --help и не сам --view.
+func isViewFlag(cmd *cobra.Command, f *pflag.Flag) bool {
+	if f.Name == "view" || f.Name == "help" {
+		return false
+	}
+	return cmd.Root().PersistentFlags().Lookup(f.Name) == nil
+}
+
+// applyView подставляет во флаги команды list аргументы представления,
+// заданного флагом --view. Флаги, явно указанные в командной строке,
+// важнее флагов представления: их значения из представления отбрасываются.
+func applyView(cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("view")
+	if name == "" {
+		return nil
+	}
+
+	views, err := openViews()
+	if err != nil {
+		return err
+	}
+	view, err := views.Get(name)
+	if err != nil {
+		return err
+	}
+
+	// Незаданные флаги разбираются прямо в флаги команды,
+	// заданные — в заглушки с тем же именем
+	fs := pflag.NewFlagSet("view", pflag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		switch {
+		case !isViewFlag(cmd, f):
+		case !f.Changed:
+			fs.AddFlag(f)
+		default:
+			stub := fs.VarPF(discardValue(f.Value.Type()), f.Name, f.Shorthand, f.Usage)
+			stub.NoOptDefVal = f.NoOptDefVal
+		}
+	})
+	if err := fs.Parse(view.Args); err != nil {
+		return fmt.Errorf("представление %q: %w", name, err)
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("представление %q: лишние аргументы: %s", name, strings.Join(fs.Args(), " "))
+	}
+	return nil
+}
+
+// viewArgs собирает из явно заданных флагов команды аргументы представления
+// в виде --name=value; повторяемые флаги дают по аргументу на значение.
+func viewArgs(cmd *cobra.Command) []string {
+	var args []string
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if !f.Changed || !isViewFlag(cmd, f) {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				args = append(args, "--"+f.Name+"="+v)
+			}
+			return
+		}
+		args = append(args, "--"+f.Name+"="+f.Value.String())
+	})
+	return args
+}
+
+// formatViewArgs выводит аргументы представления так, чтобы их можно было
+// скопировать в командную строку: аргументы с пробелами берутся в кавычки.
+func formatViewArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if strings.ContainsAny(a, " \t\"'\\$`") {
+			a = strconv.Quote(a)
+		}
+		quoted[i] = a
+	}
+	return strings.Join(quoted, " ")
+}
+
+// checkViewName проверяет, что имя представления допустимо и не совпадает
+// с командой: иначе "todo <имя>" запускало бы команду, а не представление.
+func checkViewName(name string) error {
+	if err := storage.ValidateViewName(name); err != nil {
+		return err
+	}
+	if name == "help" || name == "completion" {
+		return fmt.Errorf("имя %q занято командой todo %s", name, name)
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || slices.Contains(c.Aliases, name) {
+			return fmt.Errorf("имя %q занято командой todo %s", name, c.Name())
+		}
+	}
+	return nil
+}
+
+// runView выполняет "todo list" с сохранённым представлением name.
+func runView(name string) {
+	if err := listCmd.Flags().Set("view", name); err != nil {
+		printError("Ошибка:", err)
+		return
+	}
+	listCmd.Run(listCmd, nil)
+}
+
+// viewCmd — подкоманда "view", объединяющая работу с сохранёнными
+// представлениями: именованными наборами фильтров и сортировки для list.
+// Представление запускается как "todo <имя>" или "todo list --view=<имя>".
+// Пример использования:
+//
+//	todo view save work 'project:work and status:pending' --sort=due
+//	todo view save urgent --priority=">=high" --tree
+//	todo work
+//	todo list --view=work --long
+//	todo view list
+//	todo view delete work
+var viewCmd = &cobra.Command{
+	Use:   "view",                                   // формат вызова
+	Short: "Сохранённые представления списка задач", // краткое описание
+	Args:  cobra.NoArgs,                             // только подкоманды
+	Run:   func(cmd *cobra.Command, args []string) { _ = cmd.Help() },
+}
+
+// viewSaveCmd — подкоманда "view save", которая сохраняет представление.
+// Принимает те же флаги, что и list; слова после имени — запрос на языке
+// фильтров (то же, что --query). Одноимённое представление заменяется.
+var viewSaveCmd = &cobra.Command{
+	Use:   "save [name] [query]",     // формат вызова
+	Short: "Сохранить представление", // краткое описание
+	Args:  cobra.MinimumNArgs(1),     // имя обязательно, запрос — по желанию
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if err := checkViewName(name); err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+
+		// Запрос словами после имени — то же, что --query
+		if len(args) > 1 {
+			if cmd.Flags().Changed("query") {
+				printError("Ошибка: укажите запрос либо после имени, либо через --query")
+				return
+			}
+			if err := cmd.Flags().Set("query", strings.Join(args[1:], " ")); err != nil {
+				printError("Ошибка:", err)
+				return
+			}
+		}
+		if cmd.Flags().Changed("query") {
+			queryText, _ := cmd.Flags().GetString("query")
+			if _, err := query.Parse(queryText, time.Now()); err != nil {
+				printQueryError("Ошибка в запросе", queryText, err)
+				return
+			}
+		}
+
+		view := storage.View{Name: name, Args: viewArgs(cmd)}
+		if len(view.Args) == 0 {
+			printError("Ошибка: укажите запрос или флаги list для представления, например: todo view save", name, "--filter=pending")
+			return
+		}
+
+		views, err := openViews()
+		if err != nil {
+			printError("Ошибка при открытии представлений:", err)
+			return
+		}
+		replaced, err := views.Save(view)
+		if err != nil {
+			printError("Ошибка при сохранении представления:", err)
+			return
+		}
+		if replaced {
+			fmt.Fprintf(textOut(), "Представление %q обновлено: todo list %s\n", name, formatViewArgs(view.Args))
+		} else {
+			fmt.Fprintf(textOut(), "Представление %q сохранено: todo list %s\n", name, formatViewArgs(view.Args))
+		}
+		fmt.Fprintf(textOut(), "Показать: todo %s\n", name)
+	},
+}
+
+// viewListCmd — подкоманда "view list", которая показывает представления.
+var viewListCmd = &cobra.Command{
+	Use:   "list",                               // формат вызова
+	Short: "Показать сохранённые представления", // краткое описание
+	Args:  cobra.NoArgs,                         // аргументы не нужны
+	Run: func(cmd *cobra.Command, args []string) {
+		views, err := openViews()
+		if err != nil {
+			printError("Ошибка при открытии представлений:", err)
+			return
+		}
+		list, err := views.List()
+		if err != nil {
+			printError("Ошибка при чтении представлений:", err)
+			return
+		}
+		if structuredOutput() {
+			records := make([]output.View, 0, len(list))
+			for _, v := range list {
+				records = append(records, output.View{Name: v.Name, Args: append([]string{}, v.Args...), Line: formatViewArgs(v.Args)})
+			}
+			writeReport(records)
+			return
+		}
+		if len(list) == 0 {
+			fmt.Fprintln(textOut(), "Нет сохранённых представлений. Сохранить: todo view save <имя> --filter=pending --sort=due")
+			return
+		}
+
+		fmt.Fprintf(textOut(), "%-16s %s\n", "NAME", "ARGS")
+		for _, v := range list {
+			fmt.Fprintf(textOut(), "%-16s %s\n", v.Name, formatViewArgs(v.Args))
+		}
+	},
+}
+
+// viewDeleteCmd — подкоманда "view delete", которая удаляет представление.
+var viewDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",         // формат вызова
+	Short: "Удалить представление", // краткое описание
+	Args:  cobra.ExactArgs(1),      // ожидаем ровно одно имя
+	Run: func(cmd *cobra.Command, args []string) {
+		views, err := openViews()
+		if err != nil {
+			printError("Ошибка при открытии представлений:", err)
+			return
+		}
+		if err := views.Delete(args[0]); err != nil {
+			printError("Ошибка:", err)
+			return
+		}
+		fmt.Fprintf(textOut(), "Представление %q удалено.\n", args[0])
+	},
+}
+
+// completeViewNames — автодополнение имён сохранённых представлений.
+func completeViewNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	views, err := openViews()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	list, err := views.List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var suggestions []string
+	for _, v := range list {
+		suggestions = append(suggestions, v.Name)
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeViewArg — автодополнение имени представления первым аргументом.
+func completeViewArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeViewNames(cmd, args, toComplete)
+}
+
+// init автоматически вызывается при старте приложения.
+// Здесь мы подключаем подкоманду "view" и её подкоманды к rootCmd.
+func init() {
+	addListFlags(viewSaveCmd)
+	viewSaveCmd.ValidArgsFunction = completeViewArg
+	viewDeleteCmd.ValidArgsFunction = completeViewArg
+
+	viewCmd.AddCommand(viewSaveCmd, viewListCmd, viewDeleteCmd)
+	rootCmd.AddCommand(viewCmd)
+}
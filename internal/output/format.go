@@ -0,0 +1,53 @@
+// Package output выводит задачи и итоги команд в машиночитаемых форматах
+// (JSON, YAML, CSV, TSV) для скриптов. Схема записей описана в типах Task
+// и Result и в README.
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format — формат вывода команды.
+type Format int
+
+// Форматы вывода. Нулевое значение — обычный текст для человека.
+const (
+	Text Format = iota // текст с цветом и таблицами (по умолчанию)
+	JSON               // JSON с отступами
+	YAML               // YAML
+	CSV                // CSV с заголовком
+	TSV                // значения через табуляцию с заголовком
+)
+
+// formatNames — имена форматов в порядке констант.
+var formatNames = []string{"text", "json", "yaml", "csv", "tsv"}
+
+// Names возвращает имена всех форматов (например, для автодополнения).
+func Names() []string {
+	return append([]string{}, formatNames...)
+}
+
+// ParseFormat разбирает имя формата без учёта регистра.
+func ParseFormat(s string) (Format, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	for i, name := range formatNames {
+		if name == value {
+			return Format(i), nil
+		}
+	}
+	return Text, fmt.Errorf("неизвестный формат вывода %q: используйте %s", s, strings.Join(formatNames, ", "))
+}
+
+// String возвращает имя формата.
+func (f Format) String() string {
+	if f >= 0 && int(f) < len(formatNames) {
+		return formatNames[f]
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// Structured сообщает, что формат машиночитаемый, а не текст для человека.
+func (f Format) Structured() bool {
+	return f != Text
+}
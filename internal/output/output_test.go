@@ -0,0 +1,197 @@
+package output
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// update перезаписывает эталонные файлы testdata/*.golden:
+//
+//	go test ./internal/output -update
+var update = flag.Bool("update", false, "перезаписать эталонные файлы testdata/*.golden")
+
+// testNow — момент, до которого считается время идущего таймера.
+var testNow = time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+
+// testTasks возвращает задачи со всеми видами полей: срок без времени
+// и со временем, теги, зависимости, таймер, повторение и многострочные
+// заметки с символами, которые нужно экранировать в CSV и TSV.
+func testTasks(t *testing.T) []task.Task {
+	t.Helper()
+
+	recur, err := task.ParseRecurrence("every mon,thu")
+	if err != nil {
+		t.Fatalf("ParseRecurrence вернул ошибку: %v", err)
+	}
+	at := func(d, h, m int) time.Time { return time.Date(2025, 3, d, h, m, 0, 0, time.UTC) }
+	return []task.Task{
+		{
+			ID: 1, Title: "Купить хлеб", CreatedAt: at(10, 9, 0), UpdatedAt: at(10, 9, 0),
+			DueAt: time.Date(2025, 3, 14, 0, 0, 0, 0, time.Local),
+		},
+		{
+			ID: 2, Title: `Deploy "API", v2`, Status: task.StatusInProgress, Priority: task.PriorityUrgent,
+			Project: "work.release", Tags: []string{"infra", "oncall"}, BlockedBy: []int{1},
+			DueAt: at(15, 18, 30), CreatedAt: at(11, 10, 0), UpdatedAt: at(14, 11, 0), Recur: recur,
+			TimeLog: []task.Interval{{Start: at(14, 10, 0), End: at(14, 11, 0)}, {Start: at(14, 11, 30)}},
+			Notes:   "шаги:\n1.\tсобрать\n2. выложить \\ проверить",
+		},
+		{
+			ID: 3, Title: "Обзор", Status: task.StatusDone, Priority: task.PriorityLow, ParentID: 2,
+			Project: "old", Archived: true, CreatedAt: at(12, 8, 0), UpdatedAt: at(13, 8, 0), CompletedAt: at(13, 8, 0),
+		},
+	}
+}
+
+// checkGolden сравнивает вывод с эталонным файлом testdata/name.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("не удалось записать %s: %v", path, err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("не удалось прочитать %s: %v (запустите тест с -update)", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s: вывод отличается от эталона\nполучено:\n%s\nожидалось:\n%s", name, got, want)
+	}
+}
+
+// TestWriteTasks сверяет вывод списка задач во всех форматах с эталонами.
+func TestWriteTasks(t *testing.T) {
+	records := FromTasks(testTasks(t), testNow)
+	for _, f := range []Format{JSON, YAML, CSV, TSV} {
+		var buf bytes.Buffer
+		if err := WriteTasks(&buf, f, records); err != nil {
+			t.Fatalf("WriteTasks(%s) вернул ошибку: %v", f, err)
+		}
+		checkGolden(t, "tasks."+f.String()+".golden", buf.Bytes())
+	}
+
+	// Пустой список — пустой массив, а не null
+	var buf bytes.Buffer
+	if err := WriteTasks(&buf, JSON, nil); err != nil || buf.String() != "[]\n" {
+		t.Errorf("пустой список в JSON = %q, %v", buf.String(), err)
+	}
+}
+
+// TestWriteTask сверяет вывод одной задачи с эталонами.
+func TestWriteTask(t *testing.T) {
+	record := FromTask(testTasks(t)[1], testNow)
+	for _, f := range []Format{JSON, YAML, CSV, TSV} {
+		var buf bytes.Buffer
+		if err := WriteTask(&buf, f, record); err != nil {
+			t.Fatalf("WriteTask(%s) вернул ошибку: %v", f, err)
+		}
+		checkGolden(t, "task."+f.String()+".golden", buf.Bytes())
+	}
+}
+
+// TestWriteResult сверяет вывод итога команды с эталонами.
+func TestWriteResult(t *testing.T) {
+	tasks := testTasks(t)
+	results := map[string]Result{
+		"ok":    {Command: "delete", OK: true, Tasks: FromTasks(tasks[:1], testNow), Removed: FromTasks(tasks[2:], testNow)},
+		"error": {Command: "done", Error: "задача с ID 99 не найдена"},
+	}
+	for name, r := range results {
+		for _, f := range []Format{JSON, YAML, CSV, TSV} {
+			var buf bytes.Buffer
+			if err := WriteResult(&buf, f, r); err != nil {
+				t.Fatalf("WriteResult(%s) вернул ошибку: %v", f, err)
+			}
+			checkGolden(t, "result-"+name+"."+f.String()+".golden", buf.Bytes())
+		}
+	}
+}
+
+// TestParseFormat проверяет разбор имён форматов.
+func TestParseFormat(t *testing.T) {
+	for _, name := range Names() {
+		f, err := ParseFormat(name)
+		if err != nil || f.String() != name {
+			t.Errorf("ParseFormat(%q) = %v, %v", name, f, err)
+		}
+	}
+	if f, err := ParseFormat(" JSON "); err != nil || f != JSON {
+		t.Errorf("ParseFormat(JSON) = %v, %v", f, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ожидалась ошибка для неизвестного формата")
+	}
+	if Text.Structured() || !CSV.Structured() {
+		t.Error("Structured вернул неверный результат")
+	}
+}
+
+// TestWriteReport сверяет вывод записей отчётов с эталонами.
+func TestWriteReport(t *testing.T) {
+	tasks := FromTasks(testTasks(t), testNow)
+	reports := map[string]func(f Format, buf *bytes.Buffer) error{
+		"trash": func(f Format, buf *bytes.Buffer) error {
+			return WriteReport(buf, f, []TrashEntry{{DeletedAt: "2025-03-14T11:00:00Z", Task: tasks[1]}})
+		},
+		"views": func(f Format, buf *bytes.Buffer) error {
+			args := []string{"--filter=pending", "--query", "project:work and tag:infra"}
+			return WriteReport(buf, f, []View{{Name: "work", Args: args, Line: `--filter=pending --query "project:work and tag:infra"`}})
+		},
+		"timesheet": func(f Format, buf *bytes.Buffer) error {
+			return WriteReport(buf, f, []TimeTotal{
+				{Group: "task", Key: "2", Label: `[2] Deploy "API", v2`, Seconds: 5400},
+				{Group: "tag", Key: "", Label: "(без тегов)", Seconds: 600},
+				{Group: "day", Key: "2025-03-14", Label: "2025-03-14 пт", Seconds: 6000},
+			})
+		},
+		"operations": func(f Format, buf *bytes.Buffer) error {
+			return WriteReport(buf, f, []Operation{
+				{ID: 2, Kind: "clear", Time: "2025-03-14T11:00:00Z", TaskIDs: []int{1, 3}, Undone: true},
+				{ID: 1, Kind: "add", Time: "2025-03-14T10:00:00Z", TaskIDs: []int{1}},
+			})
+		},
+		"tags": func(f Format, buf *bytes.Buffer) error {
+			return WriteReport(buf, f, []TagCount{{Tag: "infra", Total: 2, Pending: 1}})
+		},
+		"projects": func(f Format, buf *bytes.Buffer) error {
+			return WriteReport(buf, f, []Project{
+				{Project: "work.release", Total: 4, Done: 1, Percent: 25},
+				{Project: "", Total: 1, Done: 1, Percent: 100, Archived: true},
+			})
+		},
+		"events": func(f Format, buf *bytes.Buffer) error {
+			return WriteReport(buf, f, []Event{{
+				Time: "2025-03-14T11:00:00Z", User: "alice", Op: "add", TaskID: 1,
+				Change: `создана: "Купить хлеб"`, After: &tasks[0],
+			}})
+		},
+	}
+	for name, write := range reports {
+		for _, f := range []Format{JSON, CSV} {
+			var buf bytes.Buffer
+			if err := write(f, &buf); err != nil {
+				t.Fatalf("WriteReport(%s, %s) вернул ошибку: %v", name, f, err)
+			}
+			checkGolden(t, "report-"+name+"."+f.String()+".golden", buf.Bytes())
+		}
+	}
+
+	// Пустой отчёт — пустой массив в JSON и один заголовок в TSV
+	var buf bytes.Buffer
+	if err := WriteReport[TagCount](&buf, JSON, nil); err != nil || buf.String() != "[]\n" {
+		t.Errorf("пустой отчёт в JSON = %q, %v", buf.String(), err)
+	}
+	buf.Reset()
+	if err := WriteReport[TagCount](&buf, TSV, nil); err != nil || buf.String() != "tag\ttotal\tpending\n" {
+		t.Errorf("пустой отчёт в TSV = %q, %v", buf.String(), err)
+	}
+}
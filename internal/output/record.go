@@ -0,0 +1,89 @@
+package output
+
+import (
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// Task — запись о задаче в машиночитаемом выводе (схема версии 1).
+// Все поля выводятся всегда: отсутствующие значения — пустая строка,
+// ноль, false или пустой список, но не null. Новые поля добавляются
+// только в конец, существующие не переименовываются и не удаляются.
+type Task struct {
+	ID              int      `json:"id" yaml:"id"`                             // ID задачи
+	Title           string   `json:"title" yaml:"title"`                       // название
+	Status          string   `json:"status" yaml:"status"`                     // todo, in-progress, blocked, review, done, cancelled
+	Priority        string   `json:"priority" yaml:"priority"`                 // low, normal, high, urgent
+	Project         string   `json:"project" yaml:"project"`                   // проект, например work.release
+	Tags            []string `json:"tags" yaml:"tags"`                         // теги по алфавиту
+	ParentID        int      `json:"parent_id" yaml:"parent_id"`               // ID родительской задачи (0 — нет)
+	BlockedBy       []int    `json:"blocked_by" yaml:"blocked_by"`             // ID задач, которые она ждёт
+	Due             string   `json:"due" yaml:"due"`                           // срок: 2025-03-14 или RFC 3339 со временем
+	Recur           string   `json:"recur" yaml:"recur"`                       // правило повторения: every mon,thu
+	CreatedAt       string   `json:"created_at" yaml:"created_at"`             // RFC 3339
+	UpdatedAt       string   `json:"updated_at" yaml:"updated_at"`             // RFC 3339
+	CompletedAt     string   `json:"completed_at" yaml:"completed_at"`         // RFC 3339
+	TrackedSeconds  int64    `json:"tracked_seconds" yaml:"tracked_seconds"`   // учтённое время в секундах
+	Running         bool     `json:"running" yaml:"running"`                   // идёт таймер
+	ProjectArchived bool     `json:"project_archived" yaml:"project_archived"` // проект задачи в архиве
+	InArchive       bool     `json:"in_archive" yaml:"in_archive"`             // задача в архиве завершённых задач
+	Notes           string   `json:"notes" yaml:"notes"`                       // заметки, могут быть многострочными
+}
+
+// Result — итог изменяющей команды: что изменилось и чем всё закончилось.
+type Result struct {
+	Command string `json:"command" yaml:"command"`                 // команда, например "done" или "tag add"
+	OK      bool   `json:"ok" yaml:"ok"`                           // команда выполнена без ошибок
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"` // текст ошибки без префикса "Ошибка:", если ok — false
+	Tasks   []Task `json:"tasks" yaml:"tasks"`                     // созданные и изменённые задачи в новом состоянии
+	Removed []Task `json:"removed" yaml:"removed"`                 // задачи, убранные из списка (корзина, архив)
+}
+
+// formatTime — приватная функция, возвращает момент в формате RFC 3339
+// или пустую строку для нулевого значения.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// FromTask строит запись о задаче. Учтённое время идущего таймера
+// считается до момента now.
+func FromTask(t task.Task, now time.Time) Task {
+	rec := Task{
+		ID:              t.ID,
+		Title:           t.Title,
+		Status:          t.Status.String(),
+		Priority:        t.Priority.String(),
+		Project:         t.Project,
+		Tags:            append([]string{}, t.Tags...),
+		ParentID:        t.ParentID,
+		BlockedBy:       append([]int{}, t.BlockedBy...),
+		Due:             formatTime(t.DueAt),
+		CreatedAt:       formatTime(t.CreatedAt),
+		UpdatedAt:       formatTime(t.UpdatedAt),
+		CompletedAt:     formatTime(t.CompletedAt),
+		TrackedSeconds:  int64(t.Tracked(now) / time.Second),
+		Running:         t.Running(),
+		ProjectArchived: t.Archived,
+		Notes:           t.Notes,
+	}
+	if !t.DueAt.IsZero() && !task.HasClock(t.DueAt) {
+		rec.Due = t.DueAt.Format(time.DateOnly)
+	}
+	if !t.Recur.IsZero() {
+		rec.Recur = t.Recur.String()
+	}
+	return rec
+}
+
+// FromTasks строит записи о задачах в том же порядке.
+func FromTasks(tasks []task.Task, now time.Time) []Task {
+	records := make([]Task, 0, len(tasks))
+	for _, t := range tasks {
+		records = append(records, FromTask(t, now))
+	}
+	return records
+}
@@ -0,0 +1,115 @@
+package output
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Report — запись отчёта команды, которая выводит не задачи: корзины,
+// представлений, учёта времени, журналов, тегов или проектов. В CSV
+// и TSV каждая запись — строка таблицы со столбцами header.
+type Report interface {
+	header() []string
+	values() []string
+}
+
+// TrashEntry — задача в корзине.
+type TrashEntry struct {
+	DeletedAt string `json:"deleted_at" yaml:"deleted_at"` // когда задача удалена, RFC 3339
+	Task      Task   `json:"task" yaml:"task"`             // задача в момент удаления
+}
+
+func (TrashEntry) header() []string { return append([]string{"deleted_at"}, columns...) }
+func (e TrashEntry) values() []string {
+	return append([]string{e.DeletedAt}, row(e.Task)...)
+}
+
+// View — сохранённое представление.
+type View struct {
+	Name string   `json:"name" yaml:"name"` // имя представления
+	Args []string `json:"args" yaml:"args"` // аргументы команды list
+	Line string   `json:"line" yaml:"line"` // те же аргументы одной строкой, как в командной строке
+}
+
+func (View) header() []string   { return []string{"name", "line"} }
+func (v View) values() []string { return []string{v.Name, v.Line} }
+
+// TimeTotal — учтённое время одной группы отчёта timesheet.
+type TimeTotal struct {
+	Group   string `json:"group" yaml:"group"`     // группировка: task, tag или day
+	Key     string `json:"key" yaml:"key"`         // ID задачи, тег или дата 2006-01-02; "" — задачи без тегов
+	Label   string `json:"label" yaml:"label"`     // подпись группы, как в текстовом отчёте
+	Seconds int64  `json:"seconds" yaml:"seconds"` // учтённое время в секундах
+}
+
+func (TimeTotal) header() []string { return []string{"group", "key", "label", "seconds"} }
+func (t TimeTotal) values() []string {
+	return []string{t.Group, t.Key, t.Label, strconv.FormatInt(t.Seconds, 10)}
+}
+
+// Operation — запись журнала операций для undo и redo.
+type Operation struct {
+	ID      int    `json:"id" yaml:"id"`             // порядковый номер операции
+	Kind    string `json:"kind" yaml:"kind"`         // add, update, done, delete, clear...
+	Time    string `json:"time" yaml:"time"`         // когда операция выполнена, RFC 3339
+	TaskIDs []int  `json:"task_ids" yaml:"task_ids"` // ID затронутых задач
+	Undone  bool   `json:"undone" yaml:"undone"`     // операция отменена и доступна для redo
+}
+
+func (Operation) header() []string { return []string{"id", "kind", "time", "task_ids", "undone"} }
+func (o Operation) values() []string {
+	return []string{strconv.Itoa(o.ID), o.Kind, o.Time, joinInts(o.TaskIDs), strconv.FormatBool(o.Undone)}
+}
+
+// TagCount — тег и число задач с ним.
+type TagCount struct {
+	Tag     string `json:"tag" yaml:"tag"`         // имя тега
+	Total   int    `json:"total" yaml:"total"`     // всего задач с тегом
+	Pending int    `json:"pending" yaml:"pending"` // из них невыполненных
+}
+
+func (TagCount) header() []string { return []string{"tag", "total", "pending"} }
+func (c TagCount) values() []string {
+	return []string{c.Tag, strconv.Itoa(c.Total), strconv.Itoa(c.Pending)}
+}
+
+// Project — проект и прогресс выполнения его задач.
+type Project struct {
+	Project  string `json:"project" yaml:"project"`   // полное имя проекта; "" — задачи без проекта
+	Total    int    `json:"total" yaml:"total"`       // всего задач, включая подпроекты
+	Done     int    `json:"done" yaml:"done"`         // из них выполнено
+	Percent  int    `json:"percent" yaml:"percent"`   // доля выполненных в процентах
+	Archived bool   `json:"archived" yaml:"archived"` // проект в архиве
+}
+
+func (Project) header() []string { return []string{"project", "total", "done", "percent", "archived"} }
+func (p Project) values() []string {
+	return []string{p.Project, strconv.Itoa(p.Total), strconv.Itoa(p.Done), strconv.Itoa(p.Percent), strconv.FormatBool(p.Archived)}
+}
+
+// Event — событие журнала аудита. Before и After — состояние задачи
+// до и после изменения; у созданной задачи before — null, у удалённой
+// after — null. В CSV и TSV вместо них выводится описание изменения.
+type Event struct {
+	Time   string `json:"time" yaml:"time"`       // когда произошло изменение, RFC 3339
+	User   string `json:"user" yaml:"user"`       // пользователь ОС, выполнивший операцию
+	Op     string `json:"op" yaml:"op"`           // add, update, done, delete...
+	TaskID int    `json:"task_id" yaml:"task_id"` // ID изменённой задачи
+	Change string `json:"change" yaml:"change"`   // что изменилось, как в текстовом выводе
+	Before *Task  `json:"before" yaml:"before"`   // состояние до изменения
+	After  *Task  `json:"after" yaml:"after"`     // состояние после изменения
+}
+
+func (Event) header() []string { return []string{"time", "user", "op", "task_id", "change"} }
+func (e Event) values() []string {
+	return []string{e.Time, e.User, e.Op, strconv.Itoa(e.TaskID), e.Change}
+}
+
+// joinInts — приватная функция, перечисляет числа через запятую.
+func joinInts(ids []int) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.Itoa(id)
+	}
+	return strings.Join(s, ",")
+}
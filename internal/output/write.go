@@ -0,0 +1,150 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// columns — столбцы задачи в CSV и TSV в порядке полей Task.
+var columns = []string{
+	"id", "title", "status", "priority", "project", "tags", "parent_id", "blocked_by",
+	"due", "recur", "created_at", "updated_at", "completed_at", "tracked_seconds",
+	"running", "project_archived", "in_archive", "notes",
+}
+
+// row — приватная функция, возвращает значения столбцов задачи.
+// Теги и зависимости перечисляются через запятую.
+func row(t Task) []string {
+	return []string{
+		strconv.Itoa(t.ID), t.Title, t.Status, t.Priority, t.Project,
+		strings.Join(t.Tags, ","), strconv.Itoa(t.ParentID), joinInts(t.BlockedBy),
+		t.Due, t.Recur, t.CreatedAt, t.UpdatedAt, t.CompletedAt,
+		strconv.FormatInt(t.TrackedSeconds, 10), strconv.FormatBool(t.Running),
+		strconv.FormatBool(t.ProjectArchived), strconv.FormatBool(t.InArchive), t.Notes,
+	}
+}
+
+// WriteTasks выводит записи о задачах: массивом в JSON и YAML,
+// таблицей с заголовком в CSV и TSV.
+func WriteTasks(w io.Writer, f Format, tasks []Task) error {
+	if tasks == nil {
+		tasks = []Task{}
+	}
+	switch f {
+	case CSV, TSV:
+		rows := make([][]string, 0, len(tasks))
+		for _, t := range tasks {
+			rows = append(rows, row(t))
+		}
+		return writeTable(w, f, columns, rows)
+	}
+	return encode(w, f, tasks)
+}
+
+// WriteTask выводит запись об одной задаче: объектом в JSON и YAML,
+// таблицей из одной строки в CSV и TSV.
+func WriteTask(w io.Writer, f Format, t Task) error {
+	switch f {
+	case CSV, TSV:
+		return writeTable(w, f, columns, [][]string{row(t)})
+	}
+	return encode(w, f, t)
+}
+
+// WriteResult выводит итог команды: объектом в JSON и YAML. В CSV и TSV
+// выводятся только затронутые задачи с дополнительным первым столбцом
+// change (changed или removed); ошибка в таблицу не попадает.
+func WriteResult(w io.Writer, f Format, r Result) error {
+	if r.Tasks == nil {
+		r.Tasks = []Task{}
+	}
+	if r.Removed == nil {
+		r.Removed = []Task{}
+	}
+	switch f {
+	case CSV, TSV:
+		rows := make([][]string, 0, len(r.Tasks)+len(r.Removed))
+		for _, t := range r.Tasks {
+			rows = append(rows, append([]string{"changed"}, row(t)...))
+		}
+		for _, t := range r.Removed {
+			rows = append(rows, append([]string{"removed"}, row(t)...))
+		}
+		return writeTable(w, f, append([]string{"change"}, columns...), rows)
+	}
+	return encode(w, f, r)
+}
+
+// WriteReport выводит записи отчёта: массивом в JSON и YAML,
+// таблицей с заголовком в CSV и TSV.
+func WriteReport[R Report](w io.Writer, f Format, records []R) error {
+	if records == nil {
+		records = []R{}
+	}
+	switch f {
+	case CSV, TSV:
+		var zero R
+		rows := make([][]string, 0, len(records))
+		for _, r := range records {
+			rows = append(rows, r.values())
+		}
+		return writeTable(w, f, zero.header(), rows)
+	}
+	return encode(w, f, records)
+}
+
+// encode — приватная функция, выводит значение в JSON или YAML.
+func encode(w io.Writer, f Format, v any) error {
+	switch f {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(v)
+	case YAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return fmt.Errorf("формат %s не поддерживается для записей", f)
+}
+
+// tsvEscaper — экранирование значений TSV: табуляция, перевод строки
+// и обратная косая черта записываются как \t, \n, \r и \\.
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// writeTable — приватная функция, выводит таблицу с заголовком в CSV или TSV.
+func writeTable(w io.Writer, f Format, header []string, rows [][]string) error {
+	if f == CSV {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	}
+
+	var b strings.Builder
+	for _, r := range append([][]string{header}, rows...) {
+		for i, v := range r {
+			if i > 0 {
+				b.WriteByte('\t')
+			}
+			b.WriteString(tsvEscaper.Replace(v))
+		}
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
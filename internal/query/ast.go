@@ -0,0 +1,150 @@
+// Package query реализует язык фильтров задач для list и search:
+//
+//	status:pending and (tag:infra or priority>=high) and due<+7d and title~"deploy"
+//
+// Запрос разбирается в дерево (AST) из операций and, or, not, сравнений
+// "поле оператор значение" и слов для поиска по названию.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// SyntaxError — ошибка разбора запроса с указанием столбца.
+type SyntaxError struct {
+	Column int    // номер столбца (в символах, с 1)
+	Msg    string // описание ошибки
+}
+
+// Error возвращает текст ошибки для пользователя.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("столбец %d: %s", e.Column, e.Msg)
+}
+
+// Pointer возвращает запрос src и строку под ним со стрелкой ^,
+// указывающей на место ошибки.
+func (e *SyntaxError) Pointer(src string) string {
+	column := max(e.Column, 1)
+	return src + "\n" + strings.Repeat(" ", column-1) + "^"
+}
+
+// errorAt — приватная функция, создаёт SyntaxError для столбца pos.
+func errorAt(pos int, format string, args ...any) *SyntaxError {
+	return &SyntaxError{Column: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// env — контекст вычисления запроса.
+type env struct {
+	now   time.Time   // момент, относительно которого считаются даты
+	tasks []task.Task // весь список задач (для is:ready и is:blocked)
+}
+
+// Node — узел дерева запроса.
+type Node interface {
+	Pos() int       // столбец начала узла в запросе
+	String() string // канонический вид узла, который разбирается в тот же узел
+	eval(t task.Task, e *env) bool
+}
+
+// And — истинен, если истинны оба операнда.
+type And struct {
+	Left, Right Node
+}
+
+// Or — истинен, если истинен хотя бы один операнд.
+type Or struct {
+	Left, Right Node
+}
+
+// Not — отрицание операнда.
+type Not struct {
+	X   Node
+	pos int
+}
+
+// Compare — сравнение поля задачи со значением: priority>=high.
+type Compare struct {
+	Field string // имя поля в нижнем регистре
+	Op    string // оператор: : = != < <= > >= ~
+	Value string // значение
+	pos   int
+
+	match func(t task.Task, e *env) bool // скомпилированная проверка
+}
+
+// Text — слово или строка без поля: ищется в названии задачи
+// без учёта регистра.
+type Text struct {
+	Value string
+	pos   int
+}
+
+// Pos возвращает столбец начала узла.
+func (n *And) Pos() int     { return n.Left.Pos() }
+func (n *Or) Pos() int      { return n.Left.Pos() }
+func (n *Not) Pos() int     { return n.pos }
+func (n *Compare) Pos() int { return n.pos }
+func (n *Text) Pos() int    { return n.pos }
+
+// String возвращает канонический вид узла.
+func (n *And) String() string     { return "(" + n.Left.String() + " and " + n.Right.String() + ")" }
+func (n *Or) String() string      { return "(" + n.Left.String() + " or " + n.Right.String() + ")" }
+func (n *Not) String() string     { return "not " + n.X.String() }
+func (n *Compare) String() string { return n.Field + n.Op + quote(n.Value) }
+func (n *Text) String() string    { return quote(n.Value) }
+
+func (n *And) eval(t task.Task, e *env) bool     { return n.Left.eval(t, e) && n.Right.eval(t, e) }
+func (n *Or) eval(t task.Task, e *env) bool      { return n.Left.eval(t, e) || n.Right.eval(t, e) }
+func (n *Not) eval(t task.Task, e *env) bool     { return !n.X.eval(t, e) }
+func (n *Compare) eval(t task.Task, e *env) bool { return n.match(t, e) }
+func (n *Text) eval(t task.Task, e *env) bool {
+	return strings.Contains(strings.ToLower(t.Title), strings.ToLower(n.Value))
+}
+
+// quote — приватная функция, записывает значение словом, если это
+// возможно, иначе — строкой в кавычках.
+func quote(s string) string {
+	if isWord(s) {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+// Query — разобранный запрос.
+type Query struct {
+	Root Node      // корень дерева (nil — пустой запрос, подходит любая задача)
+	now  time.Time // момент разбора, относительно которого считаются даты
+}
+
+// String возвращает канонический вид запроса.
+func (q *Query) String() string {
+	if q.Root == nil {
+		return ""
+	}
+	return q.Root.String()
+}
+
+// Match сообщает, подходит ли задача t под запрос. Список tasks нужен
+// условиям, которые зависят от других задач (is:ready, is:blocked).
+func (q *Query) Match(t task.Task, tasks []task.Task) bool {
+	if q.Root == nil {
+		return true
+	}
+	return q.Root.eval(t, &env{now: q.now, tasks: tasks})
+}
+
+// Filter возвращает задачи из tasks, подходящие под запрос, в том же порядке.
+func (q *Query) Filter(tasks []task.Task) []task.Task {
+	result := make([]task.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if q.Match(t, tasks) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
@@ -0,0 +1,255 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// matcher — скомпилированная проверка условия для задачи.
+type matcher func(t task.Task, e *env) bool
+
+// field — поле задачи, доступное в запросах.
+type field struct {
+	ops     []string                                               // допустимые операторы
+	compile func(op, value string, now time.Time) (matcher, error) // проверка для значения
+}
+
+// Наборы операторов для разных видов полей.
+var (
+	eqOps    = []string{":", "=", "!="}
+	orderOps = []string{":", "=", "!=", "<", "<=", ">", ">="}
+	textOps  = []string{":", "~", "=", "!="}
+)
+
+// fields — поля, по которым можно фильтровать задачи.
+var fields = map[string]field{
+	"status":    {eqOps, compileStatus},
+	"tag":       {eqOps, compileTag},
+	"project":   {eqOps, compileProject},
+	"priority":  {orderOps, compilePriority},
+	"due":       {orderOps, compileTime(func(t task.Task) time.Time { return t.DueAt })},
+	"created":   {orderOps, compileTime(func(t task.Task) time.Time { return t.CreatedAt })},
+	"updated":   {orderOps, compileTime(func(t task.Task) time.Time { return t.UpdatedAt })},
+	"completed": {orderOps, compileTime(func(t task.Task) time.Time { return t.CompletedAt })},
+	"title":     {textOps, compileText(func(t task.Task) string { return t.Title })},
+	"notes":     {textOps, compileText(func(t task.Task) string { return t.Notes })},
+	"id":        {orderOps, compileInt(func(t task.Task) int { return t.ID })},
+	"parent":    {orderOps, compileInt(func(t task.Task) int { return t.ParentID })},
+	"is":        {[]string{":"}, compileIs},
+}
+
+// conditions — значения поля is.
+var conditions = map[string]matcher{
+	"open":      func(t task.Task, _ *env) bool { return !t.IsClosed() },
+	"closed":    func(t task.Task, _ *env) bool { return t.IsClosed() },
+	"overdue":   func(t task.Task, e *env) bool { return t.IsOverdue(e.now) },
+	"ready":     func(t task.Task, e *env) bool { return task.IsReady(e.tasks, t) },
+	"blocked":   func(t task.Task, e *env) bool { return !task.IsReady(e.tasks, t) && !t.IsClosed() },
+	"running":   func(t task.Task, _ *env) bool { return t.Running() },
+	"recurring": func(t task.Task, _ *env) bool { return !t.Recur.IsZero() },
+	"subtask":   func(t task.Task, _ *env) bool { return t.ParentID != 0 },
+}
+
+// FieldNames возвращает имена полей запроса по алфавиту.
+func FieldNames() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ConditionNames возвращает значения поля is по алфавиту.
+func ConditionNames() []string {
+	names := make([]string, 0, len(conditions))
+	for name := range conditions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// compile — приватная функция, проверяет поле, оператор и значение
+// сравнения и строит для него узел с готовой проверкой.
+func compile(name, op, value token, now time.Time) (*Compare, error) {
+	fieldName := strings.ToLower(name.text)
+	f, ok := fields[fieldName]
+	if !ok {
+		return nil, errorAt(name.pos, "неизвестное поле %q, доступны: %s", name.text, strings.Join(FieldNames(), ", "))
+	}
+	if !slices.Contains(f.ops, op.text) {
+		return nil, errorAt(op.pos, "оператор %s не поддерживается для поля %s, используйте: %s",
+			op.text, fieldName, strings.Join(f.ops, " "))
+	}
+	match, err := f.compile(op.text, value.text, now)
+	if err != nil {
+		return nil, errorAt(value.pos, "%s", err)
+	}
+	return &Compare{Field: fieldName, Op: op.text, Value: value.text, pos: name.pos, match: match}, nil
+}
+
+// negateIf — приватная функция, возвращает проверку, обратную m,
+// для оператора !=.
+func negateIf(op string, m matcher) matcher {
+	if op != "!=" {
+		return m
+	}
+	return func(t task.Task, e *env) bool { return !m(t, e) }
+}
+
+// compileStatus — status:pending, status:completed, status:all
+// или имя состояния (status:in-progress).
+func compileStatus(op, value string, _ time.Time) (matcher, error) {
+	var m matcher
+	switch strings.ToLower(value) {
+	case "all":
+		m = func(task.Task, *env) bool { return true }
+	case "pending", "open":
+		m = func(t task.Task, _ *env) bool { return !t.IsClosed() }
+	case "completed":
+		m = func(t task.Task, _ *env) bool { return t.Status == task.StatusDone }
+	case "closed":
+		m = func(t task.Task, _ *env) bool { return t.IsClosed() }
+	default:
+		s, err := task.ParseStatus(value)
+		if err != nil {
+			return nil, err
+		}
+		m = func(t task.Task, _ *env) bool { return t.Status == s }
+	}
+	return negateIf(op, m), nil
+}
+
+// compileTag — tag:infra (задача с тегом), tag!=infra (без тега).
+func compileTag(op, value string, _ time.Time) (matcher, error) {
+	tag, err := task.NormalizeTag(value)
+	if err != nil {
+		return nil, err
+	}
+	return negateIf(op, func(t task.Task, _ *env) bool { return t.HasTag(tag) }), nil
+}
+
+// compileProject — project:work (проект work и его подпроекты),
+// project:"" — задачи без проекта.
+func compileProject(op, value string, _ time.Time) (matcher, error) {
+	project, err := task.NormalizeProject(value)
+	if err != nil {
+		return nil, err
+	}
+	return negateIf(op, func(t task.Task, _ *env) bool { return task.InProject(t.Project, project) }), nil
+}
+
+// compareInts — приватная функция, сравнивает a и b оператором op.
+func compareInts(op string, a, b int) bool {
+	switch op {
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return a == b
+}
+
+// compilePriority — priority>=high, priority:low...
+func compilePriority(op, value string, _ time.Time) (matcher, error) {
+	p, err := task.ParsePriority(value)
+	if err != nil {
+		return nil, err
+	}
+	return func(t task.Task, _ *env) bool { return compareInts(op, int(t.Priority), int(p)) }, nil
+}
+
+// compileInt — id>10, parent:3...
+func compileInt(get func(task.Task) int) func(op, value string, _ time.Time) (matcher, error) {
+	return func(op, value string, _ time.Time) (matcher, error) {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("ожидалось число, получено %q", value)
+		}
+		return func(t task.Task, _ *env) bool { return compareInts(op, get(t), n) }, nil
+	}
+}
+
+// compileTime — due<+7d, created>=2025-03-01, completed:today, due:none.
+// Дата без времени означает весь день: due<=fri — до конца пятницы.
+// Задачи без даты подходят только под due:none и due!=...
+func compileTime(get func(task.Task) time.Time) func(op, value string, now time.Time) (matcher, error) {
+	return func(op, value string, now time.Time) (matcher, error) {
+		if strings.EqualFold(value, "none") {
+			switch op {
+			case ":", "=":
+				return func(t task.Task, _ *env) bool { return get(t).IsZero() }, nil
+			case "!=":
+				return func(t task.Task, _ *env) bool { return !get(t).IsZero() }, nil
+			}
+			return nil, errors.New("none можно сравнивать только через : и !=")
+		}
+
+		from, err := task.ParseDate(value, now)
+		if err != nil {
+			return nil, err
+		}
+		to := from.Add(time.Minute)
+		if !task.HasClock(from) {
+			to = from.AddDate(0, 0, 1)
+		}
+
+		return func(t task.Task, _ *env) bool {
+			at := get(t)
+			if at.IsZero() {
+				return op == "!="
+			}
+			inside := !at.Before(from) && at.Before(to)
+			switch op {
+			case "!=":
+				return !inside
+			case "<":
+				return at.Before(from)
+			case "<=":
+				return at.Before(to)
+			case ">":
+				return !at.Before(to)
+			case ">=":
+				return !at.Before(from)
+			}
+			return inside
+		}, nil
+	}
+}
+
+// compileText — title~deploy и title:deploy (подстрока без учёта регистра),
+// title="Релиз 1.0" (точное совпадение без учёта регистра).
+func compileText(get func(task.Task) string) func(op, value string, _ time.Time) (matcher, error) {
+	return func(op, value string, _ time.Time) (matcher, error) {
+		lower := strings.ToLower(value)
+		switch op {
+		case "=":
+			return func(t task.Task, _ *env) bool { return strings.EqualFold(get(t), value) }, nil
+		case "!=":
+			return func(t task.Task, _ *env) bool { return !strings.EqualFold(get(t), value) }, nil
+		}
+		return func(t task.Task, _ *env) bool { return strings.Contains(strings.ToLower(get(t)), lower) }, nil
+	}
+}
+
+// compileIs — is:overdue, is:ready, is:blocked и другие состояния задачи.
+func compileIs(_, value string, _ time.Time) (matcher, error) {
+	m, ok := conditions[strings.ToLower(value)]
+	if !ok {
+		return nil, fmt.Errorf("неизвестное условие is:%s, доступны: %s", value, strings.Join(ConditionNames(), ", "))
+	}
+	return m, nil
+}
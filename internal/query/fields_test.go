@@ -0,0 +1,68 @@
+package query
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// TestQuery_Filter проверяет вычисление запросов на наборе задач.
+func TestQuery_Filter(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2025, 3, d, h, 0, 0, 0, time.Local) }
+	tasks := []task.Task{
+		{ID: 1, Title: "Deploy API", Tags: []string{"infra"}, Priority: task.PriorityNormal, DueAt: day(15, 0), Project: "work.release"},
+		{ID: 2, Title: "Купить хлеб", Priority: task.PriorityHigh, Status: task.StatusDone, CompletedAt: day(13, 18)},
+		{ID: 3, Title: "Починить deploy-скрипт", Priority: task.PriorityUrgent, DueAt: day(30, 0), BlockedBy: []int{1}},
+		{ID: 4, Title: "Обзор", Priority: task.PriorityLow, Status: task.StatusInProgress, DueAt: day(10, 0), ParentID: 1, Notes: "см. RFC"},
+		{ID: 5, Title: "Старое", Status: task.StatusCancelled, Tags: []string{"infra"}},
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"", []int{1, 2, 3, 4, 5}},
+		{"deploy", []int{1, 3}},
+		{`status:pending and (tag:infra or priority>=high) and due<+7d and title~"deploy"`, []int{1}},
+		{"status:pending", []int{1, 3, 4}},
+		{"status:completed", []int{2}},
+		{"status!=done", []int{1, 3, 4, 5}},
+		{"status:in_progress", []int{4}},
+		{"tag:infra", []int{1, 5}},
+		{"tag!=infra", []int{2, 3, 4}},
+		{"priority>=high", []int{2, 3}},
+		{"priority<normal", []int{4}},
+		{"project:work", []int{1}},
+		{`project:""`, []int{2, 3, 4, 5}},
+		{"due:none", []int{2, 5}},
+		{"due:tomorrow", []int{1}},
+		{"due<=tomorrow", []int{1, 4}},
+		{"due>tomorrow", []int{3}},
+		{"completed>=2025-03-13", []int{2}},
+		{`completed<"2025-03-13 12:00"`, nil},
+		{"title=обзор", []int{4}},
+		{"notes~rfc", []int{4}},
+		{"id>=4 or parent:1", []int{4, 5}},
+		{"is:overdue", []int{4}},
+		{"is:ready", []int{1, 4}},
+		{"is:blocked", []int{3}},
+		{"is:subtask", []int{4}},
+		{"not is:closed and not deploy", []int{4}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query, testNow)
+		if err != nil {
+			t.Errorf("Parse(%q) вернул ошибку: %v", tt.query, err)
+			continue
+		}
+		var got []int
+		for _, matched := range q.Filter(tasks) {
+			got = append(got, matched.ID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: получено %v, ожидалось %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind — вид лексемы запроса.
+type tokenKind int
+
+const (
+	tokEOF    tokenKind = iota // конец запроса
+	tokWord                    // слово: поле, значение или and/or/not
+	tokString                  // строка в кавычках
+	tokOp                      // оператор сравнения: : = != < <= > >= ~
+	tokLParen                  // (
+	tokRParen                  // )
+)
+
+// token — лексема запроса.
+type token struct {
+	kind tokenKind
+	text string // текст слова или оператора, содержимое строки без кавычек
+	pos  int    // номер столбца (в символах, с 1)
+}
+
+// operators — операторы сравнения; двухсимвольные проверяются первыми.
+var operators = []string{"!=", "<=", ">=", ":", "=", "<", ">", "~"}
+
+// isWordRune сообщает, может ли символ входить в слово.
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune(`()"!=<>:~`, r)
+}
+
+// isWord сообщает, записывается ли значение одним словом без кавычек.
+func isWord(s string) bool {
+	if s == "" || isKeyword(s) {
+		return false
+	}
+	for _, r := range s {
+		if !isWordRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isKeyword сообщает, является ли слово ключевым (and, or, not).
+func isKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "and", "or", "not":
+		return true
+	}
+	return false
+}
+
+// lex разбивает запрос на лексемы.
+func lex(src string) ([]token, error) {
+	runes := []rune(src)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if unicode.IsSpace(r) {
+			i++
+			continue
+		}
+
+		tok := token{pos: i + 1}
+		switch {
+		case r == '(':
+			tok.kind, tok.text = tokLParen, "("
+			i++
+		case r == ')':
+			tok.kind, tok.text = tokRParen, ")"
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, errorAt(tok.pos, "незакрытая кавычка")
+			}
+			text, err := strconv.Unquote(string(runes[i : end+1]))
+			if err != nil {
+				return nil, errorAt(tok.pos, "некорректная строка в кавычках")
+			}
+			// Экранирование вроде \x80 даёт байты, которые не являются UTF-8:
+			// такую строку нельзя ни сравнить с текстом задачи, ни вывести обратно.
+			if !utf8.ValidString(text) {
+				return nil, errorAt(tok.pos, "строка в кавычках не в кодировке UTF-8")
+			}
+			tok.kind, tok.text = tokString, text
+			i = end + 1
+		case isWordRune(r):
+			end := i
+			for end < len(runes) && isWordRune(runes[end]) {
+				end++
+			}
+			tok.kind, tok.text = tokWord, string(runes[i:end])
+			i = end
+		default:
+			rest := string(runes[i:])
+			for _, op := range operators {
+				if strings.HasPrefix(rest, op) {
+					tok.kind, tok.text = tokOp, op
+					break
+				}
+			}
+			if tok.kind != tokOp {
+				return nil, errorAt(tok.pos, "неожиданный символ %q", r)
+			}
+			i += len([]rune(tok.text))
+		}
+		tokens = append(tokens, tok)
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(runes) + 1}), nil
+}
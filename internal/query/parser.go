@@ -0,0 +1,173 @@
+package query
+
+import (
+	"strings"
+	"time"
+)
+
+// maxDepth — максимальная вложенность скобок и not в запросе.
+const maxDepth = 100
+
+// parser — разбор запроса методом рекурсивного спуска:
+//
+//	or      = and { "or" and }
+//	and     = unary { ["and"] unary }
+//	unary   = "not" unary | primary
+//	primary = "(" or ")" | word op value | word | string
+type parser struct {
+	tokens []token
+	i      int
+	depth  int
+	now    time.Time
+}
+
+// Parse разбирает запрос относительно момента now (от него считаются
+// даты вида +7d и today). Пустой запрос подходит под любую задачу.
+// Ошибки разбора возвращаются как *SyntaxError со столбцом.
+func Parse(src string, now time.Time) (*Query, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, now: now}
+	if p.peek().kind == tokEOF {
+		return &Query{now: now}, nil
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	switch tok := p.peek(); tok.kind {
+	case tokEOF:
+	case tokRParen:
+		return nil, errorAt(tok.pos, "лишняя закрывающая скобка")
+	default:
+		return nil, errorAt(tok.pos, "неожиданное %q", tok.text)
+	}
+	return &Query{Root: root, now: now}, nil
+}
+
+// peek возвращает текущую лексему.
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+// next возвращает текущую лексему и переходит к следующей.
+func (p *parser) next() token {
+	tok := p.tokens[p.i]
+	if tok.kind != tokEOF {
+		p.i++
+	}
+	return tok
+}
+
+// isKeywordToken сообщает, является ли лексема ключевым словом kw.
+func isKeywordToken(tok token, kw string) bool {
+	return tok.kind == tokWord && strings.EqualFold(tok.text, kw)
+}
+
+// parseOr разбирает условия, объединённые через or.
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for isKeywordToken(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// parseAnd разбирает условия, объединённые через and или пробел.
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		switch {
+		case isKeywordToken(tok, "and"):
+			p.next()
+		case tok.kind == tokWord && !isKeywordToken(tok, "or"),
+			tok.kind == tokString, tok.kind == tokLParen:
+			// Условия через пробел без and тоже объединяются через and
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+}
+
+// parseUnary разбирает условие с отрицанием not.
+func (p *parser) parseUnary() (Node, error) {
+	tok := p.peek()
+	if !isKeywordToken(tok, "not") {
+		return p.parsePrimary()
+	}
+	if p.depth++; p.depth > maxDepth {
+		return nil, errorAt(tok.pos, "слишком глубокая вложенность")
+	}
+	defer func() { p.depth-- }()
+
+	p.next()
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &Not{X: x, pos: tok.pos}, nil
+}
+
+// parsePrimary разбирает условие в скобках, сравнение или слово.
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		if p.depth++; p.depth > maxDepth {
+			return nil, errorAt(tok.pos, "слишком глубокая вложенность")
+		}
+		defer func() { p.depth-- }()
+
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			if closing.kind == tokEOF {
+				return nil, errorAt(tok.pos, "незакрытая скобка")
+			}
+			return nil, errorAt(closing.pos, "ожидалась закрывающая скобка, получено %q", closing.text)
+		}
+		return inner, nil
+	case tokString:
+		return &Text{Value: tok.text, pos: tok.pos}, nil
+	case tokWord:
+		if isKeyword(tok.text) {
+			return nil, errorAt(tok.pos, "ожидалось условие перед %q", tok.text)
+		}
+		if p.peek().kind != tokOp {
+			return &Text{Value: tok.text, pos: tok.pos}, nil
+		}
+		op := p.next()
+		value := p.next()
+		if value.kind != tokWord && value.kind != tokString {
+			return nil, errorAt(value.pos, "ожидалось значение после %s%s", tok.text, op.text)
+		}
+		return compile(tok, op, value, p.now)
+	case tokOp:
+		return nil, errorAt(tok.pos, "перед оператором %s не указано поле", tok.text)
+	case tokRParen:
+		return nil, errorAt(tok.pos, "ожидалось условие перед )")
+	}
+	return nil, errorAt(tok.pos, "неожиданный конец запроса: ожидалось условие")
+}
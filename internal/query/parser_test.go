@@ -0,0 +1,140 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testNow — момент, относительно которого разбираются запросы в тестах
+// (пятница, 14 марта 2025).
+var testNow = time.Date(2025, 3, 14, 10, 0, 0, 0, time.Local)
+
+// TestParse проверяет разбор запросов и их канонический вид:
+// приоритет and над or, скобки, неявный and, отрицание и кавычки.
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"deploy", "deploy"},
+		{`"купить хлеб"`, `"купить хлеб"`},
+		{"status:pending", "status:pending"},
+		{"Priority >= high", "priority>=high"},
+		{"a or b and c", "(a or (b and c))"},
+		{"a b c", "((a and b) and c)"},
+		{"(a or b) c", "((a or b) and c)"},
+		{"not tag:infra", "not tag:infra"},
+		{"NOT (a OR b)", "not (a or b)"},
+		{`title~"deploy" and due<+7d`, "(title~deploy and due<+7d)"},
+		{`title:"and"`, `title:"and"`},
+		{`title="a \"b\""`, `title="a \"b\""`},
+		{`status:pending and (tag:infra or priority>=high) and due<+7d and title~"deploy"`,
+			"(((status:pending and (tag:infra or priority>=high)) and due<+7d) and title~deploy)"},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.in, testNow)
+		if err != nil {
+			t.Errorf("Parse(%q) вернул ошибку: %v", tt.in, err)
+			continue
+		}
+		if got := q.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, ожидалось %s", tt.in, got, tt.want)
+		}
+		// Канонический вид разбирается в тот же запрос
+		if again, err := Parse(q.String(), testNow); err != nil || again.String() != q.String() {
+			t.Errorf("Parse(%q) не сохраняется при повторном разборе: %v, %v", q, again, err)
+		}
+	}
+}
+
+// TestParse_Errors проверяет, что ошибки разбора указывают на столбец
+// с проблемой (в символах, а не байтах).
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		in     string
+		column int
+	}{
+		{"status:", 8},
+		{"(a or b", 1},
+		{"a or b)", 7},
+		{"a and", 6},
+		{"or a", 1},
+		{"()", 2},
+		{`title~"deploy`, 7},
+		{"a ! b", 3},
+		{"=high", 1},
+		{"colour:red", 1},
+		{"priority~high", 9},
+		{"priority>=срочно", 11},
+		{"задача due<завтра", 12},
+		{"tag:infra and is:sleeping", 18},
+		{"id>x", 4},
+		{"due<none", 5},
+		{`notes~"\x80"`, 7},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.in, testNow)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Parse(%q) = %v, ожидалась SyntaxError", tt.in, err)
+			continue
+		}
+		if syntaxErr.Column != tt.column {
+			t.Errorf("Parse(%q): столбец %d, ожидался %d (%v)", tt.in, syntaxErr.Column, tt.column, err)
+		}
+	}
+}
+
+// TestSyntaxError_Pointer проверяет стрелку под местом ошибки.
+func TestSyntaxError_Pointer(t *testing.T) {
+	_, err := Parse("задача priority>=срочно", testNow)
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("ожидалась SyntaxError, получено %v", err)
+	}
+	want := "задача priority>=срочно\n" + strings.Repeat(" ", 17) + "^"
+	if got := syntaxErr.Pointer("задача priority>=срочно"); got != want {
+		t.Errorf("Pointer =\n%s\nожидалось\n%s", got, want)
+	}
+}
+
+// FuzzParse проверяет, что разбор не паникует на произвольном вводе,
+// а канонический вид успешно разобранного запроса разбирается в тот же запрос.
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"deploy",
+		`status:pending and (tag:infra or priority>=high) and due<+7d and title~"deploy"`,
+		"not (a or b) c",
+		`title="a \"b\"" or notes~"é"`,
+		"is:overdue or is:ready",
+		"((((",
+		`"`,
+		"id>=3 parent:1 created<=2025-03-14",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, src string) {
+		q, err := Parse(src, testNow)
+		if err != nil {
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) вернул не SyntaxError: %v", src, err)
+			}
+			if syntaxErr.Column < 1 || syntaxErr.Column > len([]rune(src))+1 {
+				t.Fatalf("Parse(%q): столбец %d вне запроса", src, syntaxErr.Column)
+			}
+			return
+		}
+		again, err := Parse(q.String(), testNow)
+		if err != nil {
+			t.Fatalf("канонический вид %q запроса %q не разбирается: %v", q, src, err)
+		}
+		if again.String() != q.String() {
+			t.Fatalf("канонический вид меняется: %q → %q", q, again)
+		}
+	})
+}
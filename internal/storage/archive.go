@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// ArchiveEntry — завершённая задача в архиве.
+type ArchiveEntry struct {
+	Task       task.Task `json:"task"`        // задача в момент архивации
+	ArchivedAt time.Time `json:"archived_at"` // когда задача перенесена в архив
+}
+
+// Archive — архив завершённых задач: каталог с JSON-файлами по месяцам
+// (2025-03.json), куда задачи попадают по дате завершения. Задачи в архиве
+// не загружаются обычными командами, поэтому основной файл задач остаётся
+// маленьким. Запись в файлы атомарная и защищена межпроцессной блокировкой.
+type Archive struct {
+	Dir         string        // каталог архива
+	LockTimeout time.Duration // сколько ждать блокировку архива
+}
+
+// NewArchive — конструктор Archive с настройками по умолчанию.
+func NewArchive(dir string) *Archive {
+	return &Archive{Dir: dir, LockTimeout: DefaultLockTimeout}
+}
+
+// monthFile — приватная функция, возвращает имя файла архива,
+// в который попадает запись (по месяцу завершения задачи).
+func monthFile(e ArchiveEntry) string {
+	at := e.Task.ClosedAt()
+	if at.IsZero() {
+		at = e.ArchivedAt
+	}
+	return at.Local().Format("2006-01") + ".json"
+}
+
+// files — приватный метод, возвращает имена файлов архива по возрастанию
+// месяца. Отсутствующий каталог означает пустой архив.
+func (a *Archive) files() ([]string, error) {
+	dirEntries, err := os.ReadDir(a.Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, d := range dirEntries {
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".json") {
+			names = append(names, d.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// loadFile — приватный метод, читает записи одного файла архива.
+func (a *Archive) loadFile(name string) ([]ArchiveEntry, error) {
+	data, err := os.ReadFile(filepath.Join(a.Dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []ArchiveEntry{}, nil
+		}
+		return nil, err
+	}
+	if len(data) == 0 {
+		return []ArchiveEntry{}, nil
+	}
+
+	var entries []ArchiveEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// saveFile — приватный метод, записывает файл архива; пустой файл удаляется.
+func (a *Archive) saveFile(name string, entries []ArchiveEntry) error {
+	path := filepath.Join(a.Dir, name)
+	if len(entries) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0644)
+}
+
+// lock — приватный метод, захватывает блокировку архива.
+func (a *Archive) lock() (*fileLock, error) {
+	timeout := a.LockTimeout
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+	return acquireLock(a.Dir+".lock", timeout)
+}
+
+// List возвращает все задачи архива: по месяцам завершения,
+// внутри месяца — в порядке архивации.
+func (a *Archive) List() ([]ArchiveEntry, error) {
+	names, err := a.files()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []ArchiveEntry{}
+	for _, name := range names {
+		month, err := a.loadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, month...)
+	}
+	return entries, nil
+}
+
+// Add переносит задачи в архив с временем архивации archivedAt.
+func (a *Archive) Add(archivedAt time.Time, tasks ...task.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(a.Dir, 0755); err != nil {
+		return err
+	}
+	l, err := a.lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.release() }()
+
+	byFile := make(map[string][]ArchiveEntry)
+	var order []string
+	for _, t := range tasks {
+		e := ArchiveEntry{Task: t, ArchivedAt: archivedAt}
+		name := monthFile(e)
+		if _, ok := byFile[name]; !ok {
+			order = append(order, name)
+		}
+		byFile[name] = append(byFile[name], e)
+	}
+
+	for _, name := range order {
+		entries, err := a.loadFile(name)
+		if err != nil {
+			return err
+		}
+		if err := a.saveFile(name, append(entries, byFile[name]...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove убирает из архива записи, для которых match возвращает true,
+// и возвращает их.
+func (a *Archive) Remove(match func(ArchiveEntry) bool) ([]ArchiveEntry, error) {
+	l, err := a.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = l.release() }()
+
+	names, err := a.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []ArchiveEntry
+	for _, name := range names {
+		entries, err := a.loadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		kept := make([]ArchiveEntry, 0, len(entries))
+		for _, e := range entries {
+			if match(e) {
+				removed = append(removed, e)
+			} else {
+				kept = append(kept, e)
+			}
+		}
+		if len(kept) == len(entries) {
+			continue
+		}
+		if err := a.saveFile(name, kept); err != nil {
+			return nil, err
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// TestArchive_Months проверяет, что задачи раскладываются по файлам
+// месяцев завершения, а List возвращает их по порядку месяцев.
+func TestArchive_Months(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tasks.json.archive")
+	a := NewArchive(dir)
+
+	march := time.Date(2025, 3, 14, 12, 0, 0, 0, time.Local)
+	january := time.Date(2025, 1, 2, 12, 0, 0, 0, time.Local)
+	now := time.Now()
+	err := a.Add(now,
+		task.Task{ID: 1, Title: "Мартовская", Status: task.StatusDone, CompletedAt: march},
+		task.Task{ID: 2, Title: "Январская", Status: task.StatusCancelled, UpdatedAt: january},
+	)
+	if err != nil {
+		t.Fatalf("Add вернул ошибку: %v", err)
+	}
+
+	for _, name := range []string{"2025-01.json", "2025-03.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("ожидался файл архива %s: %v", name, err)
+		}
+	}
+
+	entries, err := a.List()
+	if err != nil {
+		t.Fatalf("List вернул ошибку: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Task.ID != 2 || entries[1].Task.ID != 1 {
+		t.Fatalf("ожидались задачи 2 и 1 по месяцам, получено %+v", entries)
+	}
+	if !entries[0].ArchivedAt.Equal(now) {
+		t.Errorf("время архивации %v, ожидалось %v", entries[0].ArchivedAt, now)
+	}
+
+	// Опустевший файл месяца удаляется.
+	removed, err := a.Remove(func(e ArchiveEntry) bool { return e.Task.ID == 2 })
+	if err != nil || len(removed) != 1 {
+		t.Fatalf("Remove = %+v, %v; ожидалась одна запись", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2025-01.json")); !os.IsNotExist(err) {
+		t.Errorf("пустой файл месяца должен быть удалён: %v", err)
+	}
+}
+
+// TestArchive_Journaled проверяет, что операция "archive" переносит задачи
+// в архив, а не в корзину, и что undo возвращает их в список.
+func TestArchive_Journaled(t *testing.T) {
+	s := newTestTrashStore(t)
+	s.Archive = NewArchive(filepath.Join(t.TempDir(), "tasks.json.archive"))
+	for _, title := range []string{"Выполнена", "В работе"} {
+		if _, err := s.AddTask(task.Task{Title: title}); err != nil {
+			t.Fatalf("AddTask вернул ошибку: %v", err)
+		}
+	}
+	if err := s.MarkTaskDone(1); err != nil {
+		t.Fatalf("MarkTaskDone вернул ошибку: %v", err)
+	}
+
+	err := s.ModifyAs("archive", func(tasks []task.Task) ([]task.Task, error) {
+		return tasks[1:], nil
+	})
+	if err != nil {
+		t.Fatalf("ModifyAs вернул ошибку: %v", err)
+	}
+	assertTitles(t, s, "В работе")
+
+	archived, err := s.Archive.List()
+	if err != nil || len(archived) != 1 || archived[0].Task.Title != "Выполнена" {
+		t.Fatalf("в архиве ожидалась задача 1, получено %+v, %v", archived, err)
+	}
+	if got := trashIDs(t, s); len(got) != 0 {
+		t.Errorf("архивация не должна класть задачи в корзину: %v", got)
+	}
+
+	if _, err := s.Undo(); err != nil {
+		t.Fatalf("Undo вернул ошибку: %v", err)
+	}
+	assertTitles(t, s, "Выполнена", "В работе")
+	if archived, _ := s.Archive.List(); len(archived) != 0 {
+		t.Errorf("после undo архив должен быть пуст: %+v", archived)
+	}
+
+	if _, err := s.Redo(); err != nil {
+		t.Fatalf("Redo вернул ошибку: %v", err)
+	}
+	if archived, _ := s.Archive.List(); len(archived) != 1 {
+		t.Errorf("после redo задача должна вернуться в архив: %+v", archived)
+	}
+}
+
+// TestArchive_WriteFailureKeepsTasks проверяет, что завершённые задачи
+// остаются в списке, если файл архива записать не удалось.
+func TestArchive_WriteFailureKeepsTasks(t *testing.T) {
+	s := newTestTrashStore(t)
+	if _, err := s.AddTask(task.Task{Title: "Выполнена"}); err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+	if err := s.MarkTaskDone(1); err != nil {
+		t.Fatalf("MarkTaskDone вернул ошибку: %v", err)
+	}
+
+	// Каталог архива на месте обычного файла: создать его нельзя
+	blocker := filepath.Join(t.TempDir(), "tasks.json.archive")
+	if err := os.WriteFile(blocker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	s.Archive = NewArchive(blocker)
+
+	err := s.ModifyAs("archive", func(tasks []task.Task) ([]task.Task, error) { return nil, nil })
+	if err == nil {
+		t.Fatal("ModifyAs должен вернуть ошибку записи архива")
+	}
+	assertTitles(t, s, "Выполнена")
+	if got := trashIDs(t, s); len(got) != 0 {
+		t.Errorf("задача не должна попасть и в корзину: %v", got)
+	}
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Точки подмены файловых операций. В рабочем коде не меняются,
+// тесты подставляют сюда сбои, чтобы имитировать прерванную запись.
+var (
+	writeData = func(f *os.File, data []byte) error {
+		_, err := f.Write(data)
+		return err
+	}
+	syncFile   = (*os.File).Sync
+	renameFile = os.Rename
+)
+
+// writeFileAtomic записывает data в файл path так, чтобы при сбое
+// (падение процесса, нехватка места, Ctrl-C) на диске оставалась
+// либо старая, либо новая версия файла целиком, но не обрезанная.
+//
+// Данные пишутся во временный файл в той же директории, сбрасываются
+// на диск через fsync и только затем атомарно переименовываются поверх
+// исходного файла. Права существующего файла сохраняются; для нового
+// файла используются права perm.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	if info, statErr := os.Stat(path); statErr == nil {
+		perm = info.Mode().Perm()
+
+		// rename позволяет заменить даже файл только для чтения,
+		// поэтому явно проверяем, что пользователь может в него писать.
+		f, openErr := os.OpenFile(path, os.O_WRONLY, 0)
+		if openErr != nil {
+			return openErr
+		}
+		_ = f.Close()
+	} else if !errors.Is(statErr, os.ErrNotExist) {
+		return statErr
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// При любой ошибке убираем временный файл, исходный остаётся нетронутым.
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if err = writeData(tmp, data); err != nil {
+		return err
+	}
+	if err = syncFile(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = renameFile(tmpPath, path); err != nil {
+		return err
+	}
+
+	// Сбрасываем на диск запись директории, иначе после отключения питания
+	// переименование может "откатиться". В Windows директорию открыть нельзя.
+	if runtime.GOOS != "windows" {
+		if d, dirErr := os.Open(dir); dirErr == nil {
+			_ = d.Sync()
+			_ = d.Close()
+		}
+	}
+	return nil
+}
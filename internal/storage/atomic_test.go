@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// newStoreWithTasks создаёт JSONStore во временной директории с n задачами.
+func newStoreWithTasks(t *testing.T, n int) *JSONStore {
+	t.Helper()
+
+	store := NewJSONStore(filepath.Join(t.TempDir(), "tasks.json"))
+	tasks := make([]task.Task, 0, n)
+	for i := 1; i <= n; i++ {
+		tasks = append(tasks, task.Task{ID: i, Title: fmt.Sprintf("Задача %d", i), CreatedAt: time.Now()})
+	}
+	if err := store.OverwriteTasks(tasks); err != nil {
+		t.Fatalf("OverwriteTasks вернул ошибку: %v", err)
+	}
+	return store
+}
+
+// assertNoTempFiles проверяет, что в директории не осталось временных файлов.
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("не удалось прочитать директорию: %v", err)
+	}
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Errorf("остался временный файл %s", e.Name())
+		}
+	}
+}
+
+// TestWriteFileAtomic_Interrupted имитирует сбои на каждом шаге записи
+// и проверяет, что исходный файл всегда остаётся целым.
+func TestWriteFileAtomic_Interrupted(t *testing.T) {
+	errDiskFull := errors.New("no space left on device")
+
+	tests := []struct {
+		name  string
+		setup func()
+	}{
+		{"обрыв посередине записи", func() {
+			writeData = func(f *os.File, data []byte) error {
+				_, _ = f.Write(data[:len(data)/2])
+				return errDiskFull
+			}
+		}},
+		{"ошибка fsync", func() {
+			syncFile = func(*os.File) error { return errDiskFull }
+		}},
+		{"сбой перед переименованием", func() {
+			renameFile = func(string, string) error { return errDiskFull }
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newStoreWithTasks(t, 3)
+			before, err := os.ReadFile(store.FilePath)
+			if err != nil {
+				t.Fatalf("не удалось прочитать файл: %v", err)
+			}
+
+			origWrite, origSync, origRename := writeData, syncFile, renameFile
+			defer func() { writeData, syncFile, renameFile = origWrite, origSync, origRename }()
+			tt.setup()
+
+			if _, err := store.AddTask(task.Task{Title: "Не сохранится", CreatedAt: time.Now()}); !errors.Is(err, errDiskFull) {
+				t.Fatalf("ожидалась ошибка записи, получено %v", err)
+			}
+
+			after, err := os.ReadFile(store.FilePath)
+			if err != nil {
+				t.Fatalf("не удалось прочитать файл: %v", err)
+			}
+			if string(after) != string(before) {
+				t.Errorf("исходный файл изменился после прерванной записи")
+			}
+
+			tasks, err := store.ListTasks()
+			if err != nil || len(tasks) != 3 {
+				t.Errorf("ожидалось 3 задачи без ошибки, получено %d, %v", len(tasks), err)
+			}
+			assertNoTempFiles(t, filepath.Dir(store.FilePath))
+		})
+	}
+}
+
+// TestWriteFileAtomic_PreservesPermissions проверяет, что перезапись
+// не меняет права существующего файла.
+func TestWriteFileAtomic_PreservesPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("права доступа Unix не поддерживаются в Windows")
+	}
+
+	store := newStoreWithTasks(t, 1)
+	if err := os.Chmod(store.FilePath, 0600); err != nil {
+		t.Fatalf("не удалось изменить права файла: %v", err)
+	}
+
+	if _, err := store.AddTask(task.Task{Title: "Ещё одна", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+
+	info, err := os.Stat(store.FilePath)
+	if err != nil {
+		t.Fatalf("не удалось получить информацию о файле: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("ожидались права 0600, получено %o", info.Mode().Perm())
+	}
+}
+
+// TestJSONStore_SurvivesKilledWriter запускает отдельный процесс, который
+// непрерывно перезаписывает файл задач, и убивает его в случайный момент.
+// После каждого убийства файл должен читаться целиком.
+func TestJSONStore_SurvivesKilledWriter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("пропускаем многопроцессный тест в режиме -short")
+	}
+
+	const taskCount = 500
+	store := newStoreWithTasks(t, taskCount)
+
+	for round := 0; round < 5; round++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+		cmd.Env = append(os.Environ(), "TODO_STORAGE_HELPER=writer", "TODO_STORAGE_FILE="+store.FilePath)
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("не удалось запустить процесс: %v", err)
+		}
+
+		time.Sleep(time.Duration(20+round*15) * time.Millisecond)
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+
+		tasks, err := store.ListTasks()
+		if err != nil {
+			t.Fatalf("раунд %d: файл повреждён после убийства процесса: %v", round, err)
+		}
+		if len(tasks) != taskCount {
+			t.Fatalf("раунд %d: ожидалось %d задач, получено %d", round, taskCount, len(tasks))
+		}
+	}
+}
+
+// TestHelperProcess не является настоящим тестом: это тело дочернего
+// процесса для многопроцессных тестов. Без переменной окружения
+// TODO_STORAGE_HELPER он сразу завершается.
+func TestHelperProcess(t *testing.T) {
+	path := os.Getenv("TODO_STORAGE_FILE")
+
+	switch os.Getenv("TODO_STORAGE_HELPER") {
+	case "writer":
+		// Бесконечно перезаписываем файл тем же числом задач с новыми названиями.
+		store := NewJSONStore(path)
+		for i := 0; ; i++ {
+			_ = store.Modify(func(tasks []task.Task) ([]task.Task, error) {
+				for j := range tasks {
+					tasks[j].Title = fmt.Sprintf("Задача %d, версия %d", tasks[j].ID, i)
+				}
+				return tasks, nil
+			})
+		}
+	case "adder":
+		// Добавляем заданное число задач и выходим.
+		var count int
+		_, _ = fmt.Sscan(os.Getenv("TODO_STORAGE_COUNT"), &count)
+		store := NewJSONStore(path)
+		for i := 0; i < count; i++ {
+			if _, err := store.AddTask(task.Task{Title: fmt.Sprintf("pid %d #%d", os.Getpid(), i), CreatedAt: time.Now()}); err != nil {
+				fmt.Fprintln(os.Stderr, "AddTask:", err)
+				os.Exit(1)
+			}
+		}
+		os.Exit(0)
+	}
+}
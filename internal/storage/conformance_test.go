@@ -0,0 +1,42 @@
+package storage_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/storage/storagetest"
+)
+
+// Все реализации Storage прогоняются через один и тот же набор проверок,
+// чтобы гарантировать одинаковую семантику ID, ошибок и порядка задач.
+
+func TestJSONStoreConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		return storage.NewJSONStore(filepath.Join(t.TempDir(), "tasks.json"))
+	})
+}
+
+func TestSQLiteStoreConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		s, err := storage.NewSQLiteStore(filepath.Join(t.TempDir(), "tasks.db"))
+		if err != nil {
+			t.Fatalf("NewSQLiteStore вернул ошибку: %v", err)
+		}
+		t.Cleanup(func() { _ = s.Close() })
+		return s
+	})
+}
+
+func TestMemoryStoreConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		return storage.NewMemoryStore()
+	})
+}
+
+func TestJournaledStoreConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		journal := storage.NewJournal(filepath.Join(t.TempDir(), "tasks.journal"))
+		return storage.NewJournaledStore(storage.NewMemoryStore(), journal)
+	})
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// Event — запись журнала аудита об изменении одной задачи.
+// Before отсутствует у созданной задачи, After — у удалённой.
+type Event struct {
+	Time   time.Time  `json:"time"`             // когда произошло изменение
+	User   string     `json:"user"`             // пользователь ОС, выполнивший операцию
+	Op     string     `json:"op"`               // add, update, done, delete, clear, undo, redo...
+	TaskID int        `json:"task_id"`          // ID изменённой задачи
+	Before *task.Task `json:"before,omitempty"` // состояние до изменения
+	After  *task.Task `json:"after,omitempty"`  // состояние после изменения
+}
+
+// EventLog — журнал аудита: файл, в который дописывается по одной
+// JSON-записи (строке) на каждое изменение задачи. В отличие от Journal,
+// записи никогда не удаляются и не переписываются.
+type EventLog struct {
+	Path        string        // путь к файлу журнала аудита
+	User        string        // имя пользователя, подставляемое в события
+	LockTimeout time.Duration // сколько ждать блокировку файла
+}
+
+// NewEventLog — конструктор EventLog. Пользователь определяется по ОС.
+func NewEventLog(path string) *EventLog {
+	return &EventLog{Path: path, User: currentUser(), LockTimeout: DefaultLockTimeout}
+}
+
+// currentUser — приватная функция, возвращает имя текущего пользователя ОС.
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	for _, env := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(env); name != "" {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// Append дописывает события в конец журнала. Время и пользователь
+// заполняются автоматически, если не заданы.
+func (l *EventLog) Append(events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	timeout := l.LockTimeout
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+	lock, err := acquireLock(l.Path+".lock", timeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.release() }()
+
+	f, err := os.OpenFile(l.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, e := range events {
+		if e.Time.IsZero() {
+			e.Time = now
+		}
+		if e.User == "" {
+			e.User = l.User
+		}
+		if err := enc.Encode(e); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read возвращает события, для которых match возвращает true
+// (все события, если match равен nil), в порядке записи.
+// Недописанная последняя строка (сбой во время записи) пропускается.
+func (l *EventLog) Read(match func(Event) bool) ([]Event, error) {
+	f, err := os.Open(l.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Event{}, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	events := []Event{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024) // снимки задач могут быть длинными
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if match == nil || match(e) {
+			events = append(events, e)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// operationEvents — приватная функция, превращает операцию журнала
+// в события аудита: по одному на каждую затронутую задачу.
+func operationEvents(kind string, ids []int, before, after []task.Task) []Event {
+	b, a := tasksByID(before), tasksByID(after)
+
+	events := make([]Event, 0, len(ids))
+	for _, id := range ids {
+		e := Event{Op: kind, TaskID: id}
+		if t, ok := b[id]; ok {
+			e.Before = &t
+		}
+		if t, ok := a[id]; ok {
+			e.After = &t
+		}
+		events = append(events, e)
+	}
+	return events
+}
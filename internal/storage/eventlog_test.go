@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// TestEventLog_RecordsEveryMutation проверяет, что каждое изменение,
+// включая undo и redo, попадает в журнал аудита со снимками до и после.
+func TestEventLog_RecordsEveryMutation(t *testing.T) {
+	dir := t.TempDir()
+	s := NewJournaledStore(NewMemoryStore(), NewJournal(filepath.Join(dir, "tasks.journal")))
+	s.Events = NewEventLog(filepath.Join(dir, "tasks.history"))
+	s.Events.User = "alice"
+
+	if _, err := s.AddTask(task.Task{Title: "Отчёт", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+	if err := s.UpdateTask(task.Task{ID: 1, Title: "Квартальный отчёт"}); err != nil {
+		t.Fatalf("UpdateTask вернул ошибку: %v", err)
+	}
+	if err := s.MarkTaskDone(1); err != nil {
+		t.Fatalf("MarkTaskDone вернул ошибку: %v", err)
+	}
+	if _, err := s.Undo(); err != nil {
+		t.Fatalf("Undo вернул ошибку: %v", err)
+	}
+	if _, err := s.Redo(); err != nil {
+		t.Fatalf("Redo вернул ошибку: %v", err)
+	}
+	if err := s.DeleteTask(1); err != nil {
+		t.Fatalf("DeleteTask вернул ошибку: %v", err)
+	}
+
+	events, err := s.Events.Read(nil)
+	if err != nil {
+		t.Fatalf("Read вернул ошибку: %v", err)
+	}
+	wantOps := []string{"add", "update", "done", "undo", "redo", "delete"}
+	if len(events) != len(wantOps) {
+		t.Fatalf("ожидалось %d событий, получено %d: %+v", len(wantOps), len(events), events)
+	}
+	for i, op := range wantOps {
+		e := events[i]
+		if e.Op != op || e.TaskID != 1 || e.User != "alice" || e.Time.IsZero() {
+			t.Errorf("событие %d: ожидалась операция %s над задачей 1 от alice, получено %+v", i, op, e)
+		}
+	}
+
+	if events[0].Before != nil || events[0].After == nil || events[0].After.Title != "Отчёт" {
+		t.Errorf("add должен содержать только состояние после: %+v", events[0])
+	}
+	if events[1].Before.Title != "Отчёт" || events[1].After.Title != "Квартальный отчёт" {
+		t.Errorf("update должен содержать старое и новое название: %+v", events[1])
+	}
+	if events[3].Before == nil || events[3].Before.Status != task.StatusDone || events[3].After.Status == task.StatusDone {
+		t.Errorf("undo должен вернуть задачу в невыполненное состояние: %+v", events[3])
+	}
+	if events[5].Before == nil || events[5].After != nil {
+		t.Errorf("delete должен содержать только состояние до: %+v", events[5])
+	}
+}
+
+// TestEventLog_SkipsTruncatedLine проверяет, что недописанная строка
+// (сбой во время записи) не мешает читать остальные события.
+func TestEventLog_SkipsTruncatedLine(t *testing.T) {
+	log := NewEventLog(filepath.Join(t.TempDir(), "tasks.history"))
+	if err := log.Append(Event{Op: "add", TaskID: 1}, Event{Op: "done", TaskID: 1}); err != nil {
+		t.Fatalf("Append вернул ошибку: %v", err)
+	}
+
+	f, err := os.OpenFile(log.Path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("не удалось открыть журнал: %v", err)
+	}
+	_, _ = f.WriteString(`{"time":"2025-01-01T00:00:00Z","op":"upd`)
+	_ = f.Close()
+
+	events, err := log.Read(func(e Event) bool { return e.Op == "done" })
+	if err != nil {
+		t.Fatalf("Read вернул ошибку: %v", err)
+	}
+	if len(events) != 1 || events[0].User == "" {
+		t.Errorf("ожидалось одно событие done с пользователем, получено %+v", events)
+	}
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultLockTimeout — сколько JSONStore ждёт блокировку файла задач,
+// прежде чем вернуть ошибку.
+const DefaultLockTimeout = 5 * time.Second
+
+// lockRetryInterval — пауза между попытками захватить занятую блокировку.
+const lockRetryInterval = 10 * time.Millisecond
+
+// ErrLockTimeout — общая ошибка "не удалось дождаться блокировки".
+// Проверяется через errors.Is.
+var ErrLockTimeout = errors.New("файл задач занят другим процессом")
+
+// LockTimeoutError — ошибка ожидания блокировки конкретного файла.
+type LockTimeoutError struct {
+	Path    string        // путь к файлу блокировки
+	Timeout time.Duration // сколько ждали
+}
+
+// Error возвращает текст ошибки для пользователя.
+func (e *LockTimeoutError) Error() string {
+	return fmt.Sprintf("не удалось захватить блокировку %s за %s: %v", e.Path, e.Timeout, ErrLockTimeout)
+}
+
+// Is позволяет сравнивать LockTimeoutError с ErrLockTimeout через errors.Is.
+func (e *LockTimeoutError) Is(target error) bool {
+	return target == ErrLockTimeout
+}
+
+// fileLock — захваченная межпроцессная (advisory) блокировка файла.
+type fileLock struct {
+	f *os.File
+}
+
+// acquireLock захватывает эксклюзивную блокировку файла path, создавая
+// его при необходимости. Если блокировку держит другой процесс, попытки
+// повторяются до истечения timeout, после чего возвращается LockTimeoutError.
+func acquireLock(path string, timeout time.Duration) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := tryLockFile(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		if ok {
+			return &fileLock{f: f}, nil
+		}
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			return nil, &LockTimeoutError{Path: path, Timeout: timeout}
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// release снимает блокировку и закрывает файл.
+// Файл блокировки не удаляется: удаление открыло бы гонку между процессами.
+func (l *fileLock) release() error {
+	unlockErr := unlockFile(l.f)
+	closeErr := l.f.Close()
+	if unlockErr != nil {
+		return unlockErr
+	}
+	return closeErr
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package storage
+
+import "os"
+
+// tryLockFile на платформах без flock блокировку не выполняет:
+// защита остаётся только внутри процесса (мьютекс JSONStore).
+func tryLockFile(*os.File) (bool, error) {
+	return true, nil
+}
+
+// unlockFile на платформах без flock ничего не делает.
+func unlockFile(*os.File) error {
+	return nil
+}
@@ -0,0 +1,94 @@
+//go:build unix
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// TestJSONStore_LockTimeout проверяет, что при занятой блокировке
+// операция завершается понятной ошибкой после таймаута.
+func TestJSONStore_LockTimeout(t *testing.T) {
+	store := NewJSONStore(filepath.Join(t.TempDir(), "tasks.json"))
+	store.LockTimeout = 50 * time.Millisecond
+
+	// Держим блокировку "другим процессом": flock привязан к открытому
+	// файлу, поэтому второе открытие конфликтует и внутри одного процесса.
+	held, err := acquireLock(store.FilePath+".lock", time.Second)
+	if err != nil {
+		t.Fatalf("не удалось захватить блокировку: %v", err)
+	}
+
+	start := time.Now()
+	_, err = store.AddTask(task.Task{Title: "Заблокирована", CreatedAt: time.Now()})
+	if !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("ожидалась ошибка ErrLockTimeout, получено %v", err)
+	}
+	if waited := time.Since(start); waited < store.LockTimeout {
+		t.Errorf("ожидание блокировки оказалось короче таймаута: %s", waited)
+	}
+
+	// После освобождения блокировки операция проходит.
+	if err := held.release(); err != nil {
+		t.Fatalf("не удалось снять блокировку: %v", err)
+	}
+	if _, err := store.AddTask(task.Task{Title: "Разблокирована", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddTask после снятия блокировки вернул ошибку: %v", err)
+	}
+}
+
+// TestJSONStore_ConcurrentProcesses запускает несколько процессов,
+// параллельно добавляющих задачи, и проверяет, что ни одна задача
+// не потерялась, а ID не повторяются.
+func TestJSONStore_ConcurrentProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("пропускаем многопроцессный тест в режиме -short")
+	}
+
+	const (
+		processes = 8
+		perProc   = 25
+	)
+	path := filepath.Join(t.TempDir(), "tasks.json")
+
+	cmds := make([]*exec.Cmd, 0, processes)
+	for p := 0; p < processes; p++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+		cmd.Env = append(os.Environ(),
+			"TODO_STORAGE_HELPER=adder",
+			"TODO_STORAGE_FILE="+path,
+			fmt.Sprintf("TODO_STORAGE_COUNT=%d", perProc),
+		)
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("не удалось запустить процесс: %v", err)
+		}
+		cmds = append(cmds, cmd)
+	}
+	for _, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("дочерний процесс завершился с ошибкой: %v", err)
+		}
+	}
+
+	tasks, err := NewJSONStore(path).ListTasks()
+	if err != nil {
+		t.Fatalf("ListTasks вернул ошибку: %v", err)
+	}
+	if len(tasks) != processes*perProc {
+		t.Fatalf("ожидалось %d задач, получено %d", processes*perProc, len(tasks))
+	}
+	for i, tsk := range tasks {
+		if tsk.ID != i+1 {
+			t.Fatalf("ожидался ID %d на позиции %d, получено %d", i+1, i, tsk.ID)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// DefaultMaxOperations — сколько последних операций хранит журнал.
+const DefaultMaxOperations = 100
+
+// Operation — запись журнала об одной изменяющей операции.
+// Before и After содержат снимки только затронутых задач: задача,
+// которой не было до операции (add), отсутствует в Before, а удалённая
+// задача (delete, clear) — в After.
+type Operation struct {
+	ID      int         `json:"id"`               // порядковый номер операции
+	Kind    string      `json:"kind"`             // add, update, done, delete, clear, complete-all...
+	Time    time.Time   `json:"time"`             // когда операция выполнена
+	TaskIDs []int       `json:"task_ids"`         // ID затронутых задач
+	Before  []task.Task `json:"before"`           // затронутые задачи до операции
+	After   []task.Task `json:"after"`            // затронутые задачи после операции
+	Undone  bool        `json:"undone,omitempty"` // операция отменена и доступна для redo
+}
+
+// Journal — журнал операций, хранящийся в отдельном JSON-файле.
+// Запись в файл атомарная и защищена межпроцессной блокировкой,
+// как и файл задач JSONStore.
+type Journal struct {
+	Path          string        // путь к файлу журнала
+	MaxOperations int           // сколько последних операций хранить
+	LockTimeout   time.Duration // сколько ждать блокировку журнала
+}
+
+// NewJournal — конструктор Journal с настройками по умолчанию.
+func NewJournal(path string) *Journal {
+	return &Journal{Path: path, MaxOperations: DefaultMaxOperations, LockTimeout: DefaultLockTimeout}
+}
+
+// load — приватный метод, читает операции из файла журнала.
+// Отсутствующий или пустой файл означает пустой журнал.
+func (j *Journal) load() ([]Operation, error) {
+	data, err := os.ReadFile(j.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Operation{}, nil
+		}
+		return nil, err
+	}
+	if len(data) == 0 {
+		return []Operation{}, nil
+	}
+
+	var ops []Operation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+// update — приватный метод, выполняет цикл "чтение → fn → запись"
+// под блокировкой файла журнала.
+func (j *Journal) update(fn func(ops []Operation) ([]Operation, error)) error {
+	timeout := j.LockTimeout
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+	l, err := acquireLock(j.Path+".lock", timeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.release() }()
+
+	ops, err := j.load()
+	if err != nil {
+		return err
+	}
+	ops, err = fn(ops)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(ops, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(j.Path, data, 0644)
+}
+
+// Record добавляет операцию в журнал и возвращает её с назначенным ID.
+// Отменённые операции удаляются: после нового изменения redo невозможен.
+func (j *Journal) Record(op Operation) (Operation, error) {
+	err := j.update(func(ops []Operation) ([]Operation, error) {
+		lastID := 0
+		if len(ops) > 0 {
+			lastID = ops[len(ops)-1].ID
+		}
+
+		applied := make([]Operation, 0, len(ops)+1)
+		for _, o := range ops {
+			if !o.Undone {
+				applied = append(applied, o)
+			}
+		}
+
+		op.ID = lastID + 1
+		op.Undone = false
+		applied = append(applied, op)
+
+		if limit := j.MaxOperations; limit > 0 && len(applied) > limit {
+			applied = applied[len(applied)-limit:]
+		}
+		return applied, nil
+	})
+	if err != nil {
+		return Operation{}, err
+	}
+	return op, nil
+}
+
+// List возвращает последние n операций (все, если n <= 0)
+// в порядке от старых к новым, включая отменённые.
+func (j *Journal) List(n int) ([]Operation, error) {
+	ops, err := j.load()
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 && len(ops) > n {
+		ops = ops[len(ops)-n:]
+	}
+	return ops, nil
+}
@@ -0,0 +1,323 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// newTestJournaledStore создаёт JournaledStore поверх MemoryStore
+// с журналом во временной директории теста.
+func newTestJournaledStore(t *testing.T) *JournaledStore {
+	t.Helper()
+	return NewJournaledStore(NewMemoryStore(), NewJournal(filepath.Join(t.TempDir(), "tasks.journal")))
+}
+
+// titles возвращает названия задач хранилища по порядку.
+func titles(t *testing.T, s Storage) []string {
+	t.Helper()
+
+	tasks, err := s.ListTasks()
+	if err != nil {
+		t.Fatalf("ListTasks вернул ошибку: %v", err)
+	}
+	result := make([]string, 0, len(tasks))
+	for _, tsk := range tasks {
+		result = append(result, tsk.Title)
+	}
+	return result
+}
+
+// assertTitles сравнивает названия задач с ожидаемыми.
+func assertTitles(t *testing.T, s Storage, want ...string) {
+	t.Helper()
+
+	got := titles(t, s)
+	if len(got) != len(want) {
+		t.Fatalf("ожидались задачи %v, получено %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ожидались задачи %v, получено %v", want, got)
+		}
+	}
+}
+
+// TestJournaledStore_UndoRedo проверяет отмену и повтор всех видов операций.
+func TestJournaledStore_UndoRedo(t *testing.T) {
+	s := newTestJournaledStore(t)
+
+	for _, title := range []string{"Первая", "Вторая", "Третья"} {
+		if _, err := s.AddTask(task.Task{Title: title, CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("AddTask вернул ошибку: %v", err)
+		}
+	}
+	if err := s.UpdateTask(task.Task{ID: 1, Title: "Первая (изменена)"}); err != nil {
+		t.Fatalf("UpdateTask вернул ошибку: %v", err)
+	}
+	if err := s.DeleteTask(2); err != nil {
+		t.Fatalf("DeleteTask вернул ошибку: %v", err)
+	}
+	assertTitles(t, s, "Первая (изменена)", "Третья")
+
+	// Отмена удаления возвращает задачу на место с тем же ID.
+	op, err := s.Undo()
+	if err != nil {
+		t.Fatalf("Undo вернул ошибку: %v", err)
+	}
+	if op.Kind != "delete" || len(op.TaskIDs) != 1 || op.TaskIDs[0] != 2 {
+		t.Errorf("отменена не та операция: %+v", op)
+	}
+	assertTitles(t, s, "Первая (изменена)", "Вторая", "Третья")
+
+	// Отмена изменения возвращает старое название.
+	if _, err := s.Undo(); err != nil {
+		t.Fatalf("Undo вернул ошибку: %v", err)
+	}
+	assertTitles(t, s, "Первая", "Вторая", "Третья")
+
+	// Redo повторяет отменённые операции в исходном порядке.
+	if op, err := s.Redo(); err != nil || op.Kind != "update" {
+		t.Fatalf("ожидался повтор update, получено %+v, %v", op, err)
+	}
+	if op, err := s.Redo(); err != nil || op.Kind != "delete" {
+		t.Fatalf("ожидался повтор delete, получено %+v, %v", op, err)
+	}
+	assertTitles(t, s, "Первая (изменена)", "Третья")
+
+	if _, err := s.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("ожидалась ошибка ErrNothingToRedo, получено %v", err)
+	}
+}
+
+// TestJournaledStore_UndoBulk проверяет отмену массовой операции.
+func TestJournaledStore_UndoBulk(t *testing.T) {
+	s := newTestJournaledStore(t)
+
+	for _, tsk := range []task.Task{
+		{Title: "Открытая", CreatedAt: time.Now()},
+		{Title: "Готовая", Status: task.StatusDone, CreatedAt: time.Now()},
+	} {
+		if _, err := s.AddTask(tsk); err != nil {
+			t.Fatalf("AddTask вернул ошибку: %v", err)
+		}
+	}
+
+	err := s.ModifyAs("clear", func(tasks []task.Task) ([]task.Task, error) {
+		return tasks[:1], nil
+	})
+	if err != nil {
+		t.Fatalf("ModifyAs вернул ошибку: %v", err)
+	}
+	assertTitles(t, s, "Открытая")
+
+	op, err := s.Undo()
+	if err != nil {
+		t.Fatalf("Undo вернул ошибку: %v", err)
+	}
+	if op.Kind != "clear" {
+		t.Errorf("ожидалась операция clear, получено %q", op.Kind)
+	}
+	assertTitles(t, s, "Открытая", "Готовая")
+}
+
+// TestJournaledStore_UndoRecurringDone проверяет, что отмена выполнения
+// повторяющейся задачи убирает и созданное повторение.
+func TestJournaledStore_UndoRecurringDone(t *testing.T) {
+	s := newTestJournaledStore(t)
+
+	recur, _ := task.ParseRecurrence("weekly")
+	if _, err := s.AddTask(task.Task{Title: "Отчёт", CreatedAt: time.Now(), Recur: recur}); err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+	original, _ := s.GetTask(1)
+	if err := s.MarkTaskDone(1); err != nil {
+		t.Fatalf("MarkTaskDone вернул ошибку: %v", err)
+	}
+	assertTitles(t, s, "Отчёт", "Отчёт")
+
+	if op, err := s.Undo(); err != nil || op.Kind != "done" || len(op.TaskIDs) != 2 {
+		t.Fatalf("ожидалась отмена done для двух задач, получено %+v, %v", op, err)
+	}
+	assertTitles(t, s, "Отчёт")
+	if got, _ := s.GetTask(1); got.Status == task.StatusDone || got.Recur != recur ||
+		!got.CompletedAt.IsZero() || !got.UpdatedAt.Equal(original.UpdatedAt) {
+		t.Errorf("задача должна вернуться в исходное состояние: %+v", got)
+	}
+
+	// Отметки времени восстанавливаются точно, поэтому повтор не конфликтует
+	if _, err := s.Redo(); err != nil {
+		t.Fatalf("Redo вернул ошибку: %v", err)
+	}
+	if got, _ := s.GetTask(1); got.Status != task.StatusDone || got.CompletedAt.IsZero() {
+		t.Errorf("повтор должен снова выполнить задачу: %+v", got)
+	}
+}
+
+// TestJournaledStore_NewOperationDropsRedo проверяет, что новое изменение
+// после undo делает повтор невозможным.
+func TestJournaledStore_NewOperationDropsRedo(t *testing.T) {
+	s := newTestJournaledStore(t)
+
+	if _, err := s.AddTask(task.Task{Title: "Первая", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+	if _, err := s.Undo(); err != nil {
+		t.Fatalf("Undo вернул ошибку: %v", err)
+	}
+	if _, err := s.AddTask(task.Task{Title: "Другая", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+
+	if _, err := s.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("ожидалась ошибка ErrNothingToRedo, получено %v", err)
+	}
+	ops, err := s.Journal.List(0)
+	if err != nil {
+		t.Fatalf("List вернул ошибку: %v", err)
+	}
+	if len(ops) != 1 || ops[0].ID != 2 {
+		t.Errorf("в журнале должна остаться одна операция #2, получено %+v", ops)
+	}
+}
+
+// TestJournaledStore_Conflict проверяет, что undo не затирает изменения,
+// сделанные в обход журнала.
+func TestJournaledStore_Conflict(t *testing.T) {
+	s := newTestJournaledStore(t)
+
+	if _, err := s.AddTask(task.Task{Title: "Первая", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+	// Меняем задачу напрямую во внутреннем хранилище.
+	if err := s.Storage.MarkTaskDone(1); err != nil {
+		t.Fatalf("MarkTaskDone вернул ошибку: %v", err)
+	}
+
+	var conflict *ConflictError
+	if _, err := s.Undo(); !errors.As(err, &conflict) || conflict.TaskID != 1 {
+		t.Fatalf("ожидалась ConflictError для задачи 1, получено %v", err)
+	}
+	assertTitles(t, s, "Первая")
+}
+
+// racingStore — хранилище, в котором между чтением задачи и её записью
+// успевает вклиниться другой процесс: GetTask возвращает устаревшее
+// состояние, а Modify видит актуальное.
+type racingStore struct {
+	Storage
+	stale task.Task // что вернёт GetTask
+}
+
+func (s racingStore) GetTask(int) (task.Task, error) { return s.stale, nil }
+
+// TestJournaledStore_BeforeUnderLock проверяет, что update и delete берут
+// состояние "до" под той же блокировкой, что и запись, а не отдельным
+// чтением: иначе undo после параллельного изменения восстановил бы
+// устаревшие данные.
+func TestJournaledStore_BeforeUnderLock(t *testing.T) {
+	inner := NewMemoryStore()
+	if _, err := inner.AddTask(task.Task{Title: "Актуальная"}); err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+	stale := task.Task{ID: 1, Title: "Устаревшая"}
+	s := NewJournaledStore(racingStore{Storage: inner, stale: stale}, NewJournal(filepath.Join(t.TempDir(), "tasks.journal")))
+
+	for _, change := range []func() error{
+		func() error { return s.UpdateTask(task.Task{ID: 1, Title: "Новая"}) },
+		func() error { return s.DeleteTask(1) },
+	} {
+		want := titles(t, inner)
+		if err := change(); err != nil {
+			t.Fatalf("изменение вернуло ошибку: %v", err)
+		}
+		if _, err := s.Undo(); err != nil {
+			t.Fatalf("Undo вернул ошибку: %v", err)
+		}
+		assertTitles(t, inner, want...)
+	}
+
+	var notFound *NotFoundError
+	if err := s.DeleteTask(42); !errors.As(err, &notFound) {
+		t.Errorf("ожидалась NotFoundError, получено %v", err)
+	}
+}
+
+// TestJournal_Limit проверяет, что журнал хранит не больше MaxOperations записей.
+func TestJournal_Limit(t *testing.T) {
+	s := newTestJournaledStore(t)
+	s.Journal.MaxOperations = 3
+
+	for i := 0; i < 5; i++ {
+		if _, err := s.AddTask(task.Task{Title: "Задача", CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("AddTask вернул ошибку: %v", err)
+		}
+	}
+
+	ops, err := s.Journal.List(0)
+	if err != nil {
+		t.Fatalf("List вернул ошибку: %v", err)
+	}
+	if len(ops) != 3 || ops[0].ID != 3 || ops[2].ID != 5 {
+		t.Errorf("ожидались операции #3..#5, получено %+v", ops)
+	}
+
+	if last, err := s.Journal.List(1); err != nil || len(last) != 1 || last[0].ID != 5 {
+		t.Errorf("List(1) должен вернуть последнюю операцию, получено %+v, %v", last, err)
+	}
+}
+
+// TestJournaledStore_NoChangesNotRecorded проверяет, что операция без
+// изменений (например, complete-all над выполненными задачами) не пишется в журнал.
+func TestJournaledStore_NoChangesNotRecorded(t *testing.T) {
+	s := newTestJournaledStore(t)
+
+	if err := s.ModifyAs("complete-all", func(tasks []task.Task) ([]task.Task, error) {
+		return tasks, nil
+	}); err != nil {
+		t.Fatalf("ModifyAs вернул ошибку: %v", err)
+	}
+
+	if _, err := s.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("ожидалась ошибка ErrNothingToUndo, получено %v", err)
+	}
+}
+
+// TestJournaledStore_OnChange проверяет, что OnChange получает изменившие
+// задачи операции, включая undo в обратном направлении, и не получает пустые.
+func TestJournaledStore_OnChange(t *testing.T) {
+	s := newTestJournaledStore(t)
+	var ops []Operation
+	s.OnChange = func(op Operation) { ops = append(ops, op) }
+
+	if _, err := s.AddTask(task.Task{Title: "Первая", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+	if err := s.ModifyAs("complete-all", func(tasks []task.Task) ([]task.Task, error) {
+		return tasks, nil
+	}); err != nil {
+		t.Fatalf("ModifyAs вернул ошибку: %v", err)
+	}
+	if err := s.DeleteTask(1); err != nil {
+		t.Fatalf("DeleteTask вернул ошибку: %v", err)
+	}
+	if _, err := s.Undo(); err != nil {
+		t.Fatalf("Undo вернул ошибку: %v", err)
+	}
+
+	var kinds []string
+	for _, op := range ops {
+		kinds = append(kinds, op.Kind)
+	}
+	if strings.Join(kinds, ",") != "add,delete,undo" {
+		t.Fatalf("OnChange получил операции %v, ожидались add, delete, undo", kinds)
+	}
+	undo := ops[2]
+	if len(undo.Before) != 0 || len(undo.After) != 1 || undo.After[0].Title != "Первая" {
+		t.Errorf("undo удаления должен вернуть задачу: %+v", undo)
+	}
+}
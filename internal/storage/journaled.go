@@ -0,0 +1,503 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// ErrNothingToUndo — в журнале нет операций, которые можно отменить.
+var ErrNothingToUndo = errors.New("нет операций для отмены")
+
+// ErrNothingToRedo — в журнале нет отменённых операций для повтора.
+var ErrNothingToRedo = errors.New("нет операций для повтора")
+
+// ConflictError — операцию нельзя отменить или повторить, потому что
+// затронутая задача с тех пор изменилась в обход журнала.
+type ConflictError struct {
+	OperationID int // номер операции в журнале
+	TaskID      int // ID изменившейся задачи
+}
+
+// Error возвращает текст ошибки для пользователя.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("операция #%d: задача с ID %d изменилась после неё", e.OperationID, e.TaskID)
+}
+
+// JournaledStore — обёртка над любым Storage, которая записывает каждую
+// изменяющую операцию в журнал и умеет отменять (Undo) и повторять (Redo) их.
+// Если задан Events, каждое изменение задачи, включая undo и redo,
+// дополнительно попадает в журнал аудита. Если задан Trash, удалённые
+// задачи попадают в корзину, откуда их можно восстановить (Restore),
+// а если задан Archive — задачи, убранные операцией "archive", попадают
+// в архив завершённых задач. Если задан OnChange, он вызывается после
+// каждой записанной операции, а также после undo и redo.
+// Новая задача получает ID больше всех когда-либо выданных, включая задачи
+// в корзине, архиве и журнале аудита, поэтому ID удалённой задачи не
+// достаётся другой.
+// Методы чтения (GetTask, ListTasks) и Close передаются исходному хранилищу.
+type JournaledStore struct {
+	Storage           // исходное хранилище задач
+	Journal *Journal  // журнал операций
+	Events  *EventLog // журнал аудита (может быть nil)
+	Trash   *Trash    // корзина удалённых задач (может быть nil)
+	Archive *Archive  // архив завершённых задач (может быть nil)
+
+	// OnChange получает каждую изменившую задачи операцию: Before — затронутые
+	// задачи до неё, After — после (для undo — в обратном направлении).
+	OnChange func(op Operation)
+}
+
+// NewJournaledStore — конструктор JournaledStore.
+func NewJournaledStore(inner Storage, journal *Journal) *JournaledStore {
+	return &JournaledStore{Storage: inner, Journal: journal}
+}
+
+// AddTask добавляет задачу с новым ID (см. issuedID) и записывает операцию "add".
+func (s *JournaledStore) AddTask(t task.Task) (task.Task, error) {
+	t.ID = 0
+	after, err := s.modify("add", func(tasks []task.Task) ([]task.Task, error) {
+		return append(tasks, t), nil
+	})
+	if err != nil {
+		return task.Task{}, err
+	}
+	return after[len(after)-1], nil
+}
+
+// UpdateTask заменяет задачу и записывает операцию "update".
+// Состояния до и после берутся под одной блокировкой хранилища.
+func (s *JournaledStore) UpdateTask(t task.Task) error {
+	return s.ModifyAs("update", func(tasks []task.Task) ([]task.Task, error) {
+		for i := range tasks {
+			if tasks[i].ID == t.ID {
+				tasks[i] = t
+				return tasks, nil
+			}
+		}
+		return nil, &NotFoundError{ID: t.ID}
+	})
+}
+
+// MarkTaskDone отмечает задачу выполненной и записывает операцию "done".
+// Следующее повторение повторяющейся задачи попадает в ту же операцию,
+// поэтому undo убирает его вместе с отметкой о выполнении.
+func (s *JournaledStore) MarkTaskDone(id int) error {
+	return s.ModifyAs("done", func(tasks []task.Task) ([]task.Task, error) {
+		return markDone(tasks, id, time.Now())
+	})
+}
+
+// DeleteTask удаляет задачу и записывает операцию "delete".
+// Задача попадает в корзину до удаления из списка (см. ModifyAs).
+func (s *JournaledStore) DeleteTask(id int) error {
+	return s.ModifyAs("delete", func(tasks []task.Task) ([]task.Task, error) {
+		for i := range tasks {
+			if tasks[i].ID == id {
+				return slices.Delete(tasks, i, i+1), nil
+			}
+		}
+		return nil, &NotFoundError{ID: id}
+	})
+}
+
+// OverwriteTasks заменяет весь список и записывает операцию "overwrite".
+func (s *JournaledStore) OverwriteTasks(tasks []task.Task) error {
+	return s.ModifyAs("overwrite", func([]task.Task) ([]task.Task, error) {
+		return tasks, nil
+	})
+}
+
+// Modify изменяет список задач и записывает операцию "modify".
+func (s *JournaledStore) Modify(fn func(tasks []task.Task) ([]task.Task, error)) error {
+	return s.ModifyAs("modify", fn)
+}
+
+// ModifyAs работает как Modify, но подписывает операцию в журнале
+// переданным именем (например, "clear" или "complete-all"). Убранные из
+// списка задачи попадают в корзину или архив до сохранения списка: если
+// записать их не удалось, список остаётся прежним.
+func (s *JournaledStore) ModifyAs(kind string, fn func(tasks []task.Task) ([]task.Task, error)) error {
+	_, err := s.modify(kind, fn)
+	return err
+}
+
+// modify — приватный метод, реализация ModifyAs. Возвращает новое
+// состояние списка. Новым задачам (ID = 0) назначаются ID после всех
+// когда-либо выданных (см. issuedID).
+func (s *JournaledStore) modify(kind string, fn func(tasks []task.Task) ([]task.Task, error)) ([]task.Task, error) {
+	var before, after, stashed []task.Task
+	err := s.Storage.Modify(func(tasks []task.Task) ([]task.Task, error) {
+		before = cloneTasks(tasks)
+
+		updated, err := fn(tasks)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.assignIDs(before, updated); err != nil {
+			return nil, err
+		}
+
+		// Нормализуем и проставляем отметки времени сами, чтобы в журнал
+		// попали уже назначенные ID и то же состояние, что в хранилище.
+		if after, err = normalizeTasks(updated); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		stampTasks(before, after, now)
+
+		removed, _ := removedAndReturned(before, after)
+		if err := s.stash(kind, removed, now); err != nil {
+			return nil, err
+		}
+		stashed = removed
+		return after, nil
+	})
+	if err != nil {
+		s.unstashFailed(stashed)
+		return nil, err
+	}
+	return after, s.record(kind, before, after)
+}
+
+// assignIDs — приватный метод, назначает задачам tasks с нулевым ID
+// новые ID по порядку следования, начиная со следующего за issuedID.
+func (s *JournaledStore) assignIDs(before, tasks []task.Task) error {
+	next := 0
+	for i := range tasks {
+		if tasks[i].ID != 0 {
+			continue
+		}
+		if next == 0 {
+			issued, err := s.issuedID(before, tasks)
+			if err != nil {
+				return err
+			}
+			next = issued + 1
+		}
+		tasks[i].ID = next
+		next++
+	}
+	return nil
+}
+
+// issuedID — приватный метод, возвращает наибольший ID из когда-либо
+// выданных: среди переданных списков задач, корзины, архива и журнала аудита.
+func (s *JournaledStore) issuedID(lists ...[]task.Task) (int, error) {
+	maxID := 0
+	for _, tasks := range lists {
+		for _, t := range tasks {
+			maxID = max(maxID, t.ID)
+		}
+	}
+
+	if s.Trash != nil {
+		entries, err := s.Trash.List()
+		if err != nil {
+			return 0, fmt.Errorf("не удалось прочитать корзину: %w", err)
+		}
+		for _, e := range entries {
+			maxID = max(maxID, e.Task.ID)
+		}
+	}
+	if s.Archive != nil {
+		entries, err := s.Archive.List()
+		if err != nil {
+			return 0, fmt.Errorf("не удалось прочитать архив: %w", err)
+		}
+		for _, e := range entries {
+			maxID = max(maxID, e.Task.ID)
+		}
+	}
+	if s.Events != nil {
+		_, err := s.Events.Read(func(e Event) bool {
+			maxID = max(maxID, e.TaskID)
+			return false // сами события не нужны
+		})
+		if err != nil {
+			return 0, fmt.Errorf("не удалось прочитать историю: %w", err)
+		}
+	}
+	return maxID, nil
+}
+
+// Undo отменяет последнюю применённую операцию журнала и возвращает её.
+func (s *JournaledStore) Undo() (Operation, error) {
+	var result Operation
+	err := s.Journal.update(func(ops []Operation) ([]Operation, error) {
+		for i := len(ops) - 1; i >= 0; i-- {
+			if ops[i].Undone {
+				continue
+			}
+			// Отмена добавления не удаляет задачу пользователя,
+			// поэтому в корзину она не попадает.
+			apply := s.applyStashed
+			if ops[i].Kind == "add" {
+				apply = s.apply
+			}
+			if err := apply(ops[i], ops[i].After, ops[i].Before); err != nil {
+				return nil, err
+			}
+			ops[i].Undone = true
+			result = ops[i]
+			return ops, nil
+		}
+		return nil, ErrNothingToUndo
+	})
+	if err != nil {
+		return result, err
+	}
+	if err := s.replay(result.After, result.Before); err != nil {
+		return result, err
+	}
+	s.notify(Operation{Kind: "undo", Time: time.Now(), TaskIDs: result.TaskIDs, Before: result.After, After: result.Before})
+	return result, s.audit("undo", result.TaskIDs, result.After, result.Before)
+}
+
+// Redo повторяет самую раннюю из отменённых операций и возвращает её.
+func (s *JournaledStore) Redo() (Operation, error) {
+	var result Operation
+	err := s.Journal.update(func(ops []Operation) ([]Operation, error) {
+		for i := range ops {
+			if !ops[i].Undone {
+				continue
+			}
+			if err := s.applyStashed(ops[i], ops[i].Before, ops[i].After); err != nil {
+				return nil, err
+			}
+			ops[i].Undone = false
+			result = ops[i]
+			return ops, nil
+		}
+		return nil, ErrNothingToRedo
+	})
+	if err != nil {
+		return result, err
+	}
+	if err := s.replay(result.Before, result.After); err != nil {
+		return result, err
+	}
+	s.notify(Operation{Kind: "redo", Time: time.Now(), TaskIDs: result.TaskIDs, Before: result.Before, After: result.After})
+	return result, s.audit("redo", result.TaskIDs, result.Before, result.After)
+}
+
+// record — приватный метод, записывает в журнал только реально
+// изменившиеся задачи. Операция без изменений не записывается.
+// Убранные задачи к этому моменту уже лежат в корзине или архиве (см. stash).
+func (s *JournaledStore) record(kind string, before, after []task.Task) error {
+	op := diffOperation(kind, before, after)
+	if len(op.TaskIDs) == 0 {
+		return nil
+	}
+	op.Time = time.Now()
+	s.notify(op)
+
+	if _, err := s.Journal.Record(op); err != nil {
+		return fmt.Errorf("изменение сохранено, но не записано в журнал: %w", err)
+	}
+	return s.audit(kind, op.TaskIDs, op.Before, op.After)
+}
+
+// notify — приватный метод, передаёт операцию в OnChange, если он задан.
+func (s *JournaledStore) notify(op Operation) {
+	if s.OnChange != nil {
+		s.OnChange(op)
+	}
+}
+
+// removedAndReturned — приватная функция, возвращает задачи, которые
+// исчезли из списка (есть в before, нет в after), и задачи, которые
+// в нём появились.
+func removedAndReturned(before, after []task.Task) (removed, returned []task.Task) {
+	b, a := tasksByID(before), tasksByID(after)
+	for _, t := range before {
+		if _, ok := a[t.ID]; !ok {
+			removed = append(removed, t)
+		}
+	}
+	for _, t := range after {
+		if _, ok := b[t.ID]; !ok {
+			returned = append(returned, t)
+		}
+	}
+	return removed, returned
+}
+
+// stash — приватный метод, переносит задачи, которые убираются из списка,
+// в архив (операция "archive") или в корзину (все остальные операции).
+// Вызывается до сохранения списка, чтобы задачи не потерялись, если
+// записать корзину или архив не удалось.
+func (s *JournaledStore) stash(kind string, removed []task.Task, now time.Time) error {
+	switch {
+	case kind == "archive" && s.Archive != nil:
+		if err := s.Archive.Add(now, removed...); err != nil {
+			return fmt.Errorf("задачи не удалось перенести в архив, изменение не сохранено: %w", err)
+		}
+	case s.Trash != nil:
+		if err := s.Trash.Add(now, removed...); err != nil {
+			return fmt.Errorf("задачи не удалось перенести в корзину, изменение не сохранено: %w", err)
+		}
+	}
+	return nil
+}
+
+// unstashFailed — приватный метод, убирает из корзины и архива задачи,
+// перенесённые туда операцией, которую не удалось сохранить: они остались
+// в списке. Ошибка не возвращается — задачи в любом случае не потеряны.
+func (s *JournaledStore) unstashFailed(stashed []task.Task) {
+	_ = s.unstash(stashed)
+}
+
+// unstash — приватный метод, убирает из корзины и архива задачи,
+// которые вернулись в список без изменений после undo или redo.
+func (s *JournaledStore) unstash(returned []task.Task) error {
+	if len(returned) == 0 {
+		return nil
+	}
+	isReturned := func(t task.Task) bool {
+		for _, r := range returned {
+			if sameTask(t, r) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if s.Trash != nil {
+		if _, err := s.Trash.Remove(func(e TrashEntry) bool { return isReturned(e.Task) }); err != nil {
+			return fmt.Errorf("изменение сохранено, но корзина не обновлена: %w", err)
+		}
+	}
+	if s.Archive != nil {
+		if _, err := s.Archive.Remove(func(e ArchiveEntry) bool { return isReturned(e.Task) }); err != nil {
+			return fmt.Errorf("изменение сохранено, но архив не обновлён: %w", err)
+		}
+	}
+	return nil
+}
+
+// applyStashed — приватный метод, работает как apply, но сначала переносит
+// задачи, которые убираются из списка, в корзину или архив (см. stash).
+func (s *JournaledStore) applyStashed(op Operation, from, to []task.Task) error {
+	removed, _ := removedAndReturned(from, to)
+	if err := s.stash(op.Kind, removed, time.Now()); err != nil {
+		return err
+	}
+	if err := s.apply(op, from, to); err != nil {
+		s.unstashFailed(removed)
+		return err
+	}
+	return nil
+}
+
+// replay — приватный метод, убирает из корзины и архива задачи, которые
+// вернулись в список после undo или redo.
+func (s *JournaledStore) replay(before, after []task.Task) error {
+	_, returned := removedAndReturned(before, after)
+	return s.unstash(returned)
+}
+
+// audit — приватный метод, дописывает изменения задач в журнал аудита.
+func (s *JournaledStore) audit(kind string, ids []int, before, after []task.Task) error {
+	if s.Events == nil {
+		return nil
+	}
+	if err := s.Events.Append(operationEvents(kind, ids, before, after)...); err != nil {
+		return fmt.Errorf("изменение сохранено, но не записано в историю: %w", err)
+	}
+	return nil
+}
+
+// apply — приватный метод, переводит затронутые операцией задачи из
+// состояния from в состояние to. Если текущее состояние задач не совпадает
+// с from, возвращает ConflictError и ничего не меняет.
+func (s *JournaledStore) apply(op Operation, from, to []task.Task) error {
+	expected := tasksByID(from)
+	target := tasksByID(to)
+	affected := make(map[int]bool, len(op.TaskIDs))
+	for _, id := range op.TaskIDs {
+		affected[id] = true
+	}
+
+	return s.Storage.Modify(func(tasks []task.Task) ([]task.Task, error) {
+		current := tasksByID(tasks)
+		for _, id := range op.TaskIDs {
+			cur, ok := current[id]
+			exp, wantOK := expected[id]
+			if ok != wantOK || (ok && !sameTask(cur, exp)) {
+				return nil, &ConflictError{OperationID: op.ID, TaskID: id}
+			}
+		}
+
+		result := make([]task.Task, 0, len(tasks)+len(target))
+		for _, t := range tasks {
+			if !affected[t.ID] {
+				result = append(result, t)
+			}
+		}
+		for _, id := range op.TaskIDs {
+			if t, ok := target[id]; ok {
+				result = append(result, t)
+			}
+		}
+		return result, nil
+	})
+}
+
+// diffOperation — приватная функция, строит операцию журнала из
+// состояний списка до и после, оставляя только изменившиеся задачи.
+func diffOperation(kind string, before, after []task.Task) Operation {
+	b, a := tasksByID(before), tasksByID(after)
+
+	ids := make([]int, 0, len(b)+len(a))
+	for id := range b {
+		ids = append(ids, id)
+	}
+	for id := range a {
+		if _, ok := b[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	op := Operation{Kind: kind, TaskIDs: []int{}, Before: []task.Task{}, After: []task.Task{}}
+	for _, id := range ids {
+		bt, inBefore := b[id]
+		at, inAfter := a[id]
+		if inBefore && inAfter && sameTask(bt, at) {
+			continue
+		}
+		op.TaskIDs = append(op.TaskIDs, id)
+		if inBefore {
+			op.Before = append(op.Before, bt)
+		}
+		if inAfter {
+			op.After = append(op.After, at)
+		}
+	}
+	return op
+}
+
+// tasksByID — приватная функция, индексирует задачи по ID.
+func tasksByID(tasks []task.Task) map[int]task.Task {
+	m := make(map[int]task.Task, len(tasks))
+	for _, t := range tasks {
+		m[t.ID] = t
+	}
+	return m
+}
+
+// sameTask — приватная функция, сравнивает задачи по их JSON-представлению.
+// Так сравнение не зависит от часового пояса и монотонных часов в time.Time,
+// которые теряются при сохранении.
+func sameTask(a, b task.Task) bool {
+	da, errA := json.Marshal(a)
+	db, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(da, db)
+}
@@ -3,24 +3,53 @@ package storage
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"github.com/zen-flo/todo-cli/internal/task"
 	"os"
+	"sort"
 	"sync"
+	"time"
 )
 
 // JSONStore — реализация интерфейса Storage.
 // Задачи хранятся в JSON-файле на диске.
 type JSONStore struct {
-	FilePath string     // путь к файлу с задачами
-	mu       sync.Mutex // мьютекс для защиты при параллельном доступе
+	FilePath    string        // путь к файлу с задачами
+	LockTimeout time.Duration // сколько ждать блокировку файла другим процессом
+	mu          sync.Mutex    // мьютекс для защиты при параллельном доступе
 }
 
 // NewJSONStore — конструктор JSONStore.
 // NewJSONStore создаёт новый экземпляр JSONStore с указанным файлом.
-// Потокобезопасный метод: внутренние операции синхронизированы мьютексом.
+// Потокобезопасный метод: внутренние операции синхронизированы мьютексом
+// внутри процесса и блокировкой файла между процессами.
 func NewJSONStore(filePath string) *JSONStore {
-	return &JSONStore{FilePath: filePath}
+	return &JSONStore{FilePath: filePath, LockTimeout: DefaultLockTimeout}
+}
+
+// lock — приватный метод, захватывает мьютекс и межпроцессную блокировку
+// на весь цикл "загрузка → изменение → сохранение". Возвращает функцию,
+// снимающую обе блокировки.
+//
+// Блокируется отдельный файл FilePath + ".lock": сам файл задач при
+// атомарной записи заменяется новым, и блокировка на нём бы терялась.
+// Чтение без блокировки безопасно, т.к. запись атомарна.
+func (s *JSONStore) lock() (func(), error) {
+	timeout := s.LockTimeout
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+
+	s.mu.Lock()
+	l, err := acquireLock(s.FilePath+".lock", timeout)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	return func() {
+		_ = l.release()
+		s.mu.Unlock()
+	}, nil
 }
 
 // loadTasks — приватный метод, загружает задачи из JSON-файла.
@@ -43,62 +72,85 @@ func (s *JSONStore) loadTasks() ([]task.Task, error) {
 		return nil, err
 	}
 
+	// Файл мог быть отредактирован вручную — возвращаем задачи по возрастанию ID,
+	// как и остальные реализации Storage.
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+
 	return tasks, nil
 }
 
 // saveTasks — приватный метод, сохраняет список задач в JSON-файл.
+// Запись атомарная: при сбое посередине старый файл остаётся целым.
 func (s *JSONStore) saveTasks(tasks []task.Task) error {
 	data, err := json.MarshalIndent(tasks, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(s.FilePath, data, 0644)
+	return writeFileAtomic(s.FilePath, data, 0644)
 }
 
 // AddTask добавляет новую задачу в хранилище.
-// Потокобезопасный метод: использует мьютекс для синхронизации доступа.
-// Возвращает ошибку, если не удалось сохранить задачу.
-func (s *JSONStore) AddTask(t task.Task) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// Потокобезопасный метод: использует мьютекс и блокировку файла.
+// Возвращает задачу с назначенным ID или ошибку, если не удалось сохранить задачу.
+func (s *JSONStore) AddTask(t task.Task) (task.Task, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return task.Task{}, err
+	}
+	defer unlock()
 
 	// Загружаем все задачи
 	tasks, err := s.loadTasks()
 	if err != nil {
-		return err
-	}
-
-	// Находим максимальный ID
-	maxID := 0
-	for _, existing := range tasks {
-		if existing.ID > maxID {
-			maxID = existing.ID
-		}
+		return task.Task{}, err
 	}
 
-	// Присваиваем новый ID
-	t.ID = maxID + 1
+	// Присваиваем новый ID: максимальный существующий + 1
+	t.ID = nextID(tasks)
+	stampTask(&t, task.Task{}, false, time.Now())
 
 	// Добавляем задачу
 	tasks = append(tasks, t)
 
 	// Сохраняем обратно
-	return s.saveTasks(tasks)
+	if err := s.saveTasks(tasks); err != nil {
+		return task.Task{}, err
+	}
+	return t, nil
+}
+
+// GetTask возвращает задачу с указанным ID.
+// Если задача с таким ID не найдена, возвращает NotFoundError.
+func (s *JSONStore) GetTask(id int) (task.Task, error) {
+	tasks, err := s.loadTasks()
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	for _, t := range tasks {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return task.Task{}, &NotFoundError{ID: id}
 }
 
 // ListTasks возвращает все задачи из хранилища.
-// Потокобезопасный метод: использует мьютекс для синхронизации доступа.
+// Блокировка не нужна: запись атомарна, и читатель видит целый файл.
 // Возвращает слайс задач и ошибку, если не удалось загрузить данные.
 func (s *JSONStore) ListTasks() ([]task.Task, error) {
 	return s.loadTasks()
 }
 
-// UpdateTask изменяет название задачи с указанным ID.
-// Потокобезопасный метод: использует мьютекс для синхронизации доступа.
-// Если задача с таким ID не найдена, возвращает ошибку.
-func (s *JSONStore) UpdateTask(id int, newTitle string, important bool) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// UpdateTask заменяет сохранённую задачу с тем же ID на переданную.
+// Потокобезопасный метод: использует мьютекс и блокировку файла.
+// Если задача с таким ID не найдена, возвращает NotFoundError.
+func (s *JSONStore) UpdateTask(t task.Task) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	tasks, err := s.loadTasks()
 	if err != nil {
@@ -107,27 +159,30 @@ func (s *JSONStore) UpdateTask(id int, newTitle string, important bool) error {
 
 	found := false
 	for i := range tasks {
-		if tasks[i].ID == id {
-			tasks[i].Title = newTitle
-			tasks[i].Important = important
+		if tasks[i].ID == t.ID {
+			stampTask(&t, tasks[i], true, time.Now())
+			tasks[i] = t
 			found = true
 			break
 		}
 	}
 
 	if !found {
-		return fmt.Errorf("задача с ID %d не найдена", id)
+		return &NotFoundError{ID: t.ID}
 	}
 
 	return s.saveTasks(tasks)
 }
 
 // DeleteTask удаляет задачу с указанным ID из хранилища.
-// Потокобезопасный метод: использует мьютекс для синхронизации доступа.
+// Потокобезопасный метод: использует мьютекс и блокировку файла.
 // Если задача с таким ID не найдена, возвращает ошибку.
 func (s *JSONStore) DeleteTask(id int) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Загружаем все задачи
 	tasks, err := s.loadTasks()
@@ -147,50 +202,80 @@ func (s *JSONStore) DeleteTask(id int) error {
 	}
 
 	if !found {
-		return fmt.Errorf("задача с ID %d не найдена", id)
+		return &NotFoundError{ID: id}
 	}
 
 	// Сохраняем обновлённый список задач
 	return s.saveTasks(newTasks)
 }
 
-// MarkTaskDone отмечает задачу с указанным ID как выполненную.
-// Потокобезопасный метод: использует мьютекс для синхронизации доступа.
+// MarkTaskDone отмечает задачу с указанным ID как выполненную,
+// а для повторяющейся задачи добавляет следующее повторение.
+// Потокобезопасный метод: использует мьютекс и блокировку файла.
 // Если задача с таким ID не найдена, возвращает ошибку.
 func (s *JSONStore) MarkTaskDone(id int) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	return s.Modify(func(tasks []task.Task) ([]task.Task, error) {
+		return markDone(tasks, id, time.Now())
+	})
+}
 
-	// Загружаем все задачи
-	tasks, err := s.loadTasks()
+// OverwriteTasks полностью заменяет список задач в хранилище.
+// Задачам с нулевым ID назначаются новые ID, дубликаты ID запрещены.
+// Потокобезопасный метод: использует мьютекс и блокировку файла.
+func (s *JSONStore) OverwriteTasks(tasks []task.Task) error {
+	unlock, err := s.lock()
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	// Ищем задачу по ID
-	found := false
-	for i := range tasks {
-		if tasks[i].ID == id {
-			tasks[i].Completed = true
-			found = true
-			break
-		}
+	normalized, err := normalizeTasks(tasks)
+	if err != nil {
+		return err
 	}
 
-	// Если задача не найдена — сообщаем пользователю
-	if !found {
-		return fmt.Errorf("задача с ID %d не найдена", id)
+	// Перезаписать можно и повреждённый файл: тогда все задачи считаются новыми
+	current, err := s.loadTasks()
+	if err != nil {
+		current = nil
 	}
-
-	// Сохраняем обновлённый список задач
-	return s.saveTasks(tasks)
+	stampTasks(current, normalized, time.Now())
+	return s.saveTasks(normalized)
 }
 
-// OverwriteTasks полностью заменяет список задач в хранилище.
-// Потокобезопасный метод: использует мьютекс для синхронизации доступа.
-func (s *JSONStore) OverwriteTasks(tasks []task.Task) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// Modify загружает список задач, передаёт его в fn и сохраняет результат.
+// Чтение и запись выполняются под одной блокировкой, поэтому массовые
+// операции (complete-all, clear) не теряют параллельные изменения.
+// Если fn вернула ошибку, хранилище не изменяется.
+// Потокобезопасный метод: использует мьютекс и блокировку файла.
+func (s *JSONStore) Modify(fn func(tasks []task.Task) ([]task.Task, error)) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	return s.saveTasks(tasks)
+	tasks, err := s.loadTasks()
+	if err != nil {
+		return err
+	}
+	before := cloneTasks(tasks)
+
+	updated, err := fn(tasks)
+	if err != nil {
+		return err
+	}
+
+	normalized, err := normalizeTasks(updated)
+	if err != nil {
+		return err
+	}
+	stampTasks(before, normalized, time.Now())
+	return s.saveTasks(normalized)
+}
+
+// Close ничего не делает: JSONStore не держит открытых ресурсов
+// между вызовами. Метод нужен для соответствия интерфейсу Storage.
+func (s *JSONStore) Close() error {
+	return nil
 }
@@ -11,7 +11,7 @@ import (
 	"golang.org/x/text/language"
 )
 
-// TestAddTask проверяет добавление задачи с учетом Important и Completed.
+// TestAddTask проверяет добавление задачи с учетом Priority и Status.
 func TestAddTask(t *testing.T) {
 	// Создаём временный файл, чтобы не трогать реальный tasks.json.
 	tmpFile, err := os.CreateTemp("", "tasks_*.json")
@@ -29,13 +29,13 @@ func TestAddTask(t *testing.T) {
 	newTask := task.Task{
 		ID:        1,
 		Title:     "Тестовая задача",
-		Completed: false,
-		Important: true,
+		Status:    task.StatusTodo,
+		Priority:  task.PriorityHigh,
 		CreatedAt: time.Now(),
 	}
 
 	// Добавляем задачу в хранилище.
-	if err := store.AddTask(newTask); err != nil {
+	if _, err := store.AddTask(newTask); err != nil {
 		t.Fatalf("AddTask вернул ошибку: %v", err)
 	}
 
@@ -49,7 +49,7 @@ func TestAddTask(t *testing.T) {
 		t.Fatalf("ожидалось 1 задача, получили %d", len(tasks))
 	}
 
-	if tasks[0].Title != "Тестовая задача" || !tasks[0].Important || tasks[0].Completed {
+	if tasks[0].Title != "Тестовая задача" || tasks[0].Priority != task.PriorityHigh || tasks[0].Status == task.StatusDone {
 		t.Errorf("неверные данные задачи: %+v", tasks[0])
 	}
 }
@@ -70,13 +70,13 @@ func TestListTasks(t *testing.T) {
 
 	// Добавляем несколько тестовых задач
 	tasksToAdd := []task.Task{
-		{ID: 1, Title: "Первая", CreatedAt: time.Now(), Important: true},
+		{ID: 1, Title: "Первая", CreatedAt: time.Now(), Priority: task.PriorityHigh},
 		{ID: 2, Title: "Вторая", CreatedAt: time.Now().Add(time.Minute)},
-		{ID: 3, Title: "Третья", Completed: true, CreatedAt: time.Now().Add(2 * time.Minute)},
+		{ID: 3, Title: "Третья", Status: task.StatusDone, CreatedAt: time.Now().Add(2 * time.Minute)},
 	}
 
 	for _, tt := range tasksToAdd {
-		if err := store.AddTask(tt); err != nil {
+		if _, err := store.AddTask(tt); err != nil {
 			t.Fatalf("AddTask вернул ошибку: %v", err)
 		}
 	}
@@ -97,15 +97,15 @@ func TestListTasks(t *testing.T) {
 		if allTasks[i].Title != tt.Title {
 			t.Errorf("ожидался заголовок %q, получено %q", tt.Title, allTasks[i].Title)
 		}
-		if allTasks[i].Completed != tt.Completed {
-			t.Errorf("ошибка статуса: ожидалось %v, получено %v", tt.Completed, allTasks[i].Completed)
+		if allTasks[i].Status != tt.Status {
+			t.Errorf("ошибка статуса: ожидалось %v, получено %v", tt.Status, allTasks[i].Status)
 		}
 	}
 
 	// Проверка фильтра important
 	var importantTasks []task.Task
 	for _, t := range allTasks {
-		if t.Important {
+		if t.Priority >= task.PriorityHigh {
 			importantTasks = append(importantTasks, t)
 		}
 	}
@@ -116,7 +116,7 @@ func TestListTasks(t *testing.T) {
 	// Проверка фильтра completed
 	var completedTasks []task.Task
 	for _, t := range allTasks {
-		if t.Completed {
+		if t.Status == task.StatusDone {
 			completedTasks = append(completedTasks, t)
 		}
 	}
@@ -168,7 +168,7 @@ func TestDeleteTask(t *testing.T) {
 	}
 
 	for _, tt := range tasksToAdd {
-		if err := store.AddTask(tt); err != nil {
+		if _, err := store.AddTask(tt); err != nil {
 			t.Fatalf("AddTask вернул ошибку: %v", err)
 		}
 	}
@@ -215,24 +215,24 @@ func TestUpdateTask(t *testing.T) {
 	initialTask := task.Task{
 		ID:        1,
 		Title:     "Старое название",
-		Completed: false,
-		Important: false,
+		Status:    task.StatusTodo,
+		Priority:  task.PriorityNormal,
 		CreatedAt: time.Now(),
 	}
 
-	if err := store.AddTask(initialTask); err != nil {
+	if _, err := store.AddTask(initialTask); err != nil {
 		t.Fatalf("AddTask вернул ошибку: %v", err)
 	}
 
-	// Обновляем title и important
-	if err := store.UpdateTask(1, "Новое название", true); err != nil {
+	// Обновляем title и priority
+	if err := store.UpdateTask(task.Task{ID: 1, Title: "Новое название", Priority: task.PriorityHigh, CreatedAt: initialTask.CreatedAt}); err != nil {
 		t.Fatalf("UpdateTask вернул ошибку: %v", err)
 	}
 
 	// Загружаем обновлённый список.
 	// Проверяем, что название изменилось.
 	tasks, _ := store.ListTasks()
-	if tasks[0].Title != "Новое название" || !tasks[0].Important {
+	if tasks[0].Title != "Новое название" || tasks[0].Priority != task.PriorityHigh {
 		t.Errorf("обновление задачи не сработало: %+v", tasks[0])
 	}
 }
@@ -255,12 +255,12 @@ func TestMarkDone(t *testing.T) {
 	taskToAdd := task.Task{
 		ID:        1,
 		Title:     "Проверить MarkDone",
-		Completed: false,
-		Important: false,
+		Status:    task.StatusTodo,
+		Priority:  task.PriorityNormal,
 		CreatedAt: time.Now(),
 	}
 
-	if err := store.AddTask(taskToAdd); err != nil {
+	if _, err := store.AddTask(taskToAdd); err != nil {
 		t.Fatalf("AddTask вернул ошибку: %v", err)
 	}
 
@@ -281,8 +281,8 @@ func TestMarkDone(t *testing.T) {
 	}
 
 	// Проверяем, что задача действительно отмечена как выполненная.
-	if !tasks[0].Completed {
-		t.Errorf("ожидалось, что задача будет выполнена, но Completed=false")
+	if tasks[0].Status != task.StatusDone {
+		t.Errorf("ожидалось, что задача будет выполнена, но Status=%s", tasks[0].Status)
 	}
 }
 
@@ -298,7 +298,7 @@ func TestJSONStore_ReadNonExist(t *testing.T) {
 // --- Тест UpdateTask() на несуществующем файле ---
 func TestJSONStore_UpdateNonExist(t *testing.T) {
 	store := NewJSONStore("/non/exist/file.json")
-	err := store.UpdateTask(999, "Title", false)
+	err := store.UpdateTask(task.Task{ID: 999, Title: "Title"})
 	if err == nil {
 		t.Errorf("ожидалось, что обновление несуществующей задачи вернет ошибку")
 	}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// MemoryStore — реализация интерфейса Storage в оперативной памяти.
+// Ничего не сохраняет на диск; полезна в тестах и как эталон поведения
+// для других хранилищ.
+type MemoryStore struct {
+	mu    sync.Mutex  // мьютекс для защиты при параллельном доступе
+	tasks []task.Task // задачи по возрастанию ID
+}
+
+// NewMemoryStore — конструктор MemoryStore с пустым списком задач.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tasks: []task.Task{}}
+}
+
+// AddTask добавляет новую задачу и назначает ей максимальный ID + 1.
+func (s *MemoryStore) AddTask(t task.Task) (task.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t.ID = nextID(s.tasks)
+	stampTask(&t, task.Task{}, false, time.Now())
+	s.tasks = append(s.tasks, t.Clone())
+	return t, nil
+}
+
+// GetTask возвращает задачу с указанным ID.
+// Если задача с таким ID не найдена, возвращает NotFoundError.
+func (s *MemoryStore) GetTask(id int) (task.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := s.indexOf(id); i >= 0 {
+		return s.tasks[i].Clone(), nil
+	}
+	return task.Task{}, &NotFoundError{ID: id}
+}
+
+// ListTasks возвращает копию списка задач по возрастанию ID.
+func (s *MemoryStore) ListTasks() ([]task.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return cloneTasks(s.tasks), nil
+}
+
+// UpdateTask заменяет задачу с тем же ID на переданную.
+// Если задача с таким ID не найдена, возвращает NotFoundError.
+func (s *MemoryStore) UpdateTask(t task.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(t.ID)
+	if i < 0 {
+		return &NotFoundError{ID: t.ID}
+	}
+	stampTask(&t, s.tasks[i], true, time.Now())
+	s.tasks[i] = t.Clone()
+	return nil
+}
+
+// DeleteTask удаляет задачу с указанным ID.
+// Если задача с таким ID не найдена, возвращает NotFoundError.
+func (s *MemoryStore) DeleteTask(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(id)
+	if i < 0 {
+		return &NotFoundError{ID: id}
+	}
+	s.tasks = append(s.tasks[:i], s.tasks[i+1:]...)
+	return nil
+}
+
+// MarkTaskDone отмечает задачу с указанным ID как выполненную,
+// а для повторяющейся задачи добавляет следующее повторение.
+// Если задача с таким ID не найдена, возвращает NotFoundError.
+func (s *MemoryStore) MarkTaskDone(id int) error {
+	return s.Modify(func(tasks []task.Task) ([]task.Task, error) {
+		return markDone(tasks, id, time.Now())
+	})
+}
+
+// OverwriteTasks полностью заменяет список задач.
+func (s *MemoryStore) OverwriteTasks(tasks []task.Task) error {
+	return s.Modify(func([]task.Task) ([]task.Task, error) {
+		return tasks, nil
+	})
+}
+
+// Modify передаёт копию списка задач в fn и сохраняет результат.
+// Если fn вернула ошибку, список не изменяется.
+func (s *MemoryStore) Modify(fn func(tasks []task.Task) ([]task.Task, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated, err := fn(cloneTasks(s.tasks))
+	if err != nil {
+		return err
+	}
+
+	normalized, err := normalizeTasks(updated)
+	if err != nil {
+		return err
+	}
+	stampTasks(s.tasks, normalized, time.Now())
+	s.tasks = normalized
+	return nil
+}
+
+// Close ничего не делает и нужна для соответствия интерфейсу Storage.
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// indexOf — приватный метод, возвращает индекс задачи с ID или -1.
+// Вызывающий должен держать мьютекс.
+func (s *MemoryStore) indexOf(id int) int {
+	for i, t := range s.tasks {
+		if t.ID == id {
+			return i
+		}
+	}
+	return -1
+}
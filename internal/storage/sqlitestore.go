@@ -0,0 +1,415 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+
+	_ "modernc.org/sqlite" // чистый Go-драйвер SQLite, собирается без cgo
+)
+
+// migrations — последовательные шаги создания и изменения схемы.
+// Номер применённого шага хранится в PRAGMA user_version, поэтому
+// новые изменения схемы нужно только дописывать в конец списка.
+var migrations = []string{
+	`CREATE TABLE IF NOT EXISTS tasks (
+		id         INTEGER PRIMARY KEY,
+		title      TEXT    NOT NULL,
+		completed  INTEGER NOT NULL DEFAULT 0,
+		created_at TEXT    NOT NULL,
+		important  INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_tasks_completed ON tasks(completed);`,
+	`ALTER TABLE tasks ADD COLUMN due_at TEXT;
+	CREATE INDEX IF NOT EXISTS idx_tasks_due_at ON tasks(due_at);`,
+	`ALTER TABLE tasks ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+	UPDATE tasks SET priority = 1 WHERE important = 1;
+	ALTER TABLE tasks DROP COLUMN important;`,
+	`ALTER TABLE tasks ADD COLUMN tags TEXT;`,
+	`ALTER TABLE tasks ADD COLUMN project TEXT NOT NULL DEFAULT '';
+	ALTER TABLE tasks ADD COLUMN archived INTEGER NOT NULL DEFAULT 0;
+	CREATE INDEX IF NOT EXISTS idx_tasks_project ON tasks(project);`,
+	`ALTER TABLE tasks ADD COLUMN parent_id INTEGER NOT NULL DEFAULT 0;
+	CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id);`,
+	`ALTER TABLE tasks ADD COLUMN blocked_by TEXT;`,
+	`ALTER TABLE tasks ADD COLUMN recur TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE tasks ADD COLUMN time_log TEXT;`,
+	`ALTER TABLE tasks ADD COLUMN notes TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE tasks ADD COLUMN status TEXT NOT NULL DEFAULT 'todo';
+	UPDATE tasks SET status = 'done' WHERE completed = 1;
+	DROP INDEX IF EXISTS idx_tasks_completed;
+	ALTER TABLE tasks DROP COLUMN completed;
+	CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);`,
+	`ALTER TABLE tasks ADD COLUMN updated_at TEXT;
+	ALTER TABLE tasks ADD COLUMN completed_at TEXT;`,
+}
+
+// SQLiteStore — реализация интерфейса Storage поверх SQLite.
+// В отличие от JSONStore, изменения не требуют перезаписи всего файла,
+// а поиск по ID и статусу выполняется по индексам.
+type SQLiteStore struct {
+	FilePath string  // путь к файлу базы данных
+	db       *sql.DB // пул соединений с базой
+}
+
+// NewSQLiteStore — конструктор SQLiteStore.
+// Открывает (или создаёт) базу по указанному пути и применяет схему.
+func NewSQLiteStore(filePath string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite допускает только одного писателя, поэтому одно соединение
+	// избавляет от ошибок SQLITE_BUSY внутри процесса.
+	db.SetMaxOpenConns(1)
+
+	s := &SQLiteStore{FilePath: filePath, db: db}
+	if err := s.migrate(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrate — приватный метод, доводит схему базы до актуальной версии.
+func (s *SQLiteStore) migrate() error {
+	// Ждём освобождения базы другими процессами вместо немедленной ошибки.
+	if _, err := s.db.Exec(`PRAGMA busy_timeout = 5000`); err != nil {
+		return err
+	}
+
+	var version int
+	if err := s.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("миграция схемы %d: %w", i+1, err)
+		}
+		// PRAGMA не поддерживает параметры, поэтому номер подставляем в строку.
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taskColumnNames — столбцы таблицы tasks в порядке, ожидаемом scanTask и taskArgs.
+var taskColumnNames = []string{"id", "title", "status", "created_at", "priority", "due_at", "tags", "project", "archived", "parent_id", "blocked_by", "recur", "time_log", "notes", "updated_at", "completed_at"}
+
+// Части SQL-запросов, построенные из taskColumnNames: при добавлении
+// столбца достаточно поправить список, scanTask и taskArgs.
+var (
+	taskColumns      = strings.Join(taskColumnNames, ", ")
+	taskPlaceholders = strings.TrimSuffix(strings.Repeat("?, ", len(taskColumnNames)), ", ")
+	taskAssignments  = strings.Join(taskColumnNames[1:], " = ?, ") + " = ?"
+)
+
+// scanner — общий интерфейс для *sql.Row и *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask — приватная функция, читает одну строку таблицы tasks.
+func scanTask(row scanner) (task.Task, error) {
+	var (
+		t         task.Task
+		status    string
+		createdAt string
+		dueAt     sql.NullString
+		tags      sql.NullString
+		blockedBy sql.NullString
+		recur     string
+		timeLog   sql.NullString
+		updatedAt sql.NullString
+		doneAt    sql.NullString
+	)
+	if err := row.Scan(&t.ID, &t.Title, &status, &createdAt, &t.Priority, &dueAt, &tags, &t.Project, &t.Archived, &t.ParentID, &blockedBy, &recur, &timeLog, &t.Notes, &updatedAt, &doneAt); err != nil {
+		return task.Task{}, err
+	}
+
+	created, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return task.Task{}, fmt.Errorf("задача с ID %d: некорректная дата создания: %w", t.ID, err)
+	}
+	t.CreatedAt = created
+
+	if t.Status, err = task.ParseStatus(status); err != nil {
+		return task.Task{}, fmt.Errorf("задача с ID %d: %w", t.ID, err)
+	}
+
+	if t.DueAt, err = parseNullTime(dueAt); err != nil {
+		return task.Task{}, fmt.Errorf("задача с ID %d: некорректный срок: %w", t.ID, err)
+	}
+	if t.UpdatedAt, err = parseNullTime(updatedAt); err != nil {
+		return task.Task{}, fmt.Errorf("задача с ID %d: некорректное время изменения: %w", t.ID, err)
+	}
+	if t.CompletedAt, err = parseNullTime(doneAt); err != nil {
+		return task.Task{}, fmt.Errorf("задача с ID %d: некорректное время выполнения: %w", t.ID, err)
+	}
+	if err := parseNullJSON(tags, &t.Tags); err != nil {
+		return task.Task{}, fmt.Errorf("задача с ID %d: некорректные теги: %w", t.ID, err)
+	}
+	if err := parseNullJSON(blockedBy, &t.BlockedBy); err != nil {
+		return task.Task{}, fmt.Errorf("задача с ID %d: некорректные зависимости: %w", t.ID, err)
+	}
+	if t.Recur, err = task.ParseRecurrence(recur); err != nil {
+		return task.Task{}, fmt.Errorf("задача с ID %d: %w", t.ID, err)
+	}
+	if err := parseNullJSON(timeLog, &t.TimeLog); err != nil {
+		return task.Task{}, fmt.Errorf("задача с ID %d: некорректный учёт времени: %w", t.ID, err)
+	}
+
+	return t, nil
+}
+
+// parseNullTime — приватная функция, разбирает необязательную дату.
+// NULL соответствует нулевому time.Time.
+func parseNullTime(v sql.NullString) (time.Time, error) {
+	if !v.Valid || v.String == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, v.String)
+}
+
+// parseNullJSON — приватная функция, разбирает необязательное значение,
+// сохранённое в столбце как JSON. NULL оставляет dest без изменений.
+func parseNullJSON(v sql.NullString, dest any) error {
+	if !v.Valid || v.String == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(v.String), dest)
+}
+
+// nullJSON — приватная функция, сохраняет срез как JSON, а пустой — как NULL.
+func nullJSON[T any](items []T) (any, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// nullTime — приватная функция, превращает нулевое время в NULL.
+func nullTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// taskArgs — приватная функция, возвращает значения столбцов taskColumns для задачи.
+func taskArgs(t task.Task) []any {
+	// Срезы строк, чисел и отрезков времени всегда сериализуются без ошибок.
+	tags, _ := nullJSON(t.Tags)
+	blockedBy, _ := nullJSON(t.BlockedBy)
+	timeLog, _ := nullJSON(t.TimeLog)
+	return []any{
+		t.ID, t.Title, t.Status.String(), t.CreatedAt.Format(time.RFC3339Nano), int(t.Priority), nullTime(t.DueAt),
+		tags, t.Project, t.Archived, t.ParentID, blockedBy, t.Recur.String(), timeLog, t.Notes,
+		nullTime(t.UpdatedAt), nullTime(t.CompletedAt),
+	}
+}
+
+// execer — общий интерфейс для *sql.DB и *sql.Tx.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// queryTasks — приватная функция, выполняет SELECT по таблице tasks.
+// where может быть пустой строкой или условием вида "WHERE ...".
+func queryTasks(db execer, where string, args ...any) ([]task.Task, error) {
+	rows, err := db.Query(`SELECT `+taskColumns+` FROM tasks `+where+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	tasks := []task.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
+
+// AddTask добавляет новую задачу в хранилище.
+// ID назначается базой: максимальный существующий ID + 1, как и в JSONStore.
+func (s *SQLiteStore) AddTask(t task.Task) (task.Task, error) {
+	// NULL в INTEGER PRIMARY KEY заставляет SQLite выдать max(id) + 1.
+	stampTask(&t, task.Task{}, false, time.Now())
+	args := taskArgs(t)
+	args[0] = nil
+
+	res, err := s.db.Exec(`INSERT INTO tasks (`+taskColumns+`) VALUES (`+taskPlaceholders+`)`, args...)
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return task.Task{}, err
+	}
+	t.ID = int(id)
+	return t, nil
+}
+
+// GetTask возвращает задачу с указанным ID, используя первичный ключ.
+// Если задача с таким ID не найдена, возвращает NotFoundError.
+func (s *SQLiteStore) GetTask(id int) (task.Task, error) {
+	t, err := scanTask(s.db.QueryRow(`SELECT `+taskColumns+` FROM tasks WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Task{}, &NotFoundError{ID: id}
+	}
+	return t, err
+}
+
+// ListTasks возвращает все задачи из хранилища в порядке возрастания ID.
+func (s *SQLiteStore) ListTasks() ([]task.Task, error) {
+	return queryTasks(s.db, "")
+}
+
+// UpdateTask заменяет сохранённую задачу с тем же ID на переданную.
+// Если задача с таким ID не найдена, возвращает NotFoundError.
+func (s *SQLiteStore) UpdateTask(t task.Task) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }() // после Commit откат ничего не делает
+
+	// Прежнее состояние нужно, чтобы проставить отметки времени
+	old, err := scanTask(tx.QueryRow(`SELECT `+taskColumns+` FROM tasks WHERE id = ?`, t.ID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return &NotFoundError{ID: t.ID}
+	}
+	if err != nil {
+		return err
+	}
+	stampTask(&t, old, true, time.Now())
+
+	args := taskArgs(t)
+	if _, err := tx.Exec(`UPDATE tasks SET `+taskAssignments+` WHERE id = ?`, append(args[1:], t.ID)...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteTask удаляет задачу с указанным ID из хранилища.
+// Если задача с таким ID не найдена, возвращает NotFoundError.
+func (s *SQLiteStore) DeleteTask(id int) error {
+	res, err := s.db.Exec(`DELETE FROM tasks WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(res, id)
+}
+
+// MarkTaskDone отмечает задачу с указанным ID как выполненную, а для
+// повторяющейся задачи в той же транзакции добавляет следующее повторение.
+// Если задача с таким ID не найдена, возвращает NotFoundError.
+func (s *SQLiteStore) MarkTaskDone(id int) error {
+	return s.Modify(func(tasks []task.Task) ([]task.Task, error) {
+		return markDone(tasks, id, time.Now())
+	})
+}
+
+// OverwriteTasks полностью заменяет список задач в хранилище.
+// Замена выполняется в одной транзакции: либо весь список, либо ничего.
+func (s *SQLiteStore) OverwriteTasks(tasks []task.Task) error {
+	return s.Modify(func([]task.Task) ([]task.Task, error) {
+		return tasks, nil
+	})
+}
+
+// Modify загружает список задач, передаёт его в fn и сохраняет результат
+// в одной транзакции. Записываются только изменённые, новые и удалённые
+// задачи, поэтому массовые операции не переписывают всю таблицу.
+// Если fn вернула ошибку, хранилище не изменяется.
+func (s *SQLiteStore) Modify(fn func(tasks []task.Task) ([]task.Task, error)) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }() // после Commit откат ничего не делает
+
+	current, err := queryTasks(tx, "")
+	if err != nil {
+		return err
+	}
+	snapshot := cloneTasks(current) // fn может изменить current на месте
+	before := tasksByID(snapshot)
+
+	updated, err := fn(current)
+	if err != nil {
+		return err
+	}
+	normalized, err := normalizeTasks(updated)
+	if err != nil {
+		return err
+	}
+	stampTasks(snapshot, normalized, time.Now())
+
+	for _, t := range normalized {
+		if old, ok := before[t.ID]; ok {
+			delete(before, t.ID)
+			if reflect.DeepEqual(old, t) {
+				continue // задача не менялась — не трогаем строку
+			}
+		}
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO tasks (`+taskColumns+`) VALUES (`+taskPlaceholders+`)`, taskArgs(t)...); err != nil {
+			return err
+		}
+	}
+
+	// Всё, что осталось в before, отсутствует в новом списке — удаляем.
+	for id := range before {
+		if _, err := tx.Exec(`DELETE FROM tasks WHERE id = ?`, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Close закрывает соединение с базой данных.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// checkAffected — приватная функция, превращает "0 изменённых строк"
+// в ту же ошибку NotFoundError, что возвращает JSONStore.
+func checkAffected(res sql.Result, id int) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &NotFoundError{ID: id}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// TestSQLiteStore_OverwriteAndReopen проверяет полную замену списка
+// и то, что данные сохраняются между открытиями базы.
+func TestSQLiteStore_OverwriteAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore вернул ошибку: %v", err)
+	}
+	err = store.OverwriteTasks([]task.Task{
+		{ID: 5, Title: "Пятая", CreatedAt: time.Now()},
+		{ID: 7, Title: "Седьмая", Status: task.StatusDone, CreatedAt: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("OverwriteTasks вернул ошибку: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close вернул ошибку: %v", err)
+	}
+
+	// Повторное открытие не должно заново применять миграции и терять данные.
+	store, err = NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("повторный NewSQLiteStore вернул ошибку: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	if _, err := store.AddTask(task.Task{Title: "Новая", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+
+	tasks, err := store.ListTasks()
+	if err != nil {
+		t.Fatalf("ListTasks вернул ошибку: %v", err)
+	}
+	if len(tasks) != 3 || tasks[0].ID != 5 || tasks[1].ID != 7 || tasks[2].ID != 8 {
+		t.Errorf("неверный список после перезаписи: %+v", tasks)
+	}
+}
+
+// TestSQLiteStore_MigratesImportant проверяет, что база первой версии схемы
+// с флагами important и completed переводится на приоритеты и статусы
+// без потери данных.
+func TestSQLiteStore_MigratesImportant(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("не удалось открыть базу: %v", err)
+	}
+	for _, stmt := range []string{
+		migrations[0],
+		`PRAGMA user_version = 1`,
+		`INSERT INTO tasks (id, title, completed, created_at, important) VALUES
+			(1, 'Важная', 0, '2025-01-01T10:00:00Z', 1),
+			(2, 'Обычная', 1, '2025-01-01T11:00:00Z', 0)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("не удалось подготовить базу: %v", err)
+		}
+	}
+	_ = db.Close()
+
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore вернул ошибку: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	tasks, err := store.ListTasks()
+	if err != nil {
+		t.Fatalf("ListTasks вернул ошибку: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Priority != task.PriorityHigh || tasks[1].Priority != task.PriorityNormal || tasks[0].Status != task.StatusTodo || tasks[1].Status != task.StatusDone {
+		t.Errorf("неверные задачи после миграции: %+v", tasks)
+	}
+}
@@ -1,12 +1,168 @@
 package storage
 
-import "github.com/zen-flo/todo-cli/internal/task"
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
 
 // Storage — интерфейс для работы с задачами.
 // Это позволит легко подменять хранилище (например, JSON → SQLite).
+//
+// Все реализации обязаны вести себя одинаково (см. пакет storagetest):
+//   - AddTask игнорирует переданный ID и назначает максимальный ID + 1;
+//   - ListTasks возвращает задачи по возрастанию ID;
+//   - операции над отсутствующей задачей возвращают ошибку, для которой
+//     errors.Is(err, ErrNotFound) == true.
 type Storage interface {
-	AddTask(t task.Task) error       // добавить задачу
-	ListTasks() ([]task.Task, error) // получить список задач
-	UpdateTask(t task.Task) error    // обновить задачу
-	DeleteTask(id int) error         // удалить задачу
+	AddTask(t task.Task) (task.Task, error)                       // добавить задачу и вернуть её с назначенным ID
+	GetTask(id int) (task.Task, error)                            // получить задачу по ID
+	ListTasks() ([]task.Task, error)                              // получить список задач
+	UpdateTask(t task.Task) error                                 // заменить задачу с тем же ID
+	DeleteTask(id int) error                                      // удалить задачу
+	MarkTaskDone(id int) error                                    // отметить задачу выполненной
+	OverwriteTasks(tasks []task.Task) error                       // заменить весь список задач
+	Modify(fn func(tasks []task.Task) ([]task.Task, error)) error // атомарно изменить весь список задач
+	Close() error                                                 // освободить ресурсы хранилища
+}
+
+// Проверяем на этапе компиляции, что все реализации удовлетворяют интерфейсу.
+var (
+	_ Storage = (*JSONStore)(nil)
+	_ Storage = (*SQLiteStore)(nil)
+	_ Storage = (*MemoryStore)(nil)
+)
+
+// ErrNotFound — общая ошибка "задача не найдена".
+// Проверяется через errors.Is независимо от реализации хранилища.
+var ErrNotFound = errors.New("задача не найдена")
+
+// NotFoundError — ошибка отсутствия задачи с конкретным ID.
+type NotFoundError struct {
+	ID int // ID задачи, которую не удалось найти
+}
+
+// Error возвращает текст ошибки для пользователя.
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("задача с ID %d не найдена", e.ID)
+}
+
+// Is позволяет сравнивать NotFoundError с ErrNotFound через errors.Is.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// normalizeTasks — приватная функция, приводит список задач к виду,
+// в котором его сохраняют все хранилища: задачи отсортированы по ID,
+// задачам с нулевым ID назначены новые ID, дубликаты ID запрещены.
+// Исходный слайс не изменяется.
+func normalizeTasks(tasks []task.Task) ([]task.Task, error) {
+	result := make([]task.Task, 0, len(tasks))
+	maxID := 0
+	seen := make(map[int]bool, len(tasks))
+	for _, t := range tasks {
+		if t.ID < 0 {
+			return nil, fmt.Errorf("некорректный ID задачи: %d", t.ID)
+		}
+		if t.ID > 0 {
+			if seen[t.ID] {
+				return nil, fmt.Errorf("дублирующийся ID задачи: %d", t.ID)
+			}
+			seen[t.ID] = true
+		}
+		if t.ID > maxID {
+			maxID = t.ID
+		}
+		result = append(result, t.Clone())
+	}
+
+	// Новым задачам (ID = 0) выдаём ID по порядку следования.
+	for i := range result {
+		if result[i].ID == 0 {
+			maxID++
+			result[i].ID = maxID
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// nextID — приватная функция, возвращает ID для новой задачи.
+func nextID(tasks []task.Task) int {
+	maxID := 0
+	for _, t := range tasks {
+		if t.ID > maxID {
+			maxID = t.ID
+		}
+	}
+	return maxID + 1
+}
+
+// markDone — приватная функция, отмечает задачу id выполненной на момент now
+// и добавляет в конец списка её следующее повторение (с нулевым ID),
+// если задача повторяющаяся. Общая реализация MarkTaskDone для всех хранилищ.
+func markDone(tasks []task.Task, id int, now time.Time) ([]task.Task, error) {
+	for i := range tasks {
+		if tasks[i].ID == id {
+			if next, ok := tasks[i].Complete(now); ok {
+				tasks = append(tasks, next)
+			}
+			return tasks, nil
+		}
+	}
+	return nil, &NotFoundError{ID: id}
+}
+
+// stampTask — приватная функция, проставляет отметки времени задаче t,
+// которая заменяет задачу old (existed = false — задача новая), на момент now.
+// Общая логика всех хранилищ:
+//   - UpdatedAt новой задачи без отметки и изменившейся задачи становится now,
+//     если вызывающий не задал его сам (например, при отмене операции);
+//   - CompletedAt становится now, когда задача переходит в статус done,
+//     и сбрасывается, когда она из него выходит.
+func stampTask(t *task.Task, old task.Task, existed bool, now time.Time) {
+	switch {
+	case t.Status != task.StatusDone:
+		t.CompletedAt = time.Time{}
+	case t.CompletedAt.IsZero() && (!existed || old.Status != task.StatusDone):
+		t.CompletedAt = now
+	}
+
+	if !existed {
+		if t.UpdatedAt.IsZero() {
+			t.UpdatedAt = now
+		}
+		return
+	}
+	if !t.UpdatedAt.Equal(old.UpdatedAt) {
+		return // отметку задал вызывающий
+	}
+	// Сравниваем содержимое, не считая самих отметок времени
+	old.CompletedAt = t.CompletedAt
+	if !sameTask(old, *t) {
+		t.UpdatedAt = now
+	}
+}
+
+// stampTasks — приватная функция, проставляет отметки времени задачам
+// after — новому состоянию списка before (см. stampTask).
+func stampTasks(before, after []task.Task, now time.Time) {
+	old := tasksByID(before)
+	for i := range after {
+		prev, existed := old[after[i].ID]
+		stampTask(&after[i], prev, existed, now)
+	}
+}
+
+// cloneTasks — приватная функция, возвращает глубокую копию списка задач.
+func cloneTasks(tasks []task.Task) []task.Task {
+	result := make([]task.Task, len(tasks))
+	for i, t := range tasks {
+		result[i] = t.Clone()
+	}
+	return result
 }
@@ -0,0 +1,486 @@
+// Package storagetest содержит общий набор тестов соответствия для
+// реализаций storage.Storage. Любое хранилище (JSON, в памяти, SQL)
+// должно проходить его, чтобы команды вели себя одинаково
+// независимо от выбранного backend.
+//
+// Пример использования:
+//
+//	func TestJSONStoreConformance(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T) storage.Storage {
+//			return storage.NewJSONStore(filepath.Join(t.TempDir(), "tasks.json"))
+//		})
+//	}
+package storagetest
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/storage"
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// Factory создаёт новое пустое хранилище для одного подтеста.
+// Освобождать ресурсы хранилища должна сама фабрика (например, через t.Cleanup).
+type Factory func(t *testing.T) storage.Storage
+
+// Run запускает все проверки соответствия для хранилища, созданного фабрикой.
+// Каждая проверка получает собственное пустое хранилище.
+func Run(t *testing.T, newStore Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s storage.Storage)
+	}{
+		{"EmptyList", testEmptyList},
+		{"AddAssignsIDs", testAddAssignsIDs},
+		{"AddAfterDeleteUsesMaxID", testAddAfterDeleteUsesMaxID},
+		{"GetTask", testGetTask},
+		{"UpdateTask", testUpdateTask},
+		{"DeleteTask", testDeleteTask},
+		{"MarkTaskDone", testMarkTaskDone},
+		{"MarkTaskDoneRecurring", testMarkTaskDoneRecurring},
+		{"Timestamps", testTimestamps},
+		{"NotFound", testNotFound},
+		{"OverwriteOrdersByID", testOverwriteOrdersByID},
+		{"OverwriteRejectsDuplicates", testOverwriteRejectsDuplicates},
+		{"Modify", testModify},
+		{"ModifyErrorKeepsData", testModifyErrorKeepsData},
+		{"PreservesFields", testPreservesFields},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStore(t))
+		})
+	}
+}
+
+// mustAdd добавляет задачи и возвращает их с назначенными ID.
+func mustAdd(t *testing.T, s storage.Storage, titles ...string) []task.Task {
+	t.Helper()
+
+	added := make([]task.Task, 0, len(titles))
+	for _, title := range titles {
+		created, err := s.AddTask(task.Task{Title: title, CreatedAt: time.Now()})
+		if err != nil {
+			t.Fatalf("AddTask(%q) вернул ошибку: %v", title, err)
+		}
+		added = append(added, created)
+	}
+	return added
+}
+
+// mustList возвращает список задач или завершает тест.
+func mustList(t *testing.T, s storage.Storage) []task.Task {
+	t.Helper()
+
+	tasks, err := s.ListTasks()
+	if err != nil {
+		t.Fatalf("ListTasks вернул ошибку: %v", err)
+	}
+	return tasks
+}
+
+// ids возвращает ID задач в исходном порядке.
+func ids(tasks []task.Task) []int {
+	result := make([]int, 0, len(tasks))
+	for _, t := range tasks {
+		result = append(result, t.ID)
+	}
+	return result
+}
+
+// equalIDs сравнивает два списка ID поэлементно.
+func equalIDs(got, want []int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testEmptyList(t *testing.T, s storage.Storage) {
+	tasks := mustList(t, s)
+	if tasks == nil || len(tasks) != 0 {
+		t.Errorf("ожидался пустой не-nil список, получено %#v", tasks)
+	}
+}
+
+func testAddAssignsIDs(t *testing.T, s storage.Storage) {
+	created := time.Date(2025, 9, 24, 19, 48, 31, 0, time.UTC)
+
+	// Переданный ID должен игнорироваться.
+	first, err := s.AddTask(task.Task{ID: 42, Title: "Первая", CreatedAt: created, Priority: task.PriorityHigh})
+	if err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+	if first.ID != 1 {
+		t.Errorf("ожидался ID 1, получено %d", first.ID)
+	}
+
+	second := mustAdd(t, s, "Вторая")[0]
+	if second.ID != 2 {
+		t.Errorf("ожидался ID 2, получено %d", second.ID)
+	}
+
+	got, err := s.GetTask(1)
+	if err != nil {
+		t.Fatalf("GetTask вернул ошибку: %v", err)
+	}
+	if got.Title != "Первая" || got.Priority != task.PriorityHigh || got.Status == task.StatusDone || !got.CreatedAt.Equal(created) {
+		t.Errorf("задача сохранена неверно: %+v", got)
+	}
+}
+
+func testAddAfterDeleteUsesMaxID(t *testing.T, s storage.Storage) {
+	mustAdd(t, s, "1", "2", "3")
+
+	// Удаление задачи из середины не освобождает ID: новая получает max + 1.
+	if err := s.DeleteTask(2); err != nil {
+		t.Fatalf("DeleteTask вернул ошибку: %v", err)
+	}
+	if got := mustAdd(t, s, "4")[0].ID; got != 4 {
+		t.Errorf("ожидался ID 4, получено %d", got)
+	}
+
+	// Удаление последней задачи освобождает её ID.
+	if err := s.DeleteTask(4); err != nil {
+		t.Fatalf("DeleteTask вернул ошибку: %v", err)
+	}
+	if got := mustAdd(t, s, "4 снова")[0].ID; got != 4 {
+		t.Errorf("ожидался ID 4, получено %d", got)
+	}
+}
+
+func testGetTask(t *testing.T, s storage.Storage) {
+	mustAdd(t, s, "Первая", "Вторая")
+
+	got, err := s.GetTask(2)
+	if err != nil {
+		t.Fatalf("GetTask вернул ошибку: %v", err)
+	}
+	if got.ID != 2 || got.Title != "Вторая" {
+		t.Errorf("GetTask вернул не ту задачу: %+v", got)
+	}
+}
+
+func testUpdateTask(t *testing.T, s storage.Storage) {
+	orig := mustAdd(t, s, "Старое название", "Соседняя")[0]
+
+	orig.Title = "Новое название"
+	orig.Priority = task.PriorityHigh
+	orig.Status = task.StatusDone
+	if err := s.UpdateTask(orig); err != nil {
+		t.Fatalf("UpdateTask вернул ошибку: %v", err)
+	}
+
+	got, err := s.GetTask(orig.ID)
+	if err != nil {
+		t.Fatalf("GetTask вернул ошибку: %v", err)
+	}
+	if got.Title != "Новое название" || got.Priority != task.PriorityHigh || got.Status != task.StatusDone || !got.CreatedAt.Equal(orig.CreatedAt) {
+		t.Errorf("обновление не сохранилось: %+v", got)
+	}
+
+	other, err := s.GetTask(2)
+	if err != nil {
+		t.Fatalf("GetTask вернул ошибку: %v", err)
+	}
+	if other.Title != "Соседняя" {
+		t.Errorf("обновление затронуло другую задачу: %+v", other)
+	}
+}
+
+func testDeleteTask(t *testing.T, s storage.Storage) {
+	mustAdd(t, s, "1", "2", "3")
+
+	if err := s.DeleteTask(2); err != nil {
+		t.Fatalf("DeleteTask вернул ошибку: %v", err)
+	}
+	if got := ids(mustList(t, s)); !equalIDs(got, []int{1, 3}) {
+		t.Errorf("после удаления ожидались ID [1 3], получено %v", got)
+	}
+}
+
+func testMarkTaskDone(t *testing.T, s storage.Storage) {
+	mustAdd(t, s, "1", "2")
+
+	// Повторная отметка не является ошибкой.
+	for i := 0; i < 2; i++ {
+		if err := s.MarkTaskDone(1); err != nil {
+			t.Fatalf("MarkTaskDone вернул ошибку: %v", err)
+		}
+	}
+
+	tasks := mustList(t, s)
+	if tasks[0].Status != task.StatusDone || tasks[1].Status == task.StatusDone {
+		t.Errorf("неверные статусы после MarkTaskDone: %+v", tasks)
+	}
+}
+
+func testMarkTaskDoneRecurring(t *testing.T, s storage.Storage) {
+	due := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	recur := task.Recurrence{Freq: task.FreqDaily, Interval: 1}
+	created, err := s.AddTask(task.Task{Title: "Отчёт", CreatedAt: time.Now(), DueAt: due, Recur: recur, Tags: []string{"work"}})
+	if err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+
+	// Повторная отметка не должна создавать ещё одно повторение.
+	for i := 0; i < 2; i++ {
+		if err := s.MarkTaskDone(created.ID); err != nil {
+			t.Fatalf("MarkTaskDone вернул ошибку: %v", err)
+		}
+	}
+
+	tasks := mustList(t, s)
+	if len(tasks) != 2 {
+		t.Fatalf("ожидались выполненная задача и её повторение, получено %+v", tasks)
+	}
+	done, next := tasks[0], tasks[1]
+	if done.Status != task.StatusDone || !done.Recur.IsZero() {
+		t.Errorf("выполненная задача должна потерять правило повторения: %+v", done)
+	}
+	if next.Status == task.StatusDone || next.Recur != recur || next.Title != "Отчёт" || len(next.Tags) != 1 {
+		t.Errorf("неверное следующее повторение: %+v", next)
+	}
+	if !next.DueAt.After(time.Now()) || next.DueAt.Sub(due)%(24*time.Hour) != 0 {
+		t.Errorf("срок повторения должен сдвинуться на целые дни в будущее: %v", next.DueAt)
+	}
+}
+
+func testTimestamps(t *testing.T, s storage.Storage) {
+	start := time.Now()
+	added := mustAdd(t, s, "1", "2")
+	if added[0].UpdatedAt.Before(start) || !added[0].CompletedAt.IsZero() {
+		t.Fatalf("AddTask должен проставить время изменения: %+v", added[0])
+	}
+	// Время должно успеть сдвинуться, чтобы новые отметки отличались от прежних
+	time.Sleep(time.Millisecond)
+
+	if err := s.MarkTaskDone(1); err != nil {
+		t.Fatalf("MarkTaskDone вернул ошибку: %v", err)
+	}
+	done, _ := s.GetTask(1)
+	if done.CompletedAt.IsZero() || !done.UpdatedAt.After(added[0].UpdatedAt) {
+		t.Errorf("MarkTaskDone должен проставить время выполнения и изменения: %+v", done)
+	}
+	time.Sleep(time.Millisecond)
+
+	// Массовое изменение трогает только изменившиеся задачи
+	if err := s.Modify(func(tasks []task.Task) ([]task.Task, error) {
+		tasks[0].Status = task.StatusTodo
+		return tasks, nil
+	}); err != nil {
+		t.Fatalf("Modify вернул ошибку: %v", err)
+	}
+	tasks := mustList(t, s)
+	if !tasks[0].CompletedAt.IsZero() || !tasks[0].UpdatedAt.After(done.UpdatedAt) {
+		t.Errorf("возврат в работу должен сбросить время выполнения: %+v", tasks[0])
+	}
+	if !tasks[1].UpdatedAt.Equal(added[1].UpdatedAt) {
+		t.Errorf("неизменённая задача получила новое время изменения: %+v", tasks[1])
+	}
+
+	// UpdateTask без изменений содержимого не сдвигает время изменения
+	if err := s.UpdateTask(tasks[1]); err != nil {
+		t.Fatalf("UpdateTask вернул ошибку: %v", err)
+	}
+	if got, _ := s.GetTask(2); !got.UpdatedAt.Equal(added[1].UpdatedAt) {
+		t.Errorf("UpdateTask без изменений сдвинул время изменения: %v", got.UpdatedAt)
+	}
+	tasks[1].Title = "2 (изменена)"
+	if err := s.UpdateTask(tasks[1]); err != nil {
+		t.Fatalf("UpdateTask вернул ошибку: %v", err)
+	}
+	if got, _ := s.GetTask(2); !got.UpdatedAt.After(added[1].UpdatedAt) {
+		t.Errorf("UpdateTask должен обновить время изменения: %v", got.UpdatedAt)
+	}
+}
+
+func testNotFound(t *testing.T, s storage.Storage) {
+	mustAdd(t, s, "1")
+
+	_, getErr := s.GetTask(999)
+	checks := map[string]error{
+		"GetTask":      getErr,
+		"UpdateTask":   s.UpdateTask(task.Task{ID: 999, Title: "Нет такой"}),
+		"DeleteTask":   s.DeleteTask(999),
+		"MarkTaskDone": s.MarkTaskDone(999),
+	}
+	for op, err := range checks {
+		if !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("%s: ожидалась ошибка ErrNotFound, получено %v", op, err)
+			continue
+		}
+		var nf *storage.NotFoundError
+		if !errors.As(err, &nf) || nf.ID != 999 {
+			t.Errorf("%s: ожидалась NotFoundError с ID 999, получено %v", op, err)
+		}
+	}
+
+	// Неудачные операции не должны менять данные.
+	if got := ids(mustList(t, s)); !equalIDs(got, []int{1}) {
+		t.Errorf("ожидались ID [1], получено %v", got)
+	}
+}
+
+func testOverwriteOrdersByID(t *testing.T, s storage.Storage) {
+	mustAdd(t, s, "будет заменена")
+
+	err := s.OverwriteTasks([]task.Task{
+		{ID: 7, Title: "Седьмая", CreatedAt: time.Now()},
+		{ID: 0, Title: "Новая", CreatedAt: time.Now()},
+		{ID: 3, Title: "Третья", Status: task.StatusDone, CreatedAt: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("OverwriteTasks вернул ошибку: %v", err)
+	}
+
+	tasks := mustList(t, s)
+	if got := ids(tasks); !equalIDs(got, []int{3, 7, 8}) {
+		t.Fatalf("ожидались ID [3 7 8], получено %v", got)
+	}
+	if tasks[2].Title != "Новая" || tasks[0].Status != task.StatusDone {
+		t.Errorf("неверное содержимое после перезаписи: %+v", tasks)
+	}
+}
+
+func testOverwriteRejectsDuplicates(t *testing.T, s storage.Storage) {
+	mustAdd(t, s, "1")
+
+	err := s.OverwriteTasks([]task.Task{
+		{ID: 5, Title: "a", CreatedAt: time.Now()},
+		{ID: 5, Title: "b", CreatedAt: time.Now()},
+	})
+	if err == nil {
+		t.Fatalf("ожидалась ошибка для дублирующихся ID")
+	}
+	if got := ids(mustList(t, s)); !equalIDs(got, []int{1}) {
+		t.Errorf("после ошибки данные изменились: %v", got)
+	}
+}
+
+func testModify(t *testing.T, s storage.Storage) {
+	mustAdd(t, s, "1", "2", "3")
+
+	err := s.Modify(func(tasks []task.Task) ([]task.Task, error) {
+		var result []task.Task
+		for _, tsk := range tasks {
+			if tsk.ID == 2 {
+				continue // удаляем
+			}
+			tsk.Status = task.StatusDone
+			result = append(result, tsk)
+		}
+		return append(result, task.Task{Title: "добавлена", CreatedAt: time.Now()}), nil
+	})
+	if err != nil {
+		t.Fatalf("Modify вернул ошибку: %v", err)
+	}
+
+	tasks := mustList(t, s)
+	if got := ids(tasks); !equalIDs(got, []int{1, 3, 4}) {
+		t.Fatalf("ожидались ID [1 3 4], получено %v", got)
+	}
+	if tasks[0].Status != task.StatusDone || tasks[1].Status != task.StatusDone || tasks[2].Status == task.StatusDone || tasks[2].Title != "добавлена" {
+		t.Errorf("неверное содержимое после Modify: %+v", tasks)
+	}
+}
+
+func testModifyErrorKeepsData(t *testing.T, s storage.Storage) {
+	mustAdd(t, s, "1", "2")
+
+	wantErr := errors.New("отмена")
+	err := s.Modify(func(tasks []task.Task) ([]task.Task, error) {
+		tasks[0].Title = "изменено"
+		return nil, wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("ожидалась ошибка из fn, получено %v", err)
+	}
+
+	tasks := mustList(t, s)
+	if len(tasks) != 2 || tasks[0].Title != "1" {
+		t.Errorf("после ошибки в Modify данные изменились: %+v", tasks)
+	}
+}
+
+// fullTask возвращает задачу, у которой заполнены все поля,
+// чтобы проверить, что хранилище ничего не теряет.
+func fullTask() task.Task {
+	return task.Task{
+		Title:       "Все поля",
+		Status:      task.StatusDone,
+		CreatedAt:   time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC),
+		UpdatedAt:   time.Date(2025, 1, 4, 8, 0, 0, 0, time.UTC),
+		CompletedAt: time.Date(2025, 1, 4, 7, 59, 0, 0, time.FixedZone("MSK", 3*60*60)),
+		Priority:    task.PriorityUrgent,
+		DueAt:       time.Date(2025, 2, 3, 18, 30, 0, 0, time.FixedZone("MSK", 3*60*60)),
+		Tags:        []string{"backend", "infra"},
+		Project:     "work.release",
+		Archived:    true,
+		ParentID:    1,
+		BlockedBy:   []int{1, 3},
+		Recur:       task.Recurrence{Freq: task.FreqWeekly, Interval: 2, Weekdays: 1<<time.Monday | 1<<time.Thursday},
+		TimeLog: []task.Interval{
+			{Start: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 2, 10, 30, 0, 0, time.UTC)},
+			{Start: time.Date(2025, 1, 3, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))},
+		},
+		Notes: "Первая строка\n\n- пункт с \"кавычками\"\n",
+	}
+}
+
+// assertSameTask сравнивает задачи по JSON-представлению.
+func assertSameTask(t *testing.T, got, want task.Task) {
+	t.Helper()
+
+	g, _ := json.Marshal(got)
+	w, _ := json.Marshal(want)
+	if string(g) != string(w) {
+		t.Errorf("задача изменилась при сохранении:\nожидалось %s\nполучено  %s", w, g)
+	}
+}
+
+func testPreservesFields(t *testing.T, s storage.Storage) {
+	want := fullTask()
+	created, err := s.AddTask(want)
+	if err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+	want.ID = created.ID
+
+	got, err := s.GetTask(created.ID)
+	if err != nil {
+		t.Fatalf("GetTask вернул ошибку: %v", err)
+	}
+	assertSameTask(t, got, want)
+
+	// То же через Modify и UpdateTask. Отметку изменения, заданную
+	// вызывающим, хранилище не перезаписывает.
+	want.Title = "Все поля (изменена)"
+	want.UpdatedAt = want.UpdatedAt.Add(time.Hour)
+	if err := s.UpdateTask(want); err != nil {
+		t.Fatalf("UpdateTask вернул ошибку: %v", err)
+	}
+	other := fullTask()
+	other.ID = want.ID + 1
+	if err := s.Modify(func(tasks []task.Task) ([]task.Task, error) {
+		return append(tasks, other), nil
+	}); err != nil {
+		t.Fatalf("Modify вернул ошибку: %v", err)
+	}
+
+	tasks := mustList(t, s)
+	if len(tasks) != 2 {
+		t.Fatalf("ожидалось 2 задачи, получено %d", len(tasks))
+	}
+	assertSameTask(t, tasks[0], want)
+	assertSameTask(t, tasks[1], other)
+}
@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// TrashEntry — удалённая задача в корзине.
+type TrashEntry struct {
+	Task      task.Task `json:"task"`       // задача в момент удаления
+	DeletedAt time.Time `json:"deleted_at"` // когда задача удалена
+}
+
+// NotInTrashError — в корзине нет задачи с указанным ID.
+type NotInTrashError struct {
+	ID int // ID задачи, которую искали в корзине
+}
+
+// Error возвращает текст ошибки для пользователя.
+func (e *NotInTrashError) Error() string {
+	return fmt.Sprintf("задачи с ID %d нет в корзине", e.ID)
+}
+
+// Trash — корзина: отдельный JSON-файл с удалёнными задачами, из которого
+// их можно восстановить. Запись в файл атомарная и защищена межпроцессной
+// блокировкой, как и файл журнала операций.
+type Trash struct {
+	Path        string        // путь к файлу корзины
+	LockTimeout time.Duration // сколько ждать блокировку файла
+}
+
+// NewTrash — конструктор Trash с настройками по умолчанию.
+func NewTrash(path string) *Trash {
+	return &Trash{Path: path, LockTimeout: DefaultLockTimeout}
+}
+
+// load — приватный метод, читает записи из файла корзины.
+// Отсутствующий или пустой файл означает пустую корзину.
+func (tr *Trash) load() ([]TrashEntry, error) {
+	data, err := os.ReadFile(tr.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []TrashEntry{}, nil
+		}
+		return nil, err
+	}
+	if len(data) == 0 {
+		return []TrashEntry{}, nil
+	}
+
+	var entries []TrashEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// update — приватный метод, выполняет цикл "чтение → fn → запись"
+// под блокировкой файла корзины.
+func (tr *Trash) update(fn func(entries []TrashEntry) ([]TrashEntry, error)) error {
+	timeout := tr.LockTimeout
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+	l, err := acquireLock(tr.Path+".lock", timeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.release() }()
+
+	entries, err := tr.load()
+	if err != nil {
+		return err
+	}
+	entries, err = fn(entries)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(tr.Path, data, 0644)
+}
+
+// List возвращает содержимое корзины в порядке удаления задач.
+func (tr *Trash) List() ([]TrashEntry, error) {
+	return tr.load()
+}
+
+// Find возвращает последнюю удалённую задачу с указанным ID.
+// Если такой задачи в корзине нет, возвращает NotInTrashError.
+func (tr *Trash) Find(id int) (TrashEntry, error) {
+	entries, err := tr.load()
+	if err != nil {
+		return TrashEntry{}, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Task.ID == id {
+			return entries[i], nil
+		}
+	}
+	return TrashEntry{}, &NotInTrashError{ID: id}
+}
+
+// Add кладёт задачи в корзину с временем удаления deletedAt.
+func (tr *Trash) Add(deletedAt time.Time, tasks ...task.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	return tr.update(func(entries []TrashEntry) ([]TrashEntry, error) {
+		for _, t := range tasks {
+			entries = append(entries, TrashEntry{Task: t, DeletedAt: deletedAt})
+		}
+		return entries, nil
+	})
+}
+
+// Remove убирает из корзины записи, для которых match возвращает true,
+// и возвращает их.
+func (tr *Trash) Remove(match func(TrashEntry) bool) ([]TrashEntry, error) {
+	var removed []TrashEntry
+	err := tr.update(func(entries []TrashEntry) ([]TrashEntry, error) {
+		kept := make([]TrashEntry, 0, len(entries))
+		for _, e := range entries {
+			if match(e) {
+				removed = append(removed, e)
+			} else {
+				kept = append(kept, e)
+			}
+		}
+		return kept, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// Purge окончательно удаляет задачи, попавшие в корзину раньше before
+// (все задачи, если before нулевое), и возвращает их.
+func (tr *Trash) Purge(before time.Time) ([]TrashEntry, error) {
+	return tr.Remove(func(e TrashEntry) bool {
+		return before.IsZero() || e.DeletedAt.Before(before)
+	})
+}
+
+// Restore возвращает задачу с указанным ID из корзины в список и
+// записывает операцию "restore". Если ID уже занят, задаче назначается
+// новый ID; ссылки на родителя и блокирующие задачи, которых больше нет,
+// сбрасываются. Возвращает восстановленную задачу.
+func (s *JournaledStore) Restore(id int) (task.Task, error) {
+	if s.Trash == nil {
+		return task.Task{}, &NotInTrashError{ID: id}
+	}
+	entry, err := s.Trash.Find(id)
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	var restored task.Task
+	err = s.ModifyAs("restore", func(tasks []task.Task) ([]task.Task, error) {
+		t := entry.Task
+		present := tasksByID(tasks)
+		if _, taken := present[t.ID]; taken {
+			issued, err := s.issuedID(tasks)
+			if err != nil {
+				return nil, err
+			}
+			t.ID = issued + 1
+		}
+		if _, ok := present[t.ParentID]; !ok {
+			t.ParentID = 0
+		}
+		for _, blocker := range slices.Clone(t.BlockedBy) {
+			if _, ok := present[blocker]; !ok {
+				t.RemoveBlocker(blocker)
+			}
+		}
+		restored = t
+		return append(tasks, t), nil
+	})
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	_, err = s.Trash.Remove(func(e TrashEntry) bool {
+		return e.Task.ID == entry.Task.ID && e.DeletedAt.Equal(entry.DeletedAt)
+	})
+	return restored, err
+}
@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zen-flo/todo-cli/internal/task"
+)
+
+// newTestTrashStore создаёт JournaledStore поверх MemoryStore
+// с журналом и корзиной во временной директории теста.
+func newTestTrashStore(t *testing.T) *JournaledStore {
+	t.Helper()
+	s := newTestJournaledStore(t)
+	s.Trash = NewTrash(filepath.Join(t.TempDir(), "tasks.trash"))
+	return s
+}
+
+// trashIDs возвращает ID задач в корзине по порядку удаления.
+func trashIDs(t *testing.T, s *JournaledStore) []int {
+	t.Helper()
+
+	entries, err := s.Trash.List()
+	if err != nil {
+		t.Fatalf("List вернул ошибку: %v", err)
+	}
+	ids := make([]int, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.Task.ID)
+	}
+	return ids
+}
+
+// TestTrash_DeleteAndClear проверяет, что удалённые задачи попадают
+// в корзину со временем удаления, а undo удаления убирает их оттуда.
+func TestTrash_DeleteAndClear(t *testing.T) {
+	s := newTestTrashStore(t)
+	for _, title := range []string{"Первая", "Вторая", "Третья"} {
+		if _, err := s.AddTask(task.Task{Title: title}); err != nil {
+			t.Fatalf("AddTask вернул ошибку: %v", err)
+		}
+	}
+
+	start := time.Now()
+	if err := s.DeleteTask(2); err != nil {
+		t.Fatalf("DeleteTask вернул ошибку: %v", err)
+	}
+	err := s.ModifyAs("clear", func(tasks []task.Task) ([]task.Task, error) {
+		return tasks[1:], nil // удаляем задачу 1
+	})
+	if err != nil {
+		t.Fatalf("ModifyAs вернул ошибку: %v", err)
+	}
+	assertTitles(t, s, "Третья")
+
+	entries, err := s.Trash.List()
+	if err != nil {
+		t.Fatalf("List вернул ошибку: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Task.Title != "Вторая" || entries[1].Task.Title != "Первая" {
+		t.Fatalf("в корзине ожидались задачи 2 и 1, получено %+v", entries)
+	}
+	if entries[0].DeletedAt.Before(start) {
+		t.Errorf("время удаления %v раньше начала теста %v", entries[0].DeletedAt, start)
+	}
+
+	// Отмена очистки возвращает задачу из корзины, повтор — снова удаляет.
+	if _, err := s.Undo(); err != nil {
+		t.Fatalf("Undo вернул ошибку: %v", err)
+	}
+	if got := trashIDs(t, s); len(got) != 1 || got[0] != 2 {
+		t.Errorf("после undo в корзине ожидалась задача 2, получено %v", got)
+	}
+	if _, err := s.Redo(); err != nil {
+		t.Fatalf("Redo вернул ошибку: %v", err)
+	}
+	if got := trashIDs(t, s); len(got) != 2 || got[1] != 1 {
+		t.Errorf("после redo в корзине ожидались задачи 2 и 1, получено %v", got)
+	}
+}
+
+// TestTrash_UndoAdd проверяет, что отмена добавления не кладёт задачу
+// в корзину, а повтор после неё не оставляет в корзине лишних записей.
+func TestTrash_UndoAdd(t *testing.T) {
+	s := newTestTrashStore(t)
+	if _, err := s.AddTask(task.Task{Title: "Купить хлеб"}); err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+	if _, err := s.Undo(); err != nil {
+		t.Fatalf("Undo вернул ошибку: %v", err)
+	}
+	assertTitles(t, s)
+	if got := trashIDs(t, s); len(got) != 0 {
+		t.Errorf("после отмены добавления корзина должна быть пуста, получено %v", got)
+	}
+
+	if _, err := s.Redo(); err != nil {
+		t.Fatalf("Redo вернул ошибку: %v", err)
+	}
+	assertTitles(t, s, "Купить хлеб")
+	if got := trashIDs(t, s); len(got) != 0 {
+		t.Errorf("после повтора корзина должна быть пуста, получено %v", got)
+	}
+}
+
+// TestTrash_IDsNotReused проверяет, что ID удалённой задачи не достаётся
+// новой: ни пока задача в корзине, ни после очистки корзины, пока о ней
+// помнит журнал аудита.
+func TestTrash_IDsNotReused(t *testing.T) {
+	s := newTestTrashStore(t)
+	s.Events = NewEventLog(filepath.Join(t.TempDir(), "tasks.history"))
+	add := func(title string) task.Task {
+		t.Helper()
+		created, err := s.AddTask(task.Task{Title: title})
+		if err != nil {
+			t.Fatalf("AddTask вернул ошибку: %v", err)
+		}
+		return created
+	}
+
+	add("Первая")
+	if err := s.DeleteTask(1); err != nil {
+		t.Fatalf("DeleteTask вернул ошибку: %v", err)
+	}
+	if got := add("Другая"); got.ID != 2 {
+		t.Errorf("новая задача получила ID %d, ожидался 2", got.ID)
+	}
+
+	if _, err := s.Trash.Purge(time.Time{}); err != nil {
+		t.Fatalf("Purge вернул ошибку: %v", err)
+	}
+	if err := s.DeleteTask(2); err != nil {
+		t.Fatalf("DeleteTask вернул ошибку: %v", err)
+	}
+	if _, err := s.Trash.Purge(time.Time{}); err != nil {
+		t.Fatalf("Purge вернул ошибку: %v", err)
+	}
+	if got := add("Третья"); got.ID != 3 {
+		t.Errorf("после очистки корзины новая задача получила ID %d, ожидался 3", got.ID)
+	}
+}
+
+// TestTrash_Restore проверяет восстановление задачи: ID сохраняется,
+// если он свободен, иначе назначается новый; ссылки на удалённые
+// задачи сбрасываются.
+func TestTrash_Restore(t *testing.T) {
+	s := newTestTrashStore(t)
+	if _, err := s.AddTask(task.Task{Title: "Родитель"}); err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+	if _, err := s.AddTask(task.Task{Title: "Подзадача", ParentID: 1, BlockedBy: []int{1}}); err != nil {
+		t.Fatalf("AddTask вернул ошибку: %v", err)
+	}
+	err := s.ModifyAs("delete", func(tasks []task.Task) ([]task.Task, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("ModifyAs вернул ошибку: %v", err)
+	}
+
+	// ID 2 свободен, но родителя и блокирующей задачи больше нет.
+	restored, err := s.Restore(2)
+	if err != nil {
+		t.Fatalf("Restore вернул ошибку: %v", err)
+	}
+	if restored.ID != 2 || restored.ParentID != 0 || len(restored.BlockedBy) != 0 {
+		t.Errorf("ожидалась задача 2 без родителя и зависимостей, получено %+v", restored)
+	}
+	if got := trashIDs(t, s); len(got) != 1 || got[0] != 1 {
+		t.Errorf("в корзине ожидалась задача 1, получено %v", got)
+	}
+
+	// ID 1 занят новой задачей — восстановленная получает новый ID.
+	if err := s.OverwriteTasks([]task.Task{{ID: 1, Title: "Новая"}, {ID: 2, Title: "Подзадача"}}); err != nil {
+		t.Fatalf("OverwriteTasks вернул ошибку: %v", err)
+	}
+	restored, err = s.Restore(1)
+	if err != nil {
+		t.Fatalf("Restore вернул ошибку: %v", err)
+	}
+	if restored.ID != 3 || restored.Title != "Родитель" {
+		t.Errorf("ожидалась задача «Родитель» с ID 3, получено %+v", restored)
+	}
+	assertTitles(t, s, "Новая", "Подзадача", "Родитель")
+	if got := trashIDs(t, s); len(got) != 0 {
+		t.Errorf("корзина должна быть пуста, получено %v", got)
+	}
+
+	var notInTrash *NotInTrashError
+	if _, err := s.Restore(1); !errors.As(err, &notInTrash) {
+		t.Errorf("ожидалась NotInTrashError, получено %v", err)
+	}
+}
+
+// TestTrash_Purge проверяет окончательное удаление по возрасту.
+func TestTrash_Purge(t *testing.T) {
+	tr := NewTrash(filepath.Join(t.TempDir(), "tasks.trash"))
+	now := time.Now()
+	if err := tr.Add(now.AddDate(0, 0, -40), task.Task{ID: 1, Title: "Старая"}); err != nil {
+		t.Fatalf("Add вернул ошибку: %v", err)
+	}
+	if err := tr.Add(now.AddDate(0, 0, -1), task.Task{ID: 2, Title: "Свежая"}); err != nil {
+		t.Fatalf("Add вернул ошибку: %v", err)
+	}
+
+	purged, err := tr.Purge(now.AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatalf("Purge вернул ошибку: %v", err)
+	}
+	if len(purged) != 1 || purged[0].Task.ID != 1 {
+		t.Errorf("ожидалось удаление задачи 1, получено %+v", purged)
+	}
+
+	purged, err = tr.Purge(time.Time{})
+	if err != nil {
+		t.Fatalf("Purge вернул ошибку: %v", err)
+	}
+	entries, _ := tr.List()
+	if len(purged) != 1 || len(entries) != 0 {
+		t.Errorf("после полной очистки корзина должна быть пуста, получено %+v", entries)
+	}
+}
+
+// TestTrash_WriteFailureKeepsTasks проверяет, что задачи остаются в списке,
+// если корзину записать не удалось: удаление и redo не сохраняются.
+func TestTrash_WriteFailureKeepsTasks(t *testing.T) {
+	s := newTestTrashStore(t)
+	for _, title := range []string{"Первая", "Вторая"} {
+		if _, err := s.AddTask(task.Task{Title: title}); err != nil {
+			t.Fatalf("AddTask вернул ошибку: %v", err)
+		}
+	}
+	if err := s.DeleteTask(2); err != nil {
+		t.Fatalf("DeleteTask вернул ошибку: %v", err)
+	}
+	if _, err := s.Undo(); err != nil {
+		t.Fatalf("Undo вернул ошибку: %v", err)
+	}
+
+	// Корзина внутри обычного файла: записать её нельзя
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	s.Trash = NewTrash(filepath.Join(blocker, "tasks.trash"))
+
+	if err := s.DeleteTask(1); err == nil {
+		t.Error("DeleteTask должен вернуть ошибку записи корзины")
+	}
+	err := s.ModifyAs("clear", func(tasks []task.Task) ([]task.Task, error) { return nil, nil })
+	if err == nil {
+		t.Error("ModifyAs должен вернуть ошибку записи корзины")
+	}
+	if _, err := s.Redo(); err == nil {
+		t.Error("Redo удаления должен вернуть ошибку записи корзины")
+	}
+	assertTitles(t, s, "Первая", "Вторая")
+}
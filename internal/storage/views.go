@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// View — сохранённое представление: именованный набор аргументов
+// команды list (фильтры, сортировка, запрос).
+type View struct {
+	Name string   `json:"name"` // имя представления
+	Args []string `json:"args"` // аргументы list, например ["--filter=pending", "--sort=due"]
+}
+
+// ViewNotFoundError — представления с таким именем нет.
+type ViewNotFoundError struct {
+	Name string // имя представления
+}
+
+// Error возвращает текст ошибки для пользователя.
+func (e *ViewNotFoundError) Error() string {
+	return fmt.Sprintf("представление %q не найдено", e.Name)
+}
+
+// Views — сохранённые представления в отдельном JSON-файле рядом
+// с хранилищем задач. Запись атомарная и защищена межпроцессной
+// блокировкой, как и файл журнала операций.
+type Views struct {
+	Path        string        // путь к файлу представлений
+	LockTimeout time.Duration // сколько ждать блокировку файла
+}
+
+// NewViews — конструктор Views с настройками по умолчанию.
+func NewViews(path string) *Views {
+	return &Views{Path: path, LockTimeout: DefaultLockTimeout}
+}
+
+// ValidateViewName проверяет имя представления: оно не может быть пустым,
+// начинаться с "-" и содержать пробелы.
+func ValidateViewName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("пустое имя представления")
+	case strings.HasPrefix(name, "-"):
+		return fmt.Errorf("имя представления %q не может начинаться с \"-\"", name)
+	case strings.ContainsAny(name, " \t\n"):
+		return fmt.Errorf("имя представления %q не может содержать пробелы", name)
+	}
+	return nil
+}
+
+// load — приватный метод, читает представления из файла.
+// Отсутствующий или пустой файл означает, что представлений нет.
+func (v *Views) load() ([]View, error) {
+	data, err := os.ReadFile(v.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []View{}, nil
+		}
+		return nil, err
+	}
+	if len(data) == 0 {
+		return []View{}, nil
+	}
+
+	var views []View
+	if err := json.Unmarshal(data, &views); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+// update — приватный метод, выполняет цикл "чтение → fn → запись"
+// под блокировкой файла представлений.
+func (v *Views) update(fn func(views []View) ([]View, error)) error {
+	timeout := v.LockTimeout
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+	l, err := acquireLock(v.Path+".lock", timeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.release() }()
+
+	views, err := v.load()
+	if err != nil {
+		return err
+	}
+	views, err = fn(views)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(views, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(v.Path, data, 0644)
+}
+
+// List возвращает представления по алфавиту.
+func (v *Views) List() ([]View, error) {
+	return v.load()
+}
+
+// Get возвращает представление по имени или ViewNotFoundError.
+func (v *Views) Get(name string) (View, error) {
+	views, err := v.load()
+	if err != nil {
+		return View{}, err
+	}
+	for _, view := range views {
+		if view.Name == name {
+			return view, nil
+		}
+	}
+	return View{}, &ViewNotFoundError{Name: name}
+}
+
+// Save сохраняет представление, заменяя одноимённое.
+// Возвращает true, если представление с таким именем уже было.
+func (v *Views) Save(view View) (bool, error) {
+	if err := ValidateViewName(view.Name); err != nil {
+		return false, err
+	}
+	replaced := false
+	err := v.update(func(views []View) ([]View, error) {
+		i, found := slices.BinarySearchFunc(views, view.Name, func(e View, name string) int {
+			return strings.Compare(e.Name, name)
+		})
+		if found {
+			views[i], replaced = view, true
+			return views, nil
+		}
+		return slices.Insert(views, i, view), nil
+	})
+	return replaced, err
+}
+
+// Delete удаляет представление по имени или возвращает ViewNotFoundError.
+func (v *Views) Delete(name string) error {
+	return v.update(func(views []View) ([]View, error) {
+		i := slices.IndexFunc(views, func(e View) bool { return e.Name == name })
+		if i < 0 {
+			return nil, &ViewNotFoundError{Name: name}
+		}
+		return slices.Delete(views, i, i+1), nil
+	})
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// TestViews проверяет сохранение, замену, порядок и удаление представлений.
+func TestViews(t *testing.T) {
+	v := NewViews(filepath.Join(t.TempDir(), "tasks.json.views"))
+
+	if list, err := v.List(); err != nil || len(list) != 0 {
+		t.Fatalf("без файла представлений ожидался пустой список: %v, %v", list, err)
+	}
+
+	for _, view := range []View{
+		{Name: "work", Args: []string{"--project=work"}},
+		{Name: "inbox", Args: []string{"--filter=pending"}},
+	} {
+		if replaced, err := v.Save(view); err != nil || replaced {
+			t.Fatalf("Save(%s) = %v, %v", view.Name, replaced, err)
+		}
+	}
+	replaced, err := v.Save(View{Name: "work", Args: []string{"--project=work", "--sort=due"}})
+	if err != nil || !replaced {
+		t.Fatalf("повторный Save должен заменить представление: %v, %v", replaced, err)
+	}
+
+	list, err := v.List()
+	if err != nil {
+		t.Fatalf("List вернул ошибку: %v", err)
+	}
+	var names []string
+	for _, view := range list {
+		names = append(names, view.Name)
+	}
+	if !slices.Equal(names, []string{"inbox", "work"}) {
+		t.Errorf("представления должны идти по алфавиту: %v", names)
+	}
+
+	got, err := v.Get("work")
+	if err != nil || !slices.Equal(got.Args, []string{"--project=work", "--sort=due"}) {
+		t.Errorf("Get(work) = %+v, %v", got, err)
+	}
+
+	if err := v.Delete("work"); err != nil {
+		t.Fatalf("Delete вернул ошибку: %v", err)
+	}
+	var notFound *ViewNotFoundError
+	if _, err := v.Get("work"); !errors.As(err, &notFound) {
+		t.Errorf("после удаления ожидалась ViewNotFoundError, получено %v", err)
+	}
+	if err := v.Delete("work"); !errors.As(err, &notFound) {
+		t.Errorf("повторное удаление должно вернуть ViewNotFoundError, получено %v", err)
+	}
+
+	for _, name := range []string{"", "-x", "my view"} {
+		if _, err := v.Save(View{Name: name, Args: []string{"--tree"}}); err == nil {
+			t.Errorf("Save(%q) должен вернуть ошибку имени", name)
+		}
+	}
+}
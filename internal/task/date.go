@@ -0,0 +1,88 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weekdays — сокращённые и полные английские названия дней недели.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// dateLayouts — поддерживаемые форматы абсолютных дат (в местном времени).
+var dateLayouts = []string{"2006-01-02", "2006-01-02 15:04", "2006-01-02T15:04"}
+
+// ParseDate разбирает дату относительно момента now. Поддерживаются:
+//
+//   - ISO-даты: 2025-03-14, 2025-03-14 18:00, RFC 3339;
+//   - слова today, tomorrow, yesterday;
+//   - смещения +3d, +2w, +1m (дни, недели, месяцы), в том числе со знаком минус;
+//   - дни недели mon..sun (или monday..sunday) — ближайший такой день после сегодняшнего.
+//
+// Все формы, кроме тех, где время указано явно, дают полночь
+// соответствующего дня в часовом поясе now.
+func ParseDate(s string, now time.Time) (time.Time, error) {
+	trimmed := strings.TrimSpace(s)
+	value := strings.ToLower(trimmed)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch value {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if wd, ok := weekdays[value]; ok {
+		days := (int(wd) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+
+	if n := len(value); n > 2 && (value[0] == '+' || value[0] == '-') {
+		if count, err := strconv.Atoi(value[1 : n-1]); err == nil {
+			if value[0] == '-' {
+				count = -count
+			}
+			switch value[n-1] {
+			case 'd':
+				return today.AddDate(0, 0, count), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*count), nil
+			case 'm':
+				return today.AddDate(0, count, 0), nil
+			}
+		}
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, trimmed, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, trimmed); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("не удалось разобрать дату %q: используйте 2025-03-14, today, tomorrow, +3d или fri", s)
+}
+
+// HasClock сообщает, указано ли у момента время суток.
+// Полночь считается датой без времени.
+func HasClock(t time.Time) bool {
+	h, m, s := t.Clock()
+	return h != 0 || m != 0 || s != 0 || t.Nanosecond() != 0
+}
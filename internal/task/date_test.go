@@ -0,0 +1,49 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseDate проверяет абсолютные и относительные формы дат.
+func TestParseDate(t *testing.T) {
+	// Среда, 12 марта 2025 года, середина дня
+	now := time.Date(2025, 3, 12, 14, 0, 0, 0, time.Local)
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2025-03-14", day(3, 14)},
+		{"2025-03-14 18:30", time.Date(2025, 3, 14, 18, 30, 0, 0, time.Local)},
+		{"2025-03-14T18:30", time.Date(2025, 3, 14, 18, 30, 0, 0, time.Local)},
+		{"today", day(3, 12)},
+		{"Tomorrow", day(3, 13)},
+		{"yesterday", day(3, 11)},
+		{"+3d", day(3, 15)},
+		{"+2w", day(3, 26)},
+		{"+1m", day(4, 12)},
+		{"-1d", day(3, 11)},
+		{"fri", day(3, 14)},
+		{"monday", day(3, 17)},
+		{"wed", day(3, 19)}, // сегодня среда — берём следующую
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseDate(%q) = %v, %v; ожидалось %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	got, err := ParseDate("2025-03-14T18:30:00Z", now)
+	if err != nil || !got.Equal(time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)) {
+		t.Errorf("RFC 3339 разобран неверно: %v, %v", got, err)
+	}
+
+	for _, bad := range []string{"", "soon", "+d", "+3y", "2025-13-01"} {
+		if _, err := ParseDate(bad, now); err == nil {
+			t.Errorf("ParseDate(%q): ожидалась ошибка", bad)
+		}
+	}
+}
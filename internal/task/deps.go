@@ -0,0 +1,146 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CycleError — добавление зависимости замкнуло бы цикл.
+// Path — цепочка задач, где каждая заблокирована следующей,
+// первая и последняя совпадают.
+type CycleError struct {
+	Path []Task
+}
+
+// Error возвращает цепочку в читаемом виде:
+// #3 "Деплой" → #5 "Миграция" → #3 "Деплой".
+func (e *CycleError) Error() string {
+	parts := make([]string, 0, len(e.Path))
+	for _, t := range e.Path {
+		parts = append(parts, fmt.Sprintf("#%d %q", t.ID, t.Title))
+	}
+	return "цикл зависимостей: " + strings.Join(parts, " → ")
+}
+
+// IsBlockedBy сообщает, зависит ли задача от задачи id.
+func (t Task) IsBlockedBy(id int) bool {
+	_, found := slices.BinarySearch(t.BlockedBy, id)
+	return found
+}
+
+// AddBlocker добавляет зависимость от задачи id, сохраняя список
+// отсортированным и без повторов. Возвращает false, если она уже была.
+func (t *Task) AddBlocker(id int) bool {
+	i, found := slices.BinarySearch(t.BlockedBy, id)
+	if found {
+		return false
+	}
+	t.BlockedBy = slices.Insert(slices.Clone(t.BlockedBy), i, id)
+	return true
+}
+
+// RemoveBlocker удаляет зависимость от задачи id.
+// Возвращает false, если её не было.
+func (t *Task) RemoveBlocker(id int) bool {
+	i, found := slices.BinarySearch(t.BlockedBy, id)
+	if !found {
+		return false
+	}
+	t.BlockedBy = slices.Delete(slices.Clone(t.BlockedBy), i, i+1)
+	if len(t.BlockedBy) == 0 {
+		t.BlockedBy = nil
+	}
+	return true
+}
+
+// OpenBlockers возвращает ID незавершённых задач, от которых зависит t.
+// Зависимости от отсутствующих в списке и отменённых задач не учитываются.
+func OpenBlockers(tasks []Task, t Task) []int {
+	if len(t.BlockedBy) == 0 {
+		return nil
+	}
+	closed := make(map[int]bool, len(tasks))
+	for _, other := range tasks {
+		closed[other.ID] = other.IsClosed()
+	}
+
+	var open []int
+	for _, id := range t.BlockedBy {
+		if done, exists := closed[id]; exists && !done {
+			open = append(open, id)
+		}
+	}
+	return open
+}
+
+// IsReady сообщает, можно ли браться за задачу: она не завершена,
+// не заблокирована (статус blocked) и все задачи, от которых она зависит,
+// уже завершены.
+func IsReady(tasks []Task, t Task) bool {
+	return !t.IsClosed() && t.Status != StatusBlocked && len(OpenBlockers(tasks, t)) == 0
+}
+
+// CheckBlocker проверяет, можно ли сделать задачу id зависимой от задачи by:
+// обе задачи должны существовать, а новая зависимость — не замыкать цикл.
+// При цикле возвращает *CycleError с путём от id обратно к id.
+func CheckBlocker(tasks []Task, id, by int) error {
+	byID := make(map[int]Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	if _, ok := byID[id]; !ok {
+		return fmt.Errorf("задача с ID %d не найдена", id)
+	}
+	if _, ok := byID[by]; !ok {
+		return fmt.Errorf("задача с ID %d не найдена", by)
+	}
+	if id == by {
+		return &CycleError{Path: []Task{byID[id], byID[id]}}
+	}
+
+	// Ищем путь от by к id по существующим зависимостям (обход в ширину,
+	// чтобы в сообщении оказалась кратчайшая цепочка).
+	prev := map[int]int{by: 0}
+	queue := []int{by}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == id {
+			// Восстанавливаем цепочку by → ... → id и замыкаем её новой
+			// зависимостью: id → by → ... → id.
+			var chain []Task
+			for step := id; step != 0; step = prev[step] {
+				chain = append(chain, byID[step])
+			}
+			slices.Reverse(chain)
+			return &CycleError{Path: append([]Task{byID[id]}, chain...)}
+		}
+		for _, next := range byID[current].BlockedBy {
+			if _, seen := prev[next]; !seen {
+				if _, exists := byID[next]; exists {
+					prev[next] = current
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// PruneBlockers убирает зависимости от задач, которых нет в списке
+// (например, после удаления). Возвращает изменённый список.
+func PruneBlockers(tasks []Task) []Task {
+	present := make(map[int]bool, len(tasks))
+	for _, t := range tasks {
+		present[t.ID] = true
+	}
+	for i := range tasks {
+		for _, id := range tasks[i].BlockedBy {
+			if !present[id] {
+				tasks[i].RemoveBlocker(id)
+			}
+		}
+	}
+	return tasks
+}
@@ -0,0 +1,89 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestBlockers проверяет добавление и удаление зависимостей.
+func TestBlockers(t *testing.T) {
+	var task Task
+	if !task.AddBlocker(5) || !task.AddBlocker(2) || task.AddBlocker(5) {
+		t.Fatal("AddBlocker вернул неверный признак изменения")
+	}
+	if fmt.Sprint(task.BlockedBy) != "[2 5]" || !task.IsBlockedBy(2) || task.IsBlockedBy(3) {
+		t.Errorf("неверные зависимости: %v", task.BlockedBy)
+	}
+
+	// Изменение копии не должно затрагивать исходную задачу
+	copied := task
+	copied.RemoveBlocker(2)
+	if fmt.Sprint(task.BlockedBy) != "[2 5]" {
+		t.Errorf("RemoveBlocker изменил исходную задачу: %v", task.BlockedBy)
+	}
+	if task.RemoveBlocker(7) || !task.RemoveBlocker(2) || !task.RemoveBlocker(5) || task.BlockedBy != nil {
+		t.Errorf("неверное удаление зависимостей: %v", task.BlockedBy)
+	}
+}
+
+// TestIsReady проверяет учёт выполненных и отсутствующих зависимостей.
+func TestIsReady(t *testing.T) {
+	tasks := []Task{
+		{ID: 1, Status: StatusDone},
+		{ID: 2},
+		{ID: 3, BlockedBy: []int{1}},
+		{ID: 4, BlockedBy: []int{1, 2, 9}},
+	}
+	if !IsReady(tasks, tasks[1]) || !IsReady(tasks, tasks[2]) || IsReady(tasks, tasks[3]) || IsReady(tasks, tasks[0]) {
+		t.Error("IsReady вернул неверный результат")
+	}
+	if got := fmt.Sprint(OpenBlockers(tasks, tasks[3])); got != "[2]" {
+		t.Errorf("OpenBlockers = %s, ожидалось [2]", got)
+	}
+}
+
+// TestCheckBlocker проверяет обнаружение циклов и текст ошибки.
+func TestCheckBlocker(t *testing.T) {
+	tasks := []Task{
+		{ID: 1, Title: "Деплой", BlockedBy: []int{2}},
+		{ID: 2, Title: "Миграция", BlockedBy: []int{3}},
+		{ID: 3, Title: "Бэкап"},
+		{ID: 4, Title: "Анонс", BlockedBy: []int{1}},
+	}
+
+	if err := CheckBlocker(tasks, 4, 3); err != nil {
+		t.Errorf("зависимость без цикла отклонена: %v", err)
+	}
+	if err := CheckBlocker(tasks, 1, 8); err == nil {
+		t.Error("ожидалась ошибка для несуществующей задачи")
+	}
+
+	tests := []struct {
+		id, by int
+		want   string
+	}{
+		{3, 1, `цикл зависимостей: #3 "Бэкап" → #1 "Деплой" → #2 "Миграция" → #3 "Бэкап"`},
+		{2, 2, `цикл зависимостей: #2 "Миграция" → #2 "Миграция"`},
+		{1, 4, `цикл зависимостей: #1 "Деплой" → #4 "Анонс" → #1 "Деплой"`},
+	}
+	for _, tt := range tests {
+		err := CheckBlocker(tasks, tt.id, tt.by)
+		var cycle *CycleError
+		if !errors.As(err, &cycle) {
+			t.Errorf("CheckBlocker(%d, %d) = %v, ожидался цикл", tt.id, tt.by, err)
+			continue
+		}
+		if err.Error() != tt.want {
+			t.Errorf("CheckBlocker(%d, %d) = %q, ожидалось %q", tt.id, tt.by, err, tt.want)
+		}
+	}
+}
+
+// TestPruneBlockers проверяет удаление зависимостей от удалённых задач.
+func TestPruneBlockers(t *testing.T) {
+	tasks := PruneBlockers([]Task{{ID: 1, BlockedBy: []int{2, 3}}, {ID: 3, BlockedBy: []int{7}}})
+	if fmt.Sprint(tasks[0].BlockedBy) != "[3]" || tasks[1].BlockedBy != nil {
+		t.Errorf("неверные зависимости после очистки: %+v", tasks)
+	}
+}
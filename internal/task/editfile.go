@@ -0,0 +1,153 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// frontMatterDelimiter — строка, которая открывает и закрывает заголовок
+// редактируемого файла.
+const frontMatterDelimiter = "---"
+
+// EditError — ошибка в редактируемом файле задачи.
+// Line — номер строки (с 1), 0 — ошибка относится ко всему файлу.
+type EditError struct {
+	Line int
+	Err  error
+}
+
+// Error возвращает текст ошибки с номером строки.
+func (e *EditError) Error() string {
+	if e.Line == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("строка %d: %v", e.Line, e.Err)
+}
+
+// Unwrap возвращает исходную ошибку.
+func (e *EditError) Unwrap() error {
+	return e.Err
+}
+
+// FormatEditable представляет задачу в виде текста для редактора:
+// заголовок (front matter) с названием, приоритетом и сроком между строками
+// "---", а после него — заметки в свободной форме. Строки заголовка
+// и строки перед ним, начинающиеся с #, — комментарии.
+//
+//	---
+//	title: Сдать отчёт
+//	priority: high
+//	due: 2025-03-14 18:00
+//	---
+//	Заметки...
+func FormatEditable(t Task) string {
+	var b strings.Builder
+	b.WriteString(frontMatterDelimiter + "\n")
+	b.WriteString("# Приоритет: " + strings.Join(PriorityNames(), ", ") + ". Срок: 2025-03-14, tomorrow, +3d, fri или пусто.\n")
+	b.WriteString("# Заметки пишутся после второй строки ---. Пустой файл отменяет редактирование.\n")
+	fmt.Fprintf(&b, "title: %s\n", t.Title)
+	fmt.Fprintf(&b, "priority: %s\n", t.Priority)
+	fmt.Fprintf(&b, "due: %s\n", editableDue(t.DueAt))
+	b.WriteString(frontMatterDelimiter + "\n")
+	if t.Notes != "" {
+		b.WriteString(t.Notes + "\n")
+	}
+	return b.String()
+}
+
+// ParseEditable разбирает текст в формате FormatEditable и возвращает
+// задачу t с новыми названием, приоритетом, сроком и заметками.
+// Относительные сроки считаются от now. Ошибка указывает строку
+// (*EditError).
+func ParseEditable(s string, t Task, now time.Time) (Task, error) {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+
+	// Заголовок начинается с первой непустой строки, не считая комментариев
+	start := 0
+	for start < len(lines) && isEditComment(lines[start]) {
+		start++
+	}
+	if start == len(lines) || strings.TrimSpace(lines[start]) != frontMatterDelimiter {
+		return Task{}, &EditError{Line: start + 1, Err: fmt.Errorf("файл должен начинаться со строки %s", frontMatterDelimiter)}
+	}
+
+	seen := make(map[string]bool)
+	end := -1
+	for i := start + 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == frontMatterDelimiter {
+			end = i
+			break
+		}
+		if isEditComment(line) {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return Task{}, &EditError{Line: i + 1, Err: fmt.Errorf("ожидалось \"поле: значение\", получено %q", line)}
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		if seen[key] {
+			return Task{}, &EditError{Line: i + 1, Err: fmt.Errorf("поле %q указано дважды", key)}
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "title":
+			if value == "" {
+				err = fmt.Errorf("название задачи не может быть пустым")
+			}
+			t.Title = value
+		case "priority":
+			t.Priority, err = ParsePriority(value)
+		case "due":
+			// Неизменённый срок оставляем как есть, не теряя часовой пояс
+			switch {
+			case value == editableDue(t.DueAt):
+			case value == "":
+				t.DueAt = time.Time{}
+			default:
+				t.DueAt, err = ParseDate(value, now)
+			}
+		default:
+			err = fmt.Errorf("неизвестное поле %q (допустимы title, priority, due)", key)
+		}
+		if err != nil {
+			return Task{}, &EditError{Line: i + 1, Err: err}
+		}
+	}
+	if end < 0 {
+		return Task{}, &EditError{Err: fmt.Errorf("не найдена закрывающая строка %s", frontMatterDelimiter)}
+	}
+	if !seen["title"] {
+		return Task{}, &EditError{Err: fmt.Errorf("не указано поле title")}
+	}
+
+	// Всё после заголовка — заметки; пустые строки по краям отбрасываем
+	notes := strings.Join(lines[end+1:], "\n")
+	t.Notes = strings.TrimRight(strings.TrimLeft(notes, "\n"), " \t\n")
+	return t, nil
+}
+
+// editableDue — приватная функция, форматирует срок для заголовка:
+// дату без времени — как 2025-03-14, иначе — с часами и минутами.
+func editableDue(due time.Time) string {
+	switch {
+	case due.IsZero():
+		return ""
+	case HasClock(due):
+		return due.Local().Format("2006-01-02 15:04")
+	default:
+		return due.Format("2006-01-02")
+	}
+}
+
+// isEditComment — приватная функция, сообщает, что строка заголовка
+// пустая или является комментарием.
+func isEditComment(line string) bool {
+	line = strings.TrimSpace(line)
+	return line == "" || strings.HasPrefix(line, "#")
+}
@@ -0,0 +1,72 @@
+package task
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestEditableRoundTrip проверяет, что задача без изменений
+// переживает форматирование и разбор.
+func TestEditableRoundTrip(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.Local)
+	original := Task{
+		ID:       3,
+		Title:    "Сдать отчёт",
+		Priority: PriorityHigh,
+		DueAt:    time.Date(2025, 3, 14, 18, 30, 0, 0, time.Local),
+		Tags:     []string{"work"},
+		Notes:    "Первая строка\n\n# заголовок markdown\n---\nпосле разделителя",
+	}
+
+	got, err := ParseEditable("# Ошибка: пример\n"+FormatEditable(original), original, now)
+	if err != nil {
+		t.Fatalf("ParseEditable вернул ошибку: %v", err)
+	}
+	if got.Title != original.Title || got.Priority != original.Priority || !got.DueAt.Equal(original.DueAt) ||
+		got.Notes != original.Notes || got.ID != 3 || len(got.Tags) != 1 {
+		t.Errorf("задача изменилась:\nбыло  %+v\nстало %+v", original, got)
+	}
+}
+
+// TestParseEditable проверяет разбор изменений и ошибки со строками.
+func TestParseEditable(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	original := Task{Title: "Старое", Priority: PriorityHigh, DueAt: now}
+
+	got, err := ParseEditable("\n---\nTitle:  Новое  \ndue:\n---\n\nЗаметка\n\n", original, now)
+	if err != nil {
+		t.Fatalf("ParseEditable вернул ошибку: %v", err)
+	}
+	if got.Title != "Новое" || got.Priority != PriorityHigh || !got.DueAt.IsZero() || got.Notes != "Заметка" {
+		t.Errorf("неверный результат: %+v", got)
+	}
+
+	tests := []struct {
+		in   string
+		line int
+		want string
+	}{
+		{"title: x\n", 1, "должен начинаться"},
+		{"---\ntitle: x\npriority: huge\n---\n", 3, "huge"},
+		{"---\ntitle: x\ndue: someday\n---\n", 3, "someday"},
+		{"---\ntitle:\n---\n", 2, "не может быть пустым"},
+		{"---\ntitle: x\ncolor: red\n---\n", 3, "неизвестное поле"},
+		{"---\ntitle: x\ntitle: y\n---\n", 3, "дважды"},
+		{"---\ntitle x\n---\n", 2, "поле: значение"},
+		{"---\ntitle: x\n", 0, "закрывающая"},
+		{"---\npriority: low\n---\n", 0, "title"},
+	}
+	for _, tt := range tests {
+		_, err := ParseEditable(tt.in, original, now)
+		var editErr *EditError
+		if !errors.As(err, &editErr) {
+			t.Errorf("ParseEditable(%q) = %v, ожидалась EditError", tt.in, err)
+			continue
+		}
+		if editErr.Line != tt.line || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseEditable(%q) = %q (строка %d), ожидалось %q в строке %d", tt.in, err, editErr.Line, tt.want, tt.line)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Priority — приоритет задачи. Значения упорядочены: чем больше, тем важнее.
+// Нулевое значение — обычный приоритет, поэтому задачи без явно
+// указанного приоритета считаются обычными.
+type Priority int
+
+// Уровни приоритета от низкого к срочному.
+const (
+	PriorityLow    Priority = -1 // низкий
+	PriorityNormal Priority = 0  // обычный (по умолчанию)
+	PriorityHigh   Priority = 1  // высокий (бывший флаг Important)
+	PriorityUrgent Priority = 2  // срочный
+)
+
+// priorityNames — имена приоритетов в порядке возрастания.
+var priorityNames = []struct {
+	p    Priority
+	name string
+}{
+	{PriorityLow, "low"},
+	{PriorityNormal, "normal"},
+	{PriorityHigh, "high"},
+	{PriorityUrgent, "urgent"},
+}
+
+// PriorityNames возвращает имена всех приоритетов от низкого к срочному
+// (например, для автодополнения).
+func PriorityNames() []string {
+	names := make([]string, 0, len(priorityNames))
+	for _, pn := range priorityNames {
+		names = append(names, pn.name)
+	}
+	return names
+}
+
+// ParsePriority разбирает имя приоритета без учёта регистра.
+func ParsePriority(s string) (Priority, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	for _, pn := range priorityNames {
+		if pn.name == value {
+			return pn.p, nil
+		}
+	}
+	return PriorityNormal, fmt.Errorf("неизвестный приоритет %q: используйте %s", s, strings.Join(PriorityNames(), ", "))
+}
+
+// String возвращает имя приоритета.
+func (p Priority) String() string {
+	for _, pn := range priorityNames {
+		if pn.p == p {
+			return pn.name
+		}
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// MarshalText сохраняет приоритет по имени, чтобы файл задач оставался читаемым.
+func (p Priority) MarshalText() ([]byte, error) {
+	for _, pn := range priorityNames {
+		if pn.p == p {
+			return []byte(pn.name), nil
+		}
+	}
+	return nil, fmt.Errorf("некорректный приоритет %d", int(p))
+}
+
+// UnmarshalText читает приоритет по имени.
+func (p *Priority) UnmarshalText(text []byte) error {
+	parsed, err := ParsePriority(string(text))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
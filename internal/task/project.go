@@ -0,0 +1,58 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProjectSeparator разделяет уровни иерархии проекта: "work.release"
+// — подпроект release проекта work.
+const ProjectSeparator = "."
+
+// NormalizeProject приводит имя проекта к каноническому виду (нижний
+// регистр, без пробелов по краям) и проверяет его: каждый уровень
+// иерархии должен быть непустым и не содержать пробелов.
+// Пустая строка означает "без проекта" и допустима.
+func NormalizeProject(s string) (string, error) {
+	project := strings.ToLower(strings.TrimSpace(s))
+	if project == "" {
+		return "", nil
+	}
+	for _, part := range strings.Split(project, ProjectSeparator) {
+		if part == "" || strings.ContainsAny(part, " \t") {
+			return "", fmt.Errorf("некорректное имя проекта %q: используйте вид work.release без пробелов", s)
+		}
+	}
+	return project, nil
+}
+
+// InProject сообщает, относится ли проект project к проекту parent
+// или к одному из его подпроектов: "work.release" входит в "work",
+// а "workshop" — нет.
+func InProject(project, parent string) bool {
+	return project == parent || strings.HasPrefix(project, parent+ProjectSeparator)
+}
+
+// ProjectAncestors возвращает проект и все его родительские проекты,
+// от верхнего уровня к самому проекту: "a.b.c" → [a a.b a.b.c].
+func ProjectAncestors(project string) []string {
+	if project == "" {
+		return nil
+	}
+	parts := strings.Split(project, ProjectSeparator)
+	result := make([]string, 0, len(parts))
+	for i := range parts {
+		result = append(result, strings.Join(parts[:i+1], ProjectSeparator))
+	}
+	return result
+}
+
+// RenameProject заменяет в имени проекта префикс from на to, сохраняя
+// подпроекты: RenameProject("work.release.v2", "work.release", "ship") = "ship.v2".
+// Если проект не входит в from, он возвращается без изменений.
+func RenameProject(project, from, to string) string {
+	if !InProject(project, from) {
+		return project
+	}
+	return to + strings.TrimPrefix(project, from)
+}
@@ -0,0 +1,46 @@
+package task
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestNormalizeProject проверяет приведение и проверку имён проектов.
+func TestNormalizeProject(t *testing.T) {
+	for in, want := range map[string]string{"": "", " Work.Release ": "work.release", "home": "home"} {
+		got, err := NormalizeProject(in)
+		if err != nil || got != want {
+			t.Errorf("NormalizeProject(%q) = %q, %v; ожидалось %q", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{".work", "work.", "work..release", "my work"} {
+		if _, err := NormalizeProject(bad); err == nil {
+			t.Errorf("NormalizeProject(%q): ожидалась ошибка", bad)
+		}
+	}
+}
+
+// TestProjectHierarchy проверяет вложенность, предков и переименование проектов.
+func TestProjectHierarchy(t *testing.T) {
+	if !InProject("work", "work") || !InProject("work.release", "work") || InProject("workshop", "work") || InProject("work", "work.release") {
+		t.Error("InProject неверно определяет вложенность")
+	}
+	if got := fmt.Sprint(ProjectAncestors("a.b.c")); got != "[a a.b a.b.c]" {
+		t.Errorf("ProjectAncestors вернул %s", got)
+	}
+	if ProjectAncestors("") != nil {
+		t.Error("у задачи без проекта не должно быть предков")
+	}
+
+	tests := []struct{ project, from, to, want string }{
+		{"work.release.v2", "work.release", "ship", "ship.v2"},
+		{"work.release", "work.release", "ship", "ship"},
+		{"work.releases", "work.release", "ship", "work.releases"},
+		{"home", "work", "job", "home"},
+	}
+	for _, tt := range tests {
+		if got := RenameProject(tt.project, tt.from, tt.to); got != tt.want {
+			t.Errorf("RenameProject(%q, %q, %q) = %q, ожидалось %q", tt.project, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
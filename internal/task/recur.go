@@ -0,0 +1,319 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency — единица периода повторения задачи.
+type Frequency int
+
+// Единицы периода повторения. Нулевое значение — задача не повторяется.
+const (
+	FreqNone Frequency = iota
+	FreqDaily
+	FreqWeekly
+	FreqMonthly
+)
+
+// frequencyUnits — названия единиц периода в правиле (единственное число).
+var frequencyUnits = map[Frequency]string{
+	FreqDaily:   "day",
+	FreqWeekly:  "week",
+	FreqMonthly: "month",
+}
+
+// recurrenceAliases — сокращения правил: daily — то же, что every day.
+var recurrenceAliases = map[string]string{
+	"daily":   "day",
+	"weekly":  "week",
+	"monthly": "month",
+}
+
+// weekOrder — дни недели в порядке вывода (неделя начинается с понедельника).
+var weekOrder = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+	time.Friday, time.Saturday, time.Sunday,
+}
+
+// Recurrence — правило повторения задачи в духе RRULE:
+//
+//   - every day, every 3 days (daily);
+//   - every week, every mon,thu, every 2 weeks on fri (weekly);
+//   - every month, every month on 15, monthly on 31 (monthly);
+//   - every 10 days after done — период отсчитывается от выполнения, а не от срока.
+//
+// Правило хранится строкой (см. String и ParseRecurrence).
+type Recurrence struct {
+	Freq      Frequency // единица периода (FreqNone — не повторяется)
+	Interval  int       // каждые Interval единиц
+	Weekdays  uint8     // дни недели для weekly: битовая маска 1 << time.Weekday
+	MonthDay  int       // день месяца для monthly (0 — день исходного срока)
+	AfterDone bool      // отсчитывать период от выполнения, а не от срока
+}
+
+// IsZero сообщает, что правило не задано (задача не повторяется).
+func (r Recurrence) IsZero() bool {
+	return r.Freq == FreqNone
+}
+
+// HasWeekday сообщает, входит ли день недели в правило weekly.
+func (r Recurrence) HasWeekday(wd time.Weekday) bool {
+	return r.Weekdays&(1<<wd) != 0
+}
+
+// ParseRecurrence разбирает правило повторения (регистр не важен).
+// Кроме форм из описания Recurrence, принимаются сокращения daily, weekly,
+// monthly и пустая строка или none — без повторения.
+func ParseRecurrence(s string) (Recurrence, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	if value == "" || value == "none" {
+		return Recurrence{}, nil
+	}
+	invalid := fmt.Errorf("не удалось разобрать правило повторения %q: "+
+		"используйте daily, every mon,thu, monthly on 15 или every 3 days after done", s)
+
+	words := strings.Fields(value)
+	var r Recurrence
+	if n := len(words); n > 2 && words[n-2] == "after" && words[n-1] == "done" {
+		r.AfterDone = true
+		words = words[:n-2]
+	}
+	if len(words) == 0 {
+		return Recurrence{}, invalid
+	}
+
+	// daily/weekly/monthly — то же, что every day/week/month
+	if unit, ok := recurrenceAliases[words[0]]; ok {
+		words = append([]string{"every", unit}, words[1:]...)
+	}
+	if len(words) < 2 || words[0] != "every" {
+		return Recurrence{}, invalid
+	}
+	words = words[1:]
+
+	// every mon,thu — еженедельно по указанным дням
+	if days, err := parseWeekdays(words[0]); err == nil {
+		if len(words) != 1 || r.AfterDone {
+			return Recurrence{}, invalid
+		}
+		return Recurrence{Freq: FreqWeekly, Interval: 1, Weekdays: days}, nil
+	}
+
+	// every [N] day(s)|week(s)|month(s) [on ...]
+	r.Interval = 1
+	if n, err := strconv.Atoi(words[0]); err == nil {
+		if n < 1 || len(words) < 2 {
+			return Recurrence{}, invalid
+		}
+		r.Interval = n
+		words = words[1:]
+	}
+	unit := strings.TrimSuffix(words[0], "s")
+	for freq, name := range frequencyUnits {
+		if name == unit {
+			r.Freq = freq
+		}
+	}
+	if r.Freq == FreqNone || (r.Interval == 1 && unit != words[0]) {
+		return Recurrence{}, invalid
+	}
+	words = words[1:]
+
+	if len(words) == 0 {
+		return r, nil
+	}
+	if len(words) != 2 || words[0] != "on" || r.AfterDone {
+		return Recurrence{}, invalid
+	}
+	switch r.Freq {
+	case FreqWeekly:
+		days, err := parseWeekdays(words[1])
+		if err != nil {
+			return Recurrence{}, invalid
+		}
+		r.Weekdays = days
+	case FreqMonthly:
+		day, err := strconv.Atoi(words[1])
+		if err != nil || day < 1 || day > 31 {
+			return Recurrence{}, invalid
+		}
+		r.MonthDay = day
+	default:
+		return Recurrence{}, invalid
+	}
+	return r, nil
+}
+
+// parseWeekdays разбирает список дней недели через запятую: mon,thu.
+func parseWeekdays(s string) (uint8, error) {
+	var mask uint8
+	for _, name := range strings.Split(s, ",") {
+		wd, ok := weekdays[name]
+		if !ok {
+			return 0, fmt.Errorf("неизвестный день недели %q", name)
+		}
+		mask |= 1 << wd
+	}
+	return mask, nil
+}
+
+// String возвращает правило в каноническом виде, который понимает
+// ParseRecurrence: every mon,thu, every 2 weeks on fri, every 3 days after done.
+// Для задачи без повторения возвращает пустую строку.
+func (r Recurrence) String() string {
+	if r.IsZero() {
+		return ""
+	}
+
+	var days []string
+	for _, wd := range weekOrder {
+		if r.HasWeekday(wd) {
+			days = append(days, strings.ToLower(wd.String()[:3]))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("every ")
+	switch {
+	case r.Freq == FreqWeekly && r.Interval == 1 && len(days) > 0:
+		b.WriteString(strings.Join(days, ","))
+		return b.String()
+	case r.Interval == 1:
+		b.WriteString(frequencyUnits[r.Freq])
+	default:
+		fmt.Fprintf(&b, "%d %ss", r.Interval, frequencyUnits[r.Freq])
+	}
+	switch {
+	case len(days) > 0:
+		b.WriteString(" on " + strings.Join(days, ","))
+	case r.MonthDay > 0:
+		fmt.Fprintf(&b, " on %d", r.MonthDay)
+	}
+	if r.AfterDone {
+		b.WriteString(" after done")
+	}
+	return b.String()
+}
+
+// MarshalText сериализует правило строкой (для JSON).
+func (r Recurrence) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText читает правило из строки (для JSON).
+func (r *Recurrence) UnmarshalText(text []byte) error {
+	parsed, err := ParseRecurrence(string(text))
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Next возвращает срок следующего повторения задачи со сроком due,
+// выполненной в момент done. Период отсчитывается от срока (а если
+// срока нет или указано after done — от дня выполнения, сохраняя время
+// суток срока), и следующий срок всегда оказывается позже done:
+// пропущенные повторения не накапливаются.
+func (r Recurrence) Next(due, done time.Time) time.Time {
+	if r.IsZero() {
+		return time.Time{}
+	}
+
+	base := due
+	if due.IsZero() || r.AfterDone {
+		loc := done.Location()
+		var h, m, s, ns int
+		if !due.IsZero() {
+			loc = due.Location()
+			h, m, s = due.Clock()
+			ns = due.Nanosecond()
+		}
+		d := done.In(loc)
+		base = time.Date(d.Year(), d.Month(), d.Day(), h, m, s, ns, loc)
+	}
+
+	// День месяца фиксируем заранее, чтобы 31-е после февраля не стало 28-м.
+	monthDay := r.MonthDay
+	if monthDay == 0 {
+		monthDay = base.Day()
+	}
+	interval := max(r.Interval, 1)
+
+	next := base
+	for {
+		next = r.step(next, interval, monthDay)
+		if next.After(done) {
+			return next
+		}
+	}
+}
+
+// step — приватный метод, сдвигает срок на один период правила.
+func (r Recurrence) step(t time.Time, interval, monthDay int) time.Time {
+	switch r.Freq {
+	case FreqDaily:
+		return t.AddDate(0, 0, interval)
+	case FreqWeekly:
+		if r.Weekdays == 0 {
+			return t.AddDate(0, 0, 7*interval)
+		}
+		for d := 1; d <= 7; d++ {
+			next := t.AddDate(0, 0, d)
+			if !r.HasWeekday(next.Weekday()) {
+				continue
+			}
+			// Перешли на следующую неделю — пропускаем ещё interval-1 недель
+			if weekStart(next) != weekStart(t) {
+				next = next.AddDate(0, 0, 7*(interval-1))
+			}
+			return next
+		}
+	case FreqMonthly:
+		first := time.Date(t.Year(), t.Month()+time.Month(interval), 1, 0, 0, 0, 0, t.Location())
+		last := first.AddDate(0, 1, -1).Day()
+		h, m, s := t.Clock()
+		return time.Date(first.Year(), first.Month(), min(monthDay, last), h, m, s, t.Nanosecond(), t.Location())
+	}
+	return t
+}
+
+// weekStart — приватная функция, возвращает дату понедельника недели t.
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	y, m, d := t.AddDate(0, 0, -offset).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Complete отмечает задачу выполненной в момент now (CompletedAt)
+// и останавливает её таймер, если он шёл. Если задача повторяющаяся,
+// возвращает её следующее повторение и true: копию с новым сроком
+// и нулевым ID, чтобы хранилище назначило новый. Правило повторения
+// переходит к новой задаче, поэтому повторное выполнение той же задачи
+// не создаёт лишних повторений.
+func (t *Task) Complete(now time.Time) (Task, bool) {
+	if t.Status == StatusDone {
+		return Task{}, false
+	}
+	t.MarkDone()
+	t.CompletedAt = now
+	t.StopTimer(now)
+	if t.Recur.IsZero() {
+		return Task{}, false
+	}
+
+	next := t.Clone()
+	next.ID = 0
+	next.Status = StatusTodo
+	next.CreatedAt = now
+	next.UpdatedAt = time.Time{}
+	next.CompletedAt = time.Time{}
+	next.DueAt = t.Recur.Next(t.DueAt, now)
+	next.BlockedBy = nil
+	next.TimeLog = nil
+	t.Recur = Recurrence{}
+	return next, true
+}
@@ -0,0 +1,112 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseRecurrence проверяет разбор правил и их канонический вид.
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"daily", "every day"},
+		{"Every 3 Days", "every 3 days"},
+		{"weekly", "every week"},
+		{"every mon,thu", "every mon,thu"},
+		{"every thursday,mon", "every mon,thu"},
+		{"weekly on fri", "every fri"},
+		{"every 2 weeks on sun,fri", "every 2 weeks on fri,sun"},
+		{"monthly", "every month"},
+		{"monthly on 15", "every month on 15"},
+		{"every 3 months on 31", "every 3 months on 31"},
+		{"every 10 days after done", "every 10 days after done"},
+		{"every week after done", "every week after done"},
+		{"none", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		r, err := ParseRecurrence(tt.in)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q) вернул ошибку: %v", tt.in, err)
+			continue
+		}
+		if r.String() != tt.want {
+			t.Errorf("ParseRecurrence(%q) = %q, ожидалось %q", tt.in, r, tt.want)
+		}
+		// Канонический вид разбирается в то же правило
+		if again, err := ParseRecurrence(r.String()); err != nil || again != r {
+			t.Errorf("ParseRecurrence(%q) не сохраняется при повторном разборе: %+v, %v", r, again, err)
+		}
+	}
+
+	for _, bad := range []string{"sometimes", "every", "every 0 days", "every days", "every day on 5",
+		"every mon,funday", "monthly on 32", "every mon after done", "monthly on 15 after done"} {
+		if _, err := ParseRecurrence(bad); err == nil {
+			t.Errorf("ParseRecurrence(%q) должен вернуть ошибку", bad)
+		}
+	}
+}
+
+// TestRecurrenceNext проверяет расчёт следующего срока.
+func TestRecurrenceNext(t *testing.T) {
+	date := func(y int, m time.Month, d, h int) time.Time { return time.Date(y, m, d, h, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		rule      string
+		due, done time.Time
+		want      time.Time
+	}{
+		// Пт 14.03.2025, выполнена в срок
+		{"daily", date(2025, 3, 14, 0), date(2025, 3, 14, 10), date(2025, 3, 15, 0)},
+		{"every 3 days", date(2025, 3, 14, 9), date(2025, 3, 14, 8), date(2025, 3, 17, 9)},
+		{"every mon,thu", date(2025, 3, 13, 0), date(2025, 3, 13, 12), date(2025, 3, 17, 0)},
+		{"every mon,thu", date(2025, 3, 10, 0), date(2025, 3, 10, 12), date(2025, 3, 13, 0)},
+		{"every 2 weeks on mon,thu", date(2025, 3, 13, 0), date(2025, 3, 13, 12), date(2025, 3, 24, 0)},
+		{"weekly", date(2025, 3, 14, 0), date(2025, 3, 14, 12), date(2025, 3, 21, 0)},
+		// 31-е число сохраняется после короткого месяца
+		{"monthly", date(2025, 1, 31, 0), date(2025, 1, 31, 12), date(2025, 2, 28, 0)},
+		{"monthly on 31", date(2025, 2, 28, 0), date(2025, 2, 28, 12), date(2025, 3, 31, 0)},
+		// Пропущенные повторения не накапливаются
+		{"daily", date(2025, 3, 1, 0), date(2025, 3, 14, 10), date(2025, 3, 15, 0)},
+		// after done и задача без срока — от дня выполнения
+		{"every 10 days after done", date(2025, 3, 1, 18), date(2025, 3, 14, 10), date(2025, 3, 24, 18)},
+		{"weekly", time.Time{}, date(2025, 3, 14, 10), date(2025, 3, 21, 0)},
+	}
+	for _, tt := range tests {
+		r, err := ParseRecurrence(tt.rule)
+		if err != nil {
+			t.Fatalf("ParseRecurrence(%q) вернул ошибку: %v", tt.rule, err)
+		}
+		if got := r.Next(tt.due, tt.done); !got.Equal(tt.want) {
+			t.Errorf("%q: Next(%v, %v) = %v, ожидалось %v", tt.rule, tt.due, tt.done, got, tt.want)
+		}
+	}
+}
+
+// TestComplete проверяет, что выполнение повторяющейся задачи
+// порождает следующее повторение.
+func TestComplete(t *testing.T) {
+	now := time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)
+	recur, _ := ParseRecurrence("daily")
+	original := Task{ID: 3, Title: "Отчёт", DueAt: time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC),
+		Recur: recur, Tags: []string{"work"}, BlockedBy: []int{1}}
+
+	next, ok := original.Complete(now)
+	if !ok || original.Status != StatusDone || !original.CompletedAt.Equal(now) || !original.Recur.IsZero() {
+		t.Fatalf("неверное состояние выполненной задачи: %+v", original)
+	}
+	if next.ID != 0 || next.Status == StatusDone || next.Recur != recur || !next.CreatedAt.Equal(now) ||
+		!next.CompletedAt.IsZero() || next.BlockedBy != nil || next.DueAt.Day() != 15 {
+		t.Errorf("неверное следующее повторение: %+v", next)
+	}
+
+	// Повторное выполнение и выполнение разовой задачи ничего не порождают
+	if _, ok := original.Complete(now); ok {
+		t.Error("повторное выполнение не должно порождать повторение")
+	}
+	plain := Task{ID: 4}
+	if _, ok := plain.Complete(now); ok || plain.Status != StatusDone {
+		t.Errorf("разовая задача должна просто выполниться: %+v", plain)
+	}
+}
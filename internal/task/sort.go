@@ -0,0 +1,153 @@
+package task
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// SortKey — ключ сортировки задач: поле и направление.
+type SortKey struct {
+	Field string // имя поля: title, due, priority...
+	Desc  bool   // по убыванию
+}
+
+// String возвращает ключ в том виде, в котором он задаётся во флаге --sort:
+// "-priority" для убывания, "due" для возрастания.
+func (k SortKey) String() string {
+	if k.Desc {
+		return "-" + k.Field
+	}
+	return k.Field
+}
+
+// sortField — поле, по которому можно сортировать задачи.
+type sortField struct {
+	// missing сообщает, что у задачи нет значения поля (например, срока);
+	// такие задачи при любом направлении идут в конце. nil — значение есть всегда.
+	missing func(t Task) bool
+	// compare сравнивает задачи по полю; строки сравниваются через c.
+	compare func(a, b Task, c *collate.Collator) int
+}
+
+// compareTimes — приватная функция, сравнивает задачи по моменту времени.
+func compareTimes(get func(Task) time.Time) func(a, b Task, _ *collate.Collator) int {
+	return func(a, b Task, _ *collate.Collator) int { return get(a).Compare(get(b)) }
+}
+
+// sortFields — поля сортировки. Возрастание — естественный порядок значений:
+// от раннего к позднему, от низкого приоритета к срочному, по алфавиту.
+var sortFields = map[string]sortField{
+	"id": {nil, func(a, b Task, _ *collate.Collator) int { return cmp.Compare(a.ID, b.ID) }},
+	"title": {nil, func(a, b Task, c *collate.Collator) int {
+		return c.CompareString(a.Title, b.Title)
+	}},
+	"project": {func(t Task) bool { return t.Project == "" }, func(a, b Task, c *collate.Collator) int {
+		return c.CompareString(a.Project, b.Project)
+	}},
+	"priority":  {nil, func(a, b Task, _ *collate.Collator) int { return cmp.Compare(a.Priority, b.Priority) }},
+	"status":    {nil, func(a, b Task, _ *collate.Collator) int { return cmp.Compare(a.Status, b.Status) }},
+	"created":   {nil, compareTimes(func(t Task) time.Time { return t.CreatedAt })},
+	"updated":   {func(t Task) bool { return t.UpdatedAt.IsZero() }, compareTimes(func(t Task) time.Time { return t.UpdatedAt })},
+	"due":       {func(t Task) bool { return t.DueAt.IsZero() }, compareTimes(func(t Task) time.Time { return t.DueAt })},
+	"completed": {func(t Task) bool { return t.CompletedAt.IsZero() }, compareTimes(func(t Task) time.Time { return t.CompletedAt })},
+}
+
+// sortAliases — прежние имена полей сортировки.
+var sortAliases = map[string]string{
+	"name": "title",
+	"date": "created",
+}
+
+// SortFieldNames возвращает имена полей сортировки по алфавиту.
+func SortFieldNames() []string {
+	names := make([]string, 0, len(sortFields))
+	for name := range sortFields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ParseSort разбирает список ключей сортировки через запятую, например
+// "-priority,due,title": минус перед полем — по убыванию, плюс или без
+// знака — по возрастанию. Пустая строка — без сортировки.
+func ParseSort(s string) ([]SortKey, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var keys []SortKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		key := SortKey{Field: part}
+		switch {
+		case strings.HasPrefix(part, "-"):
+			key = SortKey{Field: part[1:], Desc: true}
+		case strings.HasPrefix(part, "+"):
+			key.Field = part[1:]
+		}
+		if alias, ok := sortAliases[key.Field]; ok {
+			key.Field = alias
+		}
+
+		if _, ok := sortFields[key.Field]; !ok {
+			if key.Field == "" {
+				return nil, fmt.Errorf("пустой ключ сортировки в %q", s)
+			}
+			return nil, fmt.Errorf("неизвестное поле сортировки %q, используйте: %s", key.Field, strings.Join(SortFieldNames(), ", "))
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("поле сортировки %q указано дважды", key.Field)
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Comparator строит функцию сравнения задач по ключам keys: следующий ключ
+// учитывается, только если по предыдущим задачи равны. Названия и проекты
+// сравниваются по правилам языка lang: ё — как е, регистр учитывается
+// в последнюю очередь. Функция не потокобезопасна.
+func Comparator(keys []SortKey, lang language.Tag) func(a, b Task) int {
+	c := collate.New(lang)
+	return func(a, b Task) int {
+		for _, key := range keys {
+			f := sortFields[key.Field]
+			if f.missing != nil {
+				ma, mb := f.missing(a), f.missing(b)
+				switch {
+				case ma && mb:
+					continue
+				case ma:
+					return 1
+				case mb:
+					return -1
+				}
+			}
+			if r := f.compare(a, b, c); r != 0 {
+				if key.Desc {
+					return -r
+				}
+				return r
+			}
+		}
+		return 0
+	}
+}
+
+// Sort устойчиво сортирует задачи по ключам keys: равные по всем ключам
+// задачи остаются в исходном порядке.
+func Sort(tasks []Task, keys []SortKey, lang language.Tag) {
+	if len(keys) == 0 {
+		return
+	}
+	slices.SortStableFunc(tasks, Comparator(keys, lang))
+}
@@ -0,0 +1,71 @@
+package task
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// TestParseSort проверяет разбор ключей сортировки, направления и прежние имена полей.
+func TestParseSort(t *testing.T) {
+	keys, err := ParseSort(" -priority, due,+Title,date")
+	if err != nil {
+		t.Fatalf("ParseSort вернул ошибку: %v", err)
+	}
+	want := []SortKey{{"priority", true}, {"due", false}, {"title", false}, {"created", false}}
+	if !slices.Equal(keys, want) {
+		t.Errorf("ParseSort = %v, ожидалось %v", keys, want)
+	}
+	if keys, err := ParseSort(""); err != nil || keys != nil {
+		t.Errorf("пустая строка — без сортировки: %v, %v", keys, err)
+	}
+	for _, bad := range []string{"colour", "due,", "-", "due,-due", "name,title"} {
+		if _, err := ParseSort(bad); err == nil {
+			t.Errorf("ParseSort(%q) должен вернуть ошибку", bad)
+		}
+	}
+}
+
+// TestSort проверяет сортировку по нескольким ключам: направление, задачи
+// без срока в конце, устойчивость и сравнение названий по правилам языка.
+func TestSort(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+	tasks := []Task{
+		{ID: 1, Title: "ёжик", Priority: PriorityHigh},
+		{ID: 2, Title: "Яблоко", Priority: PriorityUrgent, DueAt: day(20)},
+		{ID: 3, Title: "арбуз", Priority: PriorityHigh, DueAt: day(10)},
+		{ID: 4, Title: "Жёлудь", Priority: PriorityHigh, DueAt: day(10)},
+		{ID: 5, Title: "Ель", Priority: PriorityLow},
+		{ID: 6, Title: "ель", Priority: PriorityLow},
+	}
+
+	tests := []struct {
+		spec string
+		want []int
+	}{
+		{"-priority,due,title", []int{2, 3, 4, 1, 6, 5}},
+		{"title", []int{3, 1, 6, 5, 4, 2}}, // ё — как е, строчные раньше заглавных
+		{"-title", []int{2, 4, 5, 6, 1, 3}},
+		{"due", []int{3, 4, 2, 1, 5, 6}},
+		{"-due,id", []int{2, 3, 4, 1, 5, 6}},
+		{"priority", []int{5, 6, 1, 3, 4, 2}},
+		{"-id", []int{6, 5, 4, 3, 2, 1}},
+	}
+	for _, tt := range tests {
+		keys, err := ParseSort(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseSort(%q) вернул ошибку: %v", tt.spec, err)
+		}
+		sorted := slices.Clone(tasks)
+		Sort(sorted, keys, language.Russian)
+		var got []int
+		for _, task := range sorted {
+			got = append(got, task.ID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("--sort=%s: получено %v, ожидалось %v", tt.spec, got, tt.want)
+		}
+	}
+}
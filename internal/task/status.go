@@ -0,0 +1,132 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Status — состояние задачи в рабочем процессе.
+// Нулевое значение — новая задача, к которой ещё не приступали.
+type Status int
+
+// Состояния задачи в порядке обычного рабочего процесса.
+const (
+	StatusTodo       Status = iota // к выполнению (по умолчанию)
+	StatusInProgress               // в работе
+	StatusBlocked                  // заблокирована внешними обстоятельствами
+	StatusReview                   // на проверке
+	StatusDone                     // выполнена
+	StatusCancelled                // отменена
+)
+
+// statusNames — имена состояний в порядке рабочего процесса.
+var statusNames = []struct {
+	s    Status
+	name string
+}{
+	{StatusTodo, "todo"},
+	{StatusInProgress, "in-progress"},
+	{StatusBlocked, "blocked"},
+	{StatusReview, "review"},
+	{StatusDone, "done"},
+	{StatusCancelled, "cancelled"},
+}
+
+// StatusNames возвращает имена всех состояний в порядке рабочего процесса
+// (например, для автодополнения).
+func StatusNames() []string {
+	names := make([]string, 0, len(statusNames))
+	for _, sn := range statusNames {
+		names = append(names, sn.name)
+	}
+	return names
+}
+
+// ParseStatus разбирает имя состояния без учёта регистра;
+// вместо дефиса можно писать подчёркивание (in_progress).
+func ParseStatus(s string) (Status, error) {
+	value := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "_", "-")
+	for _, sn := range statusNames {
+		if sn.name == value {
+			return sn.s, nil
+		}
+	}
+	return StatusTodo, fmt.Errorf("неизвестный статус %q: используйте %s", s, strings.Join(StatusNames(), ", "))
+}
+
+// String возвращает имя состояния.
+func (s Status) String() string {
+	for _, sn := range statusNames {
+		if sn.s == s {
+			return sn.name
+		}
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// IsClosed сообщает, что работа над задачей завершена:
+// она выполнена или отменена.
+func (s Status) IsClosed() bool {
+	return s == StatusDone || s == StatusCancelled
+}
+
+// MarshalText сохраняет состояние по имени, чтобы файл задач оставался читаемым.
+func (s Status) MarshalText() ([]byte, error) {
+	for _, sn := range statusNames {
+		if sn.s == s {
+			return []byte(sn.name), nil
+		}
+	}
+	return nil, fmt.Errorf("некорректный статус %d", int(s))
+}
+
+// UnmarshalText читает состояние по имени.
+func (s *Status) UnmarshalText(text []byte) error {
+	parsed, err := ParseStatus(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// Workflow — допустимые переходы между состояниями: для каждого
+// состояния — список состояний, в которые из него можно перейти.
+type Workflow map[Status][]Status
+
+// DefaultWorkflow возвращает рабочий процесс по умолчанию:
+// todo → in-progress → review → done, с возможностью заблокировать
+// задачу в работе, отменить незавершённую и вернуть завершённую в todo.
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		StatusTodo:       {StatusInProgress, StatusBlocked, StatusDone, StatusCancelled},
+		StatusInProgress: {StatusTodo, StatusBlocked, StatusReview, StatusDone, StatusCancelled},
+		StatusBlocked:    {StatusTodo, StatusInProgress, StatusCancelled},
+		StatusReview:     {StatusInProgress, StatusDone, StatusCancelled},
+		StatusDone:       {StatusTodo},
+		StatusCancelled:  {StatusTodo},
+	}
+}
+
+// Allows сообщает, разрешён ли переход from → to.
+// Переход в то же состояние всегда разрешён.
+func (w Workflow) Allows(from, to Status) bool {
+	return from == to || slices.Contains(w[from], to)
+}
+
+// Check возвращает ошибку с перечнем допустимых состояний,
+// если переход from → to запрещён.
+func (w Workflow) Check(from, to Status) error {
+	if w.Allows(from, to) {
+		return nil
+	}
+	allowed := make([]string, 0, len(w[from]))
+	for _, s := range w[from] {
+		allowed = append(allowed, s.String())
+	}
+	if len(allowed) == 0 {
+		return fmt.Errorf("переход %s → %s запрещён: из %s выйти нельзя", from, to, from)
+	}
+	return fmt.Errorf("переход %s → %s запрещён, из %s можно перейти в: %s", from, to, from, strings.Join(allowed, ", "))
+}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestParseStatus проверяет разбор имён состояний и их допустимые написания.
+func TestParseStatus(t *testing.T) {
+	for _, name := range StatusNames() {
+		s, err := ParseStatus(strings.ToUpper(name))
+		if err != nil || s.String() != name {
+			t.Errorf("ParseStatus(%q) = %v, %v", name, s, err)
+		}
+	}
+	if s, err := ParseStatus("in_progress"); err != nil || s != StatusInProgress {
+		t.Errorf("ParseStatus(in_progress) = %v, %v", s, err)
+	}
+	if _, err := ParseStatus("pending"); err == nil {
+		t.Error("ожидалась ошибка для неизвестного статуса")
+	}
+	if StatusTodo.IsClosed() || StatusReview.IsClosed() || !StatusDone.IsClosed() || !StatusCancelled.IsClosed() {
+		t.Error("IsClosed вернул неверный результат")
+	}
+}
+
+// TestTaskJSON_Status проверяет сохранение статуса по имени и чтение
+// файлов старого формата, где выполнение хранилось флагом completed.
+func TestTaskJSON_Status(t *testing.T) {
+	data, err := json.Marshal(Task{ID: 1, Status: StatusInProgress})
+	if err != nil || !strings.Contains(string(data), `"status":"in-progress"`) {
+		t.Fatalf("неверный JSON: %s, %v", data, err)
+	}
+
+	tests := []struct {
+		data string
+		want Status
+	}{
+		{`{"id":1,"completed":true}`, StatusDone},
+		{`{"id":1,"completed":false}`, StatusTodo},
+		{`{"id":1,"completed":true,"status":"cancelled"}`, StatusCancelled},
+		{`{"id":1,"status":"review"}`, StatusReview},
+	}
+	for _, tt := range tests {
+		var decoded Task
+		if err := json.Unmarshal([]byte(tt.data), &decoded); err != nil {
+			t.Fatalf("ошибка при анмаршалинге %s: %v", tt.data, err)
+		}
+		if decoded.Status != tt.want {
+			t.Errorf("%s: ожидался статус %s, получено %s", tt.data, tt.want, decoded.Status)
+		}
+	}
+
+	var decoded Task
+	if err := json.Unmarshal([]byte(`{"status":"paused"}`), &decoded); err == nil {
+		t.Error("ожидалась ошибка для неизвестного статуса")
+	}
+}
+
+// TestWorkflow проверяет допустимые переходы и текст ошибки.
+func TestWorkflow(t *testing.T) {
+	w := DefaultWorkflow()
+	if !w.Allows(StatusTodo, StatusInProgress) || !w.Allows(StatusReview, StatusReview) || w.Allows(StatusDone, StatusReview) {
+		t.Error("Allows вернул неверный результат")
+	}
+
+	err := w.Check(StatusTodo, StatusReview)
+	if err == nil || !strings.Contains(err.Error(), "in-progress, blocked, done, cancelled") {
+		t.Errorf("ожидалась ошибка с перечнем состояний, получено %v", err)
+	}
+
+	// Рабочий процесс читается из JSON с именами состояний в ключах
+	var custom Workflow
+	if err := json.Unmarshal([]byte(`{"todo":["done"],"done":[]}`), &custom); err != nil {
+		t.Fatalf("ошибка при чтении рабочего процесса: %v", err)
+	}
+	if !custom.Allows(StatusTodo, StatusDone) || custom.Allows(StatusTodo, StatusInProgress) {
+		t.Errorf("неверный рабочий процесс: %v", custom)
+	}
+	if err := custom.Check(StatusDone, StatusTodo); err == nil || !strings.Contains(err.Error(), "выйти нельзя") {
+		t.Errorf("ожидалась ошибка для конечного состояния, получено %v", err)
+	}
+}
+
+// TestIsReady_Blocked проверяет, что задача со статусом blocked не готова
+// к работе даже без зависимостей.
+func TestIsReady_Blocked(t *testing.T) {
+	tasks := []Task{{ID: 1, Status: StatusBlocked}, {ID: 2, Status: StatusCancelled}, {ID: 3, BlockedBy: []int{2}}}
+	if IsReady(tasks, tasks[0]) || IsReady(tasks, tasks[1]) || !IsReady(tasks, tasks[2]) {
+		t.Error("IsReady вернул неверный результат")
+	}
+}
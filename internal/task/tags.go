@@ -0,0 +1,55 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// NormalizeTag приводит тег к каноническому виду (нижний регистр, без
+// пробелов по краям и без ведущего #) и проверяет его. Тег не может быть
+// пустым, содержать пробелы и запятые или начинаться с "-" и "+":
+// эти символы используются в фильтрах.
+func NormalizeTag(s string) (string, error) {
+	tag := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), "#"))
+	switch {
+	case tag == "":
+		return "", fmt.Errorf("пустой тег")
+	case strings.ContainsAny(tag, " \t,"):
+		return "", fmt.Errorf("тег %q не может содержать пробелы и запятые", s)
+	case strings.HasPrefix(tag, "-") || strings.HasPrefix(tag, "+"):
+		return "", fmt.Errorf("тег %q не может начинаться с %q", s, tag[:1])
+	}
+	return tag, nil
+}
+
+// HasTag сообщает, отмечена ли задача тегом.
+func (t Task) HasTag(tag string) bool {
+	_, found := slices.BinarySearch(t.Tags, tag)
+	return found
+}
+
+// AddTag добавляет тег, сохраняя список отсортированным и без повторов.
+// Возвращает false, если тег уже был. Список всегда копируется, поэтому
+// изменение не затрагивает другие копии задачи.
+func (t *Task) AddTag(tag string) bool {
+	i, found := slices.BinarySearch(t.Tags, tag)
+	if found {
+		return false
+	}
+	t.Tags = slices.Insert(slices.Clone(t.Tags), i, tag)
+	return true
+}
+
+// RemoveTag удаляет тег. Возвращает false, если тега не было.
+func (t *Task) RemoveTag(tag string) bool {
+	i, found := slices.BinarySearch(t.Tags, tag)
+	if !found {
+		return false
+	}
+	t.Tags = slices.Delete(slices.Clone(t.Tags), i, i+1)
+	if len(t.Tags) == 0 {
+		t.Tags = nil
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package task
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// TestNormalizeTag проверяет приведение и проверку тегов.
+func TestNormalizeTag(t *testing.T) {
+	for in, want := range map[string]string{"Backend": "backend", " #infra ": "infra", "on-call": "on-call"} {
+		got, err := NormalizeTag(in)
+		if err != nil || got != want {
+			t.Errorf("NormalizeTag(%q) = %q, %v; ожидалось %q", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "#", "two words", "a,b", "-x", "+x"} {
+		if _, err := NormalizeTag(bad); err == nil {
+			t.Errorf("NormalizeTag(%q): ожидалась ошибка", bad)
+		}
+	}
+}
+
+// TestTaskTags проверяет, что теги ведут себя как множество
+// и что изменение копии не затрагивает исходную задачу.
+func TestTaskTags(t *testing.T) {
+	var tk Task
+	for _, tag := range []string{"infra", "backend", "infra"} {
+		tk.AddTag(tag)
+	}
+	if !slices.Equal(tk.Tags, []string{"backend", "infra"}) {
+		t.Fatalf("ожидались теги [backend infra], получено %v", tk.Tags)
+	}
+	if !tk.HasTag("infra") || tk.HasTag("oncall") {
+		t.Errorf("HasTag работает неверно: %v", tk.Tags)
+	}
+
+	copied := tk
+	copied.AddTag("oncall")
+	copied.RemoveTag("backend")
+	if !slices.Equal(tk.Tags, []string{"backend", "infra"}) {
+		t.Errorf("изменение копии затронуло исходную задачу: %v", tk.Tags)
+	}
+
+	if tk.RemoveTag("oncall") || !tk.RemoveTag("backend") || !tk.RemoveTag("infra") || tk.Tags != nil {
+		t.Errorf("RemoveTag работает неверно: %v", tk.Tags)
+	}
+
+	var decoded Task
+	if err := json.Unmarshal([]byte(`{"tags":["b","a","b"]}`), &decoded); err != nil {
+		t.Fatalf("ошибка при анмаршалинге: %v", err)
+	}
+	if !slices.Equal(decoded.Tags, []string{"a", "b"}) {
+		t.Errorf("теги из файла не приведены к множеству: %v", decoded.Tags)
+	}
+}
@@ -1,17 +1,108 @@
 package task
 
-import "time"
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
 
 // Task — основная модель задачи.
 type Task struct {
-	ID        int       `json:"id"`         // Уникальный идентификатор
-	Title     string    `json:"title"`      // Заголовок задачи
-	Completed bool      `json:"completed"`  // Статус выполнения (true = выполнено)
-	CreatedAt time.Time `json:"created_at"` // Время создания задачи
-	Important bool      `json:"important"`  // Новый параметр: важность задачи. Важная/неважная
+	ID          int        `json:"id"`                    // Уникальный идентификатор
+	Title       string     `json:"title"`                 // Заголовок задачи
+	Status      Status     `json:"status"`                // Состояние: todo, in-progress, blocked, review, done, cancelled
+	CreatedAt   time.Time  `json:"created_at"`            // Время создания задачи
+	UpdatedAt   time.Time  `json:"updated_at,omitzero"`   // Время последнего изменения (проставляет хранилище)
+	CompletedAt time.Time  `json:"completed_at,omitzero"` // Время выполнения (нулевое значение — не выполнена)
+	Priority    Priority   `json:"priority"`              // Приоритет: low, normal, high, urgent
+	DueAt       time.Time  `json:"due_at,omitzero"`       // Срок выполнения (нулевое значение — без срока)
+	Tags        []string   `json:"tags,omitempty"`        // Теги: отсортированы, без повторов
+	Project     string     `json:"project,omitempty"`     // Проект, например work.release
+	Archived    bool       `json:"archived,omitempty"`    // Проект задачи отправлен в архив
+	ParentID    int        `json:"parent_id,omitempty"`   // ID родительской задачи (0 — задача верхнего уровня)
+	BlockedBy   []int      `json:"blocked_by,omitempty"`  // ID задач, без выполнения которых эту не начать
+	Recur       Recurrence `json:"recur,omitzero"`        // правило повторения (пустое — задача разовая)
+	TimeLog     []Interval `json:"time_log,omitempty"`    // учтённое время: отрезки таймера и ручные записи
+	Notes       string     `json:"notes,omitempty"`       // заметки в свободной форме (могут быть многострочными)
+}
+
+// UnmarshalJSON читает задачу из JSON. Файлы старых версий хранили
+// важность флагом "important": true — такие задачи получают высокий приоритет,
+// а выполнение — флагом "completed": true — такие задачи получают статус done.
+func (t *Task) UnmarshalJSON(data []byte) error {
+	type plain Task // тип без методов, чтобы не уйти в рекурсию
+	aux := struct {
+		*plain
+		Important bool `json:"important"`
+		Completed bool `json:"completed"`
+	}{plain: (*plain)(t)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Important && t.Priority == PriorityNormal {
+		t.Priority = PriorityHigh
+	}
+	if aux.Completed && t.Status == StatusTodo {
+		t.Status = StatusDone
+	}
+	// Теги и зависимости из файла, отредактированного вручную,
+	// приводим к виду множества.
+	slices.Sort(t.Tags)
+	t.Tags = slices.Compact(t.Tags)
+	slices.Sort(t.BlockedBy)
+	t.BlockedBy = slices.Compact(t.BlockedBy)
+	return nil
+}
+
+// Clone возвращает копию задачи, не разделяющую с исходной срезы.
+func (t Task) Clone() Task {
+	t.Tags = slices.Clone(t.Tags)
+	t.BlockedBy = slices.Clone(t.BlockedBy)
+	t.TimeLog = slices.Clone(t.TimeLog)
+	return t
 }
 
 // MarkDone — метод, который отмечает задачу как выполненную.
 func (t *Task) MarkDone() {
-	t.Completed = true
+	t.Status = StatusDone
+}
+
+// Reopen возвращает задачу в работу: статус todo и без времени выполнения.
+func (t *Task) Reopen() {
+	t.Status = StatusTodo
+	t.CompletedAt = time.Time{}
+}
+
+// IsClosed сообщает, что задача завершена: выполнена или отменена.
+func (t Task) IsClosed() bool {
+	return t.Status.IsClosed()
+}
+
+// ClosedAt возвращает момент завершения задачи: время выполнения, а если
+// его нет (например, у отменённой задачи) — время последнего изменения
+// или создания.
+func (t Task) ClosedAt() time.Time {
+	if !t.CompletedAt.IsZero() {
+		return t.CompletedAt
+	}
+	if !t.UpdatedAt.IsZero() {
+		return t.UpdatedAt
+	}
+	return t.CreatedAt
+}
+
+// IsOverdue сообщает, просрочена ли задача на момент now: у неё есть срок,
+// она не завершена и срок уже прошёл. Срок без времени (полночь)
+// действует до конца указанного дня.
+func (t Task) IsOverdue(now time.Time) bool {
+	if t.IsClosed() || t.DueAt.IsZero() {
+		return false
+	}
+
+	deadline := t.DueAt
+	if !HasClock(deadline) {
+		deadline = deadline.AddDate(0, 0, 1)
+	}
+	return !now.Before(deadline)
 }
@@ -2,6 +2,7 @@ package task
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 )
@@ -11,15 +12,26 @@ func TestMarkDone(t *testing.T) {
 	t1 := Task{
 		ID:        1,
 		Title:     "Test",
-		Completed: false,
+		Status:    StatusTodo,
 		CreatedAt: time.Now(),
-		Important: true,
+		Priority:  PriorityHigh,
 	}
 
 	t1.MarkDone()
 
-	if !t1.Completed {
-		t.Errorf("ожидалось, что Completed=true, но получили false")
+	if t1.Status != StatusDone {
+		t.Errorf("ожидалось, что Status=done, но получили %s", t1.Status)
+	}
+}
+
+// TestReopen проверяет возврат выполненной задачи в работу.
+func TestReopen(t *testing.T) {
+	t1 := Task{ID: 1}
+	t1.Complete(time.Now())
+	t1.Reopen()
+
+	if t1.Status != StatusTodo || !t1.CompletedAt.IsZero() {
+		t.Errorf("ожидалась открытая задача без времени выполнения: %+v", t1)
 	}
 }
 
@@ -29,9 +41,9 @@ func TestTaskJSON(t *testing.T) {
 	original := Task{
 		ID:        42,
 		Title:     "JSON Test",
-		Completed: true,
+		Status:    StatusDone,
 		CreatedAt: now,
-		Important: false,
+		Priority:  PriorityUrgent,
 	}
 
 	data, err := json.Marshal(original)
@@ -46,9 +58,9 @@ func TestTaskJSON(t *testing.T) {
 
 	if decoded.ID != original.ID ||
 		decoded.Title != original.Title ||
-		decoded.Completed != original.Completed ||
+		decoded.Status != original.Status ||
 		!decoded.CreatedAt.Equal(original.CreatedAt) ||
-		decoded.Important != original.Important {
+		decoded.Priority != original.Priority {
 		t.Errorf("данные после JSON-сериализации не совпадают:\nисходный: %+v\nполученный: %+v",
 			original, decoded)
 	}
@@ -61,13 +73,80 @@ func TestTaskDefaults(t *testing.T) {
 	if t1.ID != 0 {
 		t.Errorf("ожидался ID=0, получили %d", t1.ID)
 	}
-	if t1.Completed {
-		t.Errorf("ожидалось Completed=false по умолчанию")
+	if t1.Status == StatusDone {
+		t.Errorf("ожидалось Status=todo по умолчанию")
 	}
-	if t1.Important {
-		t.Errorf("ожидалось Important=false по умолчанию")
+	if t1.Priority != PriorityNormal {
+		t.Errorf("ожидался Priority=normal по умолчанию, получили %s", t1.Priority)
 	}
 	if t1.Title != "Default test" {
 		t.Errorf("ожидался Title=Default test, получили %q", t1.Title)
 	}
 }
+
+// TestIsOverdue проверяет определение просрочки по сроку выполнения.
+func TestIsOverdue(t *testing.T) {
+	now := time.Date(2025, 3, 12, 14, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name string
+		task Task
+		want bool
+	}{
+		{"без срока, старая", Task{CreatedAt: now.AddDate(0, -1, 0)}, false},
+		{"срок вчера", Task{DueAt: time.Date(2025, 3, 11, 0, 0, 0, 0, time.Local)}, true},
+		{"срок сегодня без времени", Task{DueAt: time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)}, false},
+		{"срок сегодня, время прошло", Task{DueAt: time.Date(2025, 3, 12, 9, 0, 0, 0, time.Local)}, true},
+		{"срок завтра", Task{DueAt: time.Date(2025, 3, 13, 0, 0, 0, 0, time.Local)}, false},
+		{"выполнена после срока", Task{Status: StatusDone, DueAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.task.IsOverdue(now); got != tt.want {
+			t.Errorf("%s: IsOverdue = %v, ожидалось %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestTaskJSON_LegacyImportant проверяет чтение файлов старого формата,
+// где важность хранилась флагом important.
+func TestTaskJSON_LegacyImportant(t *testing.T) {
+	tests := []struct {
+		data string
+		want Priority
+	}{
+		{`{"id":1,"title":"a","important":true}`, PriorityHigh},
+		{`{"id":1,"title":"a","important":false}`, PriorityNormal},
+		{`{"id":1,"title":"a","important":true,"priority":"urgent"}`, PriorityUrgent},
+		{`{"id":1,"title":"a","priority":"low"}`, PriorityLow},
+	}
+	for _, tt := range tests {
+		var decoded Task
+		if err := json.Unmarshal([]byte(tt.data), &decoded); err != nil {
+			t.Fatalf("ошибка при анмаршалинге %s: %v", tt.data, err)
+		}
+		if decoded.Priority != tt.want || decoded.Title != "a" {
+			t.Errorf("%s: ожидался приоритет %s, получено %+v", tt.data, tt.want, decoded)
+		}
+	}
+
+	var decoded Task
+	if err := json.Unmarshal([]byte(`{"priority":"critical"}`), &decoded); err == nil {
+		t.Error("ожидалась ошибка для неизвестного приоритета")
+	}
+}
+
+// TestParsePriority проверяет разбор имён приоритетов и их порядок.
+func TestParsePriority(t *testing.T) {
+	for _, name := range PriorityNames() {
+		p, err := ParsePriority(strings.ToUpper(name))
+		if err != nil || p.String() != name {
+			t.Errorf("ParsePriority(%q) = %v, %v", name, p, err)
+		}
+	}
+	if !(PriorityLow < PriorityNormal && PriorityNormal < PriorityHigh && PriorityHigh < PriorityUrgent) {
+		t.Error("приоритеты должны быть упорядочены по возрастанию")
+	}
+	if _, err := ParsePriority("важно"); err == nil {
+		t.Error("ожидалась ошибка для неизвестного приоритета")
+	}
+}
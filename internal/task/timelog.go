@@ -0,0 +1,149 @@
+package task
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Interval — отрезок времени, потраченный на задачу.
+// Нулевой End означает, что таймер ещё идёт.
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end,omitzero"`
+}
+
+// Running сообщает, идёт ли ещё таймер этого отрезка.
+func (iv Interval) Running() bool {
+	return iv.End.IsZero()
+}
+
+// Duration возвращает длительность отрезка; идущий таймер считается до now.
+func (iv Interval) Duration(now time.Time) time.Duration {
+	if iv.Running() {
+		return now.Sub(iv.Start)
+	}
+	return iv.End.Sub(iv.Start)
+}
+
+// ErrTimerRunning — у задачи уже идёт таймер.
+var ErrTimerRunning = errors.New("таймер задачи уже запущен")
+
+// Running сообщает, идёт ли у задачи таймер.
+// Идущим может быть только последний отрезок.
+func (t Task) Running() bool {
+	return len(t.TimeLog) > 0 && t.TimeLog[len(t.TimeLog)-1].Running()
+}
+
+// StartTimer запускает таймер задачи с момента now.
+func (t *Task) StartTimer(now time.Time) error {
+	if t.Running() {
+		return ErrTimerRunning
+	}
+	t.TimeLog = append(slices.Clone(t.TimeLog), Interval{Start: now})
+	return nil
+}
+
+// StopTimer останавливает таймер задачи в момент now и возвращает
+// завершённый отрезок. Возвращает false, если таймер не шёл.
+func (t *Task) StopTimer(now time.Time) (Interval, bool) {
+	if !t.Running() {
+		return Interval{}, false
+	}
+	t.TimeLog = slices.Clone(t.TimeLog)
+	last := &t.TimeLog[len(t.TimeLog)-1]
+	last.End = latest(now, last.Start)
+	return *last, true
+}
+
+// LogTime записывает отрезок длительностью d, начавшийся в момент start
+// (время, учтённое вручную, без таймера). Отрезок вставляется перед идущим
+// таймером, чтобы тот оставался последним.
+func (t *Task) LogTime(start time.Time, d time.Duration) {
+	iv := Interval{Start: start, End: start.Add(d)}
+	log := slices.Clone(t.TimeLog)
+	if t.Running() {
+		t.TimeLog = slices.Insert(log, len(log)-1, iv)
+		return
+	}
+	t.TimeLog = append(log, iv)
+}
+
+// Tracked возвращает всё время, учтённое по задаче; идущий таймер
+// считается до now.
+func (t Task) Tracked(now time.Time) time.Duration {
+	var total time.Duration
+	for _, iv := range t.TimeLog {
+		total += iv.Duration(now)
+	}
+	return total
+}
+
+// RunningTimer возвращает индекс задачи с идущим таймером или -1.
+func RunningTimer(tasks []Task) int {
+	for i, t := range tasks {
+		if t.Running() {
+			return i
+		}
+	}
+	return -1
+}
+
+// TimeEntry — время, потраченное на задачу за один день.
+type TimeEntry struct {
+	TaskID   int           // ID задачи
+	Day      time.Time     // полночь дня в часовом поясе отчёта
+	Duration time.Duration // сколько времени потрачено за день
+}
+
+// TimeEntries разбивает учтённое время задач по дням в пределах [from, to)
+// в часовом поясе from. Отрезки, переходящие через полночь, делятся между
+// днями, идущий таймер считается до now. Записи упорядочены по задаче и дню.
+func TimeEntries(tasks []Task, from, to, now time.Time) []TimeEntry {
+	loc := from.Location()
+	var entries []TimeEntry
+	for _, t := range tasks {
+		byDay := make(map[time.Time]time.Duration)
+		for _, iv := range t.TimeLog {
+			start, end := latest(iv.Start, from), iv.End
+			if iv.Running() {
+				end = now
+			}
+			end = earliest(end, to)
+
+			for start.Before(end) {
+				y, m, d := start.In(loc).Date()
+				day := time.Date(y, m, d, 0, 0, 0, 0, loc)
+				next := earliest(day.AddDate(0, 0, 1), end)
+				byDay[day] += next.Sub(start)
+				start = next
+			}
+		}
+
+		days := make([]time.Time, 0, len(byDay))
+		for day := range byDay {
+			days = append(days, day)
+		}
+		slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+		for _, day := range days {
+			entries = append(entries, TimeEntry{TaskID: t.ID, Day: day, Duration: byDay[day]})
+		}
+	}
+	return entries
+}
+
+// latest — приватная функция, возвращает более поздний из моментов.
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// earliest — приватная функция, возвращает более ранний из моментов.
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
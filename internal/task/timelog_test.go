@@ -0,0 +1,78 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestTimer проверяет запуск, остановку таймера и ручной учёт времени.
+func TestTimer(t *testing.T) {
+	start := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	var task Task
+
+	if err := task.StartTimer(start); err != nil {
+		t.Fatalf("StartTimer вернул ошибку: %v", err)
+	}
+	if err := task.StartTimer(start); !errors.Is(err, ErrTimerRunning) {
+		t.Errorf("повторный запуск должен вернуть ErrTimerRunning, получено %v", err)
+	}
+
+	// Ручная запись не должна мешать идущему таймеру
+	task.LogTime(start.Add(-2*time.Hour), 30*time.Minute)
+	if !task.Running() || task.Tracked(start.Add(time.Hour)) != 90*time.Minute {
+		t.Errorf("неверный учёт при идущем таймере: %+v", task.TimeLog)
+	}
+
+	// Изменение копии не затрагивает исходную задачу
+	copied := task
+	copied.StopTimer(start.Add(time.Hour))
+	if !task.Running() {
+		t.Error("StopTimer изменил исходную задачу")
+	}
+
+	iv, ok := task.StopTimer(start.Add(45 * time.Minute))
+	if !ok || iv.Duration(time.Time{}) != 45*time.Minute || task.Running() {
+		t.Errorf("неверная остановка таймера: %+v", task.TimeLog)
+	}
+	if _, ok := task.StopTimer(start); ok {
+		t.Error("остановка без идущего таймера должна вернуть false")
+	}
+	if task.Tracked(time.Time{}) != 75*time.Minute {
+		t.Errorf("Tracked = %v, ожидалось 1h15m", task.Tracked(time.Time{}))
+	}
+
+	tasks := []Task{{ID: 1}, task, {ID: 3, TimeLog: []Interval{{Start: start}}}}
+	if got := RunningTimer(tasks); got != 2 {
+		t.Errorf("RunningTimer = %d, ожидалось 2", got)
+	}
+}
+
+// TestTimeEntries проверяет разбиение времени по дням и границы отчёта.
+func TestTimeEntries(t *testing.T) {
+	at := func(d, h int) time.Time { return time.Date(2025, 3, d, h, 0, 0, 0, time.UTC) }
+	tasks := []Task{
+		{ID: 1, TimeLog: []Interval{
+			{Start: at(10, 22), End: at(11, 2)}, // через полночь
+			{Start: at(11, 9), End: at(11, 10)},
+			{Start: at(3, 9), End: at(3, 10)}, // раньше начала отчёта
+		}},
+		{ID: 2, TimeLog: []Interval{{Start: at(16, 23)}}}, // идущий таймер обрезается концом отчёта
+		{ID: 3},
+	}
+
+	entries := TimeEntries(tasks, at(10, 0), at(17, 0), at(17, 5))
+	want := []TimeEntry{
+		{TaskID: 1, Day: at(10, 0), Duration: 2 * time.Hour},
+		{TaskID: 1, Day: at(11, 0), Duration: 3 * time.Hour},
+		{TaskID: 2, Day: at(16, 0), Duration: time.Hour},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("TimeEntries = %+v, ожидалось %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i].TaskID != want[i].TaskID || !entries[i].Day.Equal(want[i].Day) || entries[i].Duration != want[i].Duration {
+			t.Errorf("запись %d = %+v, ожидалось %+v", i, entries[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package task
+
+import "fmt"
+
+// Progress — сколько подзадач выполнено из общего числа.
+type Progress struct {
+	Done  int // выполнено
+	Total int // всего
+}
+
+// String возвращает прогресс в виде "3/5".
+func (p Progress) String() string {
+	return fmt.Sprintf("%d/%d", p.Done, p.Total)
+}
+
+// ChildProgress считает прогресс прямых подзадач для каждой задачи,
+// у которой они есть. Ключ — ID родителя.
+func ChildProgress(tasks []Task) map[int]Progress {
+	progress := make(map[int]Progress)
+	for _, t := range tasks {
+		if t.ParentID == 0 {
+			continue
+		}
+		p := progress[t.ParentID]
+		p.Total++
+		if t.IsClosed() {
+			p.Done++
+		}
+		progress[t.ParentID] = p
+	}
+	return progress
+}
+
+// Descendants возвращает ID всех подзадач задачи id на любой глубине
+// в порядке обхода в ширину. Циклы в данных не приводят к зацикливанию.
+func Descendants(tasks []Task, id int) []int {
+	children := make(map[int][]int)
+	for _, t := range tasks {
+		if t.ParentID != 0 {
+			children[t.ParentID] = append(children[t.ParentID], t.ID)
+		}
+	}
+
+	var result []int
+	seen := map[int]bool{id: true}
+	queue := []int{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range children[current] {
+			if !seen[child] {
+				seen[child] = true
+				result = append(result, child)
+				queue = append(queue, child)
+			}
+		}
+	}
+	return result
+}
+
+// TreeNode — задача в дереве подзадач с информацией для отрисовки.
+type TreeNode struct {
+	Task  Task   // сама задача
+	Depth int    // глубина вложенности (0 — корень)
+	Last  []bool // для каждого уровня от 1 до Depth: последний ли узел среди соседей
+}
+
+// Tree раскладывает задачи в дерево и возвращает его узлы в порядке
+// обхода в глубину. Порядок корней и соседей сохраняется из tasks.
+// Задача, родителя которой нет в списке (например, он отфильтрован),
+// становится корнем.
+func Tree(tasks []Task) []TreeNode {
+	present := make(map[int]bool, len(tasks))
+	for _, t := range tasks {
+		present[t.ID] = true
+	}
+
+	children := make(map[int][]Task)
+	var roots []Task
+	for _, t := range tasks {
+		if t.ParentID != 0 && t.ParentID != t.ID && present[t.ParentID] {
+			children[t.ParentID] = append(children[t.ParentID], t)
+		} else {
+			roots = append(roots, t)
+		}
+	}
+
+	nodes := make([]TreeNode, 0, len(tasks))
+	visited := make(map[int]bool, len(tasks))
+	var walk func(t Task, last []bool)
+	walk = func(t Task, last []bool) {
+		if visited[t.ID] {
+			return
+		}
+		visited[t.ID] = true
+		nodes = append(nodes, TreeNode{Task: t, Depth: len(last), Last: last})
+
+		kids := children[t.ID]
+		for i, child := range kids {
+			walk(child, append(append([]bool{}, last...), i == len(kids)-1))
+		}
+	}
+	for _, root := range roots {
+		walk(root, nil)
+	}
+
+	// Задачи, замкнутые в цикл родителей, недостижимы из корней —
+	// выводим их корнями, чтобы ничего не потерять.
+	for _, t := range tasks {
+		if !visited[t.ID] {
+			walk(t, nil)
+		}
+	}
+	return nodes
+}
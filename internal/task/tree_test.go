@@ -0,0 +1,71 @@
+package task
+
+import (
+	"fmt"
+	"testing"
+)
+
+// treeTasks возвращает задачи с иерархией:
+//
+//	1
+//	├─ 2
+//	│  └─ 4
+//	└─ 3
+//	5
+func treeTasks() []Task {
+	return []Task{
+		{ID: 1, Title: "root"},
+		{ID: 2, ParentID: 1, Status: StatusDone},
+		{ID: 3, ParentID: 1},
+		{ID: 4, ParentID: 2},
+		{ID: 5},
+	}
+}
+
+// TestChildProgressAndDescendants проверяет прогресс и обход подзадач.
+func TestChildProgressAndDescendants(t *testing.T) {
+	tasks := treeTasks()
+
+	progress := ChildProgress(tasks)
+	if progress[1].String() != "1/2" || progress[2].String() != "0/1" || len(progress) != 2 {
+		t.Errorf("неверный прогресс: %v", progress)
+	}
+	if got := fmt.Sprint(Descendants(tasks, 1)); got != "[2 3 4]" {
+		t.Errorf("Descendants(1) = %s", got)
+	}
+	if got := Descendants(tasks, 5); len(got) != 0 {
+		t.Errorf("у задачи 5 не должно быть подзадач: %v", got)
+	}
+
+	// Цикл родителей в данных не должен зацикливать обход
+	cyclic := []Task{{ID: 1, ParentID: 2}, {ID: 2, ParentID: 1}}
+	if got := fmt.Sprint(Descendants(cyclic, 1)); got != "[2]" {
+		t.Errorf("Descendants на цикле = %s", got)
+	}
+}
+
+// TestTree проверяет порядок и разметку узлов дерева.
+func TestTree(t *testing.T) {
+	nodes := Tree(treeTasks())
+
+	var got []string
+	for _, n := range nodes {
+		got = append(got, fmt.Sprintf("%d:%d:%v", n.Task.ID, n.Depth, n.Last))
+	}
+	want := "[1:0:[] 2:1:[false] 4:2:[false true] 3:1:[true] 5:0:[]]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("Tree = %v, ожидалось %s", got, want)
+	}
+
+	// Задача без родителя в списке становится корнем
+	nodes = Tree([]Task{{ID: 4, ParentID: 2}, {ID: 5}})
+	if len(nodes) != 2 || nodes[0].Depth != 0 {
+		t.Errorf("осиротевшая задача должна стать корнем: %+v", nodes)
+	}
+
+	// Задачи в цикле родителей не теряются
+	nodes = Tree([]Task{{ID: 1, ParentID: 2}, {ID: 2, ParentID: 1}})
+	if len(nodes) != 2 {
+		t.Errorf("задачи в цикле потеряны: %+v", nodes)
+	}
+}